@@ -6,6 +6,19 @@ import (
 
 // AGeneratorSpec defines the desired state of AGenerator
 type AGeneratorSpec struct {
+	// Type selects the format of the generated value:
+	// - "password": A random string built from the character options below
+	// - "bootstrap-token": A Kubernetes bootstrap token ([a-z0-9]{6}.[a-z0-9]{16}); length and character options are ignored
+	// - "key-pair": A private key stored under the ASecret key and its public key under "<key>.pub", see keyPair
+	// +optional
+	// +kubebuilder:default=password
+	// +kubebuilder:validation:Enum=password;bootstrap-token;key-pair
+	Type string `json:"type,omitempty"`
+
+	// KeyPair configures the generated key pair when type is "key-pair"
+	// +optional
+	KeyPair *KeyPairSpec `json:"keyPair,omitempty"`
+
 	// Length is the length of the generated value
 	// +optional
 	// +kubebuilder:default=16
@@ -36,6 +49,52 @@ type AGeneratorSpec struct {
 	// +optional
 	// +kubebuilder:default="!@#$%^&*()-_=+[]{}|;:,.<>?/"
 	SpecialChars string `json:"specialChars,omitempty"`
+
+	// Deterministic derives passwords from the seed secret with HKDF instead of generating them at random,
+	// so the same seed always yields the same value for the same ASecret key, e.g. across clusters.
+	// This is weaker than random generation: anyone able to read the seed can recompute every value,
+	// and rotating a value requires a new seed. Only supported with type "password"
+	// +optional
+	Deterministic bool `json:"deterministic,omitempty"`
+
+	// SeedSecretRef selects the Secret key holding the seed of a deterministic generator
+	// +optional
+	SeedSecretRef *SecretKeyReference `json:"seedSecretRef,omitempty"`
+}
+
+// SecretKeyReference selects a key of a Secret
+type SecretKeyReference struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Key of the Secret data to read
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// KeyPairSpec defines the algorithm, size and encoding of a generated key pair
+type KeyPairSpec struct {
+	// Algorithm of the key pair, "rsa" or "ecdsa"
+	// +optional
+	// +kubebuilder:default=rsa
+	// +kubebuilder:validation:Enum=rsa;ecdsa
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Bits is the key size: 2048, 3072 or 4096 for rsa (default 2048),
+	// or the curve size 256, 384 or 521 for ecdsa (default 256)
+	// +optional
+	Bits int `json:"bits,omitempty"`
+
+	// Format of the generated keys. "pem" writes a PKCS#8 private key and a PKIX public key
+	// +optional
+	// +kubebuilder:default=pem
+	// +kubebuilder:validation:Enum=pem
+	Format string `json:"format,omitempty"`
 }
 
 // AGeneratorStatus defines the observed state of AGenerator
@@ -47,6 +106,11 @@ type AGeneratorStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=agenerators,scope=Cluster
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // AGenerator is the Schema for the agenerators API
 type AGenerator struct {
@@ -5,7 +5,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// ASecretSpec defines the desired state of ASecret
+// ASecretSpec defines the desired state of ASecret.
+// The CEL rules below reject contradictory combinations even when the admission webhook is not deployed
+// +kubebuilder:validation:XValidation:rule="!(has(self.valueType) && self.valueType == 'binary') || has(self.binaryKeyMap) || !has(self.data) || size(self.data) <= 1",message="a binary secret holds at most one data key, use binaryKeyMap for several"
+// +kubebuilder:validation:XValidation:rule="!has(self.rawKey) || (has(self.valueType) && self.valueType == 'raw')",message="rawKey requires valueType raw"
+// +kubebuilder:validation:XValidation:rule="!has(self.binaryKeyMap) || (has(self.valueType) && self.valueType == 'binary')",message="binaryKeyMap requires valueType binary"
+// +kubebuilder:validation:XValidation:rule="!has(self.pathPrefix) || !has(self.valueType) || self.valueType == 'kv'",message="pathPrefix requires valueType kv"
+// +kubebuilder:validation:XValidation:rule="!has(self.binaryEncoding) || self.binaryEncoding == 'raw' || (has(self.valueType) && self.valueType == 'binary')",message="binaryEncoding requires valueType binary"
+// +kubebuilder:validation:XValidation:rule="!has(self.keyMappings) || !has(self.valueType) || self.valueType == 'kv' || self.valueType == 'json'",message="keyMappings requires valueType kv or json"
+// +kubebuilder:validation:XValidation:rule="!has(self.flattenNested) || !self.flattenNested || (has(self.valueType) && self.valueType == 'json')",message="flattenNested requires valueType json"
+// +kubebuilder:validation:XValidation:rule="!has(self.preserveStringTypes) || !self.preserveStringTypes || (has(self.valueType) && self.valueType == 'json')",message="preserveStringTypes requires valueType json"
+// +kubebuilder:validation:XValidation:rule="!has(self.renderAsSingleKey) || !has(self.valueType) || self.valueType == 'kv' || self.valueType == 'json'",message="renderAsSingleKey requires valueType kv or json"
+// +kubebuilder:validation:XValidation:rule="!has(self.renderAsSingleKey) || !has(self.targetSecretTemplate) || !has(self.targetSecretTemplate.dotenv)",message="renderAsSingleKey cannot be combined with targetSecretTemplate.dotenv"
 type ASecretSpec struct {
 	// TargetSecretName is the name of the Kubernetes Secret to be created/managed
 	TargetSecretName string `json:"targetSecretName"`
@@ -14,14 +25,63 @@ type ASecretSpec struct {
 	// +optional
 	TargetSecretTemplate *TargetSecretTemplate `json:"targetSecretTemplate,omitempty"`
 
-	// AwsSecretPath is the path in AWS SecretsManager where the secret is stored
-	AwsSecretPath string `json:"awsSecretPath"`
+	// CreateOwnerReference sets the ASecret as controller owner of the Kubernetes Secret, so deleting
+	// the ASecret deletes the Secret. When false the Secret is tracked through the
+	// yet-another-secrets.io/managed-by annotation instead. Default is true
+	// +optional
+	CreateOwnerReference *bool `json:"createOwnerReference,omitempty"`
+
+	// Provider selects the remote secret store.
+	// Allowed values: "aws" or "none". Default is "aws".
+	// - "aws": The secret is synced with AWS SecretsManager at AwsSecretPath
+	// - "none": Local-only mode, the Kubernetes Secret is built from hardcoded and generated values and AWS is never called
+	// +kubebuilder:validation:Enum=aws;none
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// AwsSecretPath is the path in AWS SecretsManager where the secret is stored.
+	// It can also be a full secret ARN including the account id, in which case the secret must already exist.
+	// Required unless Provider is "none"
+	// +optional
+	AwsSecretPath string `json:"awsSecretPath,omitempty"`
+
+	// EndpointURL sends the AWS SecretsManager requests of this ASecret to another endpoint, such as a LocalStack instance.
+	// Defaults to the operator's --aws-endpoint or AWS_ENDPOINT_URL. The URL must be listed in the operator's
+	// --allowed-endpoint-urls, other URLs fail the sync with reason InvalidSpec
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	EndpointURL string `json:"endpointURL,omitempty"`
 
 	// KmsKeyId is the AWS KMS key ID or ARN to use for encrypting the secret in AWS Secrets Manager
 	// If not specified, uses the default AWS managed key
 	// +optional
 	KmsKeyId string `json:"kmsKeyId,omitempty"`
 
+	// Description of the AWS secret shown in the AWS console. Changes are applied to the existing secret.
+	// Defaults to a description naming the owning ASecret, set when the secret is created
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ReplicaRegions lists the AWS regions the secret is replicated to, e.g. for disaster recovery.
+	// Regions added to or removed from the list are replicated or removed on the next reconcile;
+	// replicas the operator did not add are left alone. Replicas use the default AWS managed key of their region
+	// +listType=set
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	ReplicaRegions []string `json:"replicaRegions,omitempty"`
+
+	// ForceOverwriteReplica overwrites a secret of the same name that already exists in a replica region
+	// when the secret is created or replicated, e.g. when migrating secrets that were copied by hand.
+	// Without it, such a replica fails the create or the replication. Default is false
+	// +optional
+	ForceOverwriteReplica *bool `json:"forceOverwriteReplica,omitempty"`
+
+	// ResourcePolicy is a JSON resource policy attached to the AWS secret, e.g. to share it with another
+	// account. Changes are applied to the existing secret, and clearing it deletes the policy the operator attached
+	// +optional
+	ResourcePolicy string `json:"resourcePolicy,omitempty"`
+
 	// Data contains the secret data. Each key must be a valid DNS subdomain name.
 	// Values can be hardcoded or generated using a generator reference
 	// +optional
@@ -35,20 +95,131 @@ type ASecretSpec struct {
 	// +optional
 	OnlyImportRemote *bool `json:"onlyImportRemote,omitempty"`
 
+	// AllowEmptySecret allows an update that removes every key from an existing Kubernetes Secret.
+	// By default such an update is refused and reported through a WouldEmptySecret condition
+	// +optional
+	AllowEmptySecret *bool `json:"allowEmptySecret,omitempty"`
+
+	// ExternalKeys selects how keys of the Kubernetes Secret the operator did not write are handled.
+	// Preserve (default) never modifies, prunes or pushes them to AWS, even when the ASecret also defines them.
+	// Adopt takes them over as keys of the ASecret, merging them like the operator's own keys
+	// +kubebuilder:validation:Enum=Preserve;Adopt
+	// +optional
+	ExternalKeys string `json:"externalKeys,omitempty"`
+
+	// MergePolicy selects which source wins for a key set in several of them.
+	// RemoteWins (default) lets AWS override the Kubernetes Secret, spec values only fill keys missing from both.
+	// SpecWins lets value keys override AWS and the Kubernetes Secret, pushing them to AWS.
+	// KubeWins lets the Kubernetes Secret override AWS, pushing its values to AWS
+	// +kubebuilder:validation:Enum=RemoteWins;SpecWins;KubeWins
+	// +optional
+	MergePolicy string `json:"mergePolicy,omitempty"`
+
+	// PreserveUnmanagedKeys leaves keys of the Kubernetes Secret that are neither defined in data nor present in AWS
+	// in place, without pushing them to AWS or pruning them. Keys the operator wrote before are still pruned
+	// +optional
+	PreserveUnmanagedKeys *bool `json:"preserveUnmanagedKeys,omitempty"`
+
+	// VerifyWrite reads the AWS secret back after each write and fails the sync unless it holds
+	// the written value. Stale reads are retried with backoff since Secrets Manager is eventually consistent
+	// +optional
+	VerifyWrite *bool `json:"verifyWrite,omitempty"`
+
+	// RestoreOnDeletion restores the AWS secret with RestoreSecret when it is scheduled for deletion, instead of
+	// failing the sync with an AWSSecretDeleting condition until the recovery window ends
+	// +optional
+	RestoreOnDeletion *bool `json:"restoreOnDeletion,omitempty"`
+
 	// ValueType specifies how the secret should be stored in AWS SecretsManager.
-	// Allowed values: "kv", "json", or "binary". Default is "kv".
+	// Allowed values: "kv", "json", "binary" or "raw". Default is "kv".
 	// - "kv": Key-value pairs stored as JSON in SecretString
 	// - "json": Plain JSON stored in SecretString
 	// - "binary": Binary data stored in SecretBinary (useful for certificates, keys, etc.)
-	// +kubebuilder:validation:Enum=kv;json;binary
+	// - "raw": The whole SecretString, such as a PEM or a connection URL, stored under a single key
+	// +kubebuilder:validation:Enum=kv;json;binary;raw
 	// +optional
 	ValueType string `json:"valueType,omitempty"`
 
+	// BinaryEncoding of the values of a "binary" secret in the Kubernetes Secret.
+	// Allowed values: "raw" or "base64". Default is "raw".
+	// - "raw": the Secret value holds the bytes of the AWS SecretBinary
+	// - "base64": the Secret value holds the SecretBinary as base64 text, e.g. for a PEM read as text,
+	//   and is decoded again when pushed to AWS. Values set in data are then kept as base64
+	// +kubebuilder:validation:Enum=raw;base64
+	// +optional
+	BinaryEncoding string `json:"binaryEncoding,omitempty"`
+
+	// RawKey is the Kubernetes Secret key holding the SecretString of a "raw" secret. Default is "value"
+	// +optional
+	RawKey string `json:"rawKey,omitempty"`
+
+	// RenderAsSingleKey writes the whole secret to the Kubernetes Secret as a single key holding its JSON,
+	// e.g. config.json, instead of one key per property. The JSON is re-serialized like the SecretString
+	// written to AWS, with sorted properties. Keys are still merged, generated and pushed to AWS one by one,
+	// and with onlyImportRemote the key holds the AWS secret as imported. Requires valueType "kv" or "json"
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	// +optional
+	RenderAsSingleKey string `json:"renderAsSingleKey,omitempty"`
+
+	// KeyMappings renames keys of the AWS secret in the Kubernetes Secret, from the AWS key to the Secret key,
+	// e.g. db_password to DATABASE_PASSWORD. Mapped keys are renamed back when pushed to AWS, and keys of data
+	// use the Secret key. Unmapped keys keep their name. An unmapped key named like the other side of a mapping
+	// fails the sync, since both would end up under the same key. Requires valueType "kv" or "json"
+	// +optional
+	KeyMappings map[string]string `json:"keyMappings,omitempty"`
+
+	// BinaryKeyMap maps Kubernetes Secret keys to distinct AWS secret paths, each read from its SecretBinary.
+	// It is meant for binary bundles such as tls.crt and tls.key and requires valueType "binary".
+	// When set, awsSecretPath is not read and the mapped keys are only imported, never written to AWS
+	// +optional
+	BinaryKeyMap map[string]string `json:"binaryKeyMap,omitempty"`
+
+	// PathPrefix imports every AWS secret whose name starts with the prefix, e.g. app/prod/, each under a key
+	// named after the last path segment of the secret name and holding its SecretString as is. The secret path
+	// prefix of the operator applies to it as to awsSecretPath. When set, awsSecretPath is not read and the keys
+	// are only imported, never written to AWS. Requires valueType "kv"
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// Compress stores values written to AWS SecretsManager as base64 encoded gzip behind a "yaso-gzip:" marker,
+	// keeping large text secrets under the size limit. Values that would not shrink are stored as-is.
+	// Compressed values are decompressed on import whatever this setting. Binary secrets are never compressed
+	// +optional
+	Compress *bool `json:"compress,omitempty"`
+
+	// FlattenNested imports nested objects of a json AWS secret as dotted keys, e.g. config.host,
+	// instead of a single JSON-encoded key, and nests dotted keys back into objects when writing to AWS
+	// +optional
+	FlattenNested *bool `json:"flattenNested,omitempty"`
+
+	// PreserveStringTypes writes every scalar of a json AWS secret as a JSON string, so a value such as "8080"
+	// or "true" keeps its type instead of being pushed back as a number or boolean. Objects and arrays,
+	// imported as JSON-encoded keys, keep their structure. Numbers and booleans set in AWS by other tools
+	// become strings the next time the operator writes the secret
+	// +optional
+	PreserveStringTypes *bool `json:"preserveStringTypes,omitempty"`
+
+	// NormalizeLineEndings converts the line endings of values imported from AWS SecretsManager
+	// before they are written to the Kubernetes Secret and compared with it.
+	// Allowed values: "none", "lf" or "crlf". Default is "none".
+	// Binary secrets and keys declaring an encoding are never normalized
+	// +kubebuilder:validation:Enum=none;lf;crlf
+	// +optional
+	NormalizeLineEndings string `json:"normalizeLineEndings,omitempty"`
+
 	// RefreshInterval specifies how long the operator waits between each refresh/reconcile of this secret.
-	// Default is "1h"
+	// Default is "1h", or "6h" when the operator runs with --reconcile-mode=poll
 	// Example: "10m", "1h"
 	// +optional
 	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// RotationInterval specifies how often values sourced from a GeneratorRef are regenerated
+	// and pushed to both AWS and the Kubernetes Secret. Other keys are never rotated.
+	// Rotation is disabled when unset.
+	// Example: "2160h" (90 days)
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
 }
 
 // TargetSecretTemplate defines the template for the Kubernetes Secret metadata
@@ -64,9 +235,43 @@ type TargetSecretTemplate struct {
 	// Type of the Kubernetes Secret. Defaults to Opaque if not specified
 	// +optional
 	Type *corev1.SecretType `json:"type,omitempty"`
+
+	// Dotenv renders all secret keys into an additional dotenv-formatted key of the Kubernetes Secret
+	// +optional
+	Dotenv *DotenvTemplate `json:"dotenv,omitempty"`
+
+	// Immutable creates the Kubernetes Secret as immutable, so the kubelet stops watching it. Since an immutable
+	// Secret cannot be updated in place, it is deleted and recreated when its data or type changes
+	// +optional
+	Immutable *bool `json:"immutable,omitempty"`
+}
+
+// DotenvTemplate defines how secret data is rendered as a .env file
+type DotenvTemplate struct {
+	// Key is the name of the Kubernetes Secret key holding the rendered .env content
+	// +kubebuilder:default=".env"
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Quoting controls when values are wrapped in double quotes.
+	// - "if-needed": only values containing characters unsafe for shells are quoted
+	// - "always": every value is quoted
+	// +kubebuilder:validation:Enum=if-needed;always
+	// +kubebuilder:default=if-needed
+	// +optional
+	Quoting string `json:"quoting,omitempty"`
+
+	// Newlines controls how newlines inside values are written.
+	// - "escape": newlines are written as the two characters \n inside a quoted value
+	// - "preserve": newlines are kept as-is inside a quoted value (multi-line dotenv)
+	// +kubebuilder:validation:Enum=escape;preserve
+	// +kubebuilder:default=escape
+	// +optional
+	Newlines string `json:"newlines,omitempty"`
 }
 
 // DataSource defines the source of the secret data
+// +kubebuilder:validation:XValidation:rule="!has(self.onlyImportRemote) || !self.onlyImportRemote || (!has(self.value) && !has(self.generatorRef) && !has(self.generatorRefs) && !has(self.configMapRef) && !has(self.secretRef))",message="a key with onlyImportRemote cannot set value, generatorRef, generatorRefs, configMapRef or secretRef"
 type DataSource struct {
 	// Value is the hardcoded value for this key
 	// +optional
@@ -76,15 +281,115 @@ type DataSource struct {
 	// +optional
 	GeneratorRef *GeneratorReference `json:"generatorRef,omitempty"`
 
+	// GeneratorRefs builds the value from several AGenerators, concatenating their values in order,
+	// e.g. a short prefix and a long token. The key is generated and rotated like one with a GeneratorRef
+	// +kubebuilder:validation:MinItems=2
+	// +optional
+	GeneratorRefs []GeneratorSegment `json:"generatorRefs,omitempty"`
+
+	// ConfigMapRef reads the value from a key of a ConfigMap in the ASecret namespace.
+	// The value follows the ConfigMap and is written to AWS like a hardcoded value
+	// +optional
+	ConfigMapRef *ConfigMapKeyReference `json:"configMapRef,omitempty"`
+
+	// SecretRef reads the value from a key of another Secret in the ASecret namespace, e.g. an injected
+	// service account token. The value follows the Secret like a configMapRef. The target Secret cannot be read
+	// +optional
+	SecretRef *LocalSecretKeyReference `json:"secretRef,omitempty"`
+
 	// OnlyImportRemote imports value from remote provider only, do not create if missing
 	// +optional
 	OnlyImportRemote *bool `json:"onlyImportRemote,omitempty"`
+
+	// DefaultValue seeds an import-only key while it is absent in AWS. It requires onlyImportRemote,
+	// on the key or on the ASecret, and is written to AWS unless the whole ASecret is import-only.
+	// Once the key exists in AWS the remote value wins
+	// +optional
+	DefaultValue string `json:"defaultValue,omitempty"`
+
+	// Encoding of the value as stored in AWS SecretsManager.
+	// Allowed values: "none", "base64" or "base64url". Default is "none".
+	// Imported values are decoded before being written to the Kubernetes Secret and
+	// re-encoded when pushed back to AWS
+	// +kubebuilder:validation:Enum=none;base64;base64url
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// RemoteRef extracts this key from a nested property of the AWS secret JSON.
+	// Keys with a RemoteRef are import-only, like OnlyImportRemote
+	// +optional
+	RemoteRef *RemoteReference `json:"remoteRef,omitempty"`
+}
+
+// RemoteReference selects a nested value of the AWS secret
+type RemoteReference struct {
+	// Property is a dot-separated path to the value in the AWS secret JSON, e.g. ".rds.password".
+	// Array elements are selected with a numeric segment, e.g. ".replicas.0.host"
+	// +kubebuilder:validation:MinLength=1
+	Property string `json:"property"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap in the ASecret namespace
+type ConfigMapKeyReference struct {
+	// Name of the ConfigMap
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the ConfigMap data to read
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// LocalSecretKeyReference selects a key of a Secret in the ASecret namespace
+type LocalSecretKeyReference struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the Secret data to read
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // GeneratorReference contains the reference to a generator
 type GeneratorReference struct {
 	// Name of the generator
 	Name string `json:"name"`
+
+	// Length overrides the length of the generated value for this key only
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Length *int `json:"length,omitempty"`
+
+	// IncludeUppercase overrides whether uppercase letters are included for this key only
+	// +optional
+	IncludeUppercase *bool `json:"includeUppercase,omitempty"`
+
+	// IncludeLowercase overrides whether lowercase letters are included for this key only
+	// +optional
+	IncludeLowercase *bool `json:"includeLowercase,omitempty"`
+
+	// IncludeNumbers overrides whether numbers are included for this key only
+	// +optional
+	IncludeNumbers *bool `json:"includeNumbers,omitempty"`
+
+	// IncludeSpecialChars overrides whether special characters are included for this key only
+	// +optional
+	IncludeSpecialChars *bool `json:"includeSpecialChars,omitempty"`
+
+	// SpecialChars overrides the set of special characters for this key only
+	// +optional
+	SpecialChars *string `json:"specialChars,omitempty"`
+}
+
+// GeneratorSegment is one generated part of a composite value
+type GeneratorSegment struct {
+	// GeneratorReference names the AGenerator of the segment, with optional overrides of its parameters
+	GeneratorReference `json:",inline"`
+
+	// Separator is written between the value of this segment and the next one
+	// +optional
+	Separator string `json:"separator,omitempty"`
 }
 
 // ASecretStatus defines the observed state of ASecret
@@ -94,11 +399,71 @@ type ASecretStatus struct {
 
 	// LastSyncTime is the last time the secret was synced with AWS
 	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ObservedGeneration is the generation of the spec applied by the last fully successful reconcile
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastError is the error of the last failed reconcile, kept until a reconcile succeeds
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is when LastError was recorded
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+
+	// RemoteMetadata holds timestamps reported by AWS SecretsManager for the remote secret
+	// +optional
+	RemoteMetadata *RemoteSecretMetadata `json:"remoteMetadata,omitempty"`
+
+	// LastRotationTimes records, per generated key, when its value was last generated
+	// +optional
+	LastRotationTimes map[string]metav1.Time `json:"lastRotationTimes,omitempty"`
+
+	// Summary counts the key outcomes of the last sync, e.g. "5 keys synced, 1 imported, 0 failed"
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
+	// ReplicaRegions lists the regions the operator replicated the AWS secret to, so that regions
+	// dropped from spec.replicaRegions can be removed from replication
+	// +optional
+	ReplicaRegions []string `json:"replicaRegions,omitempty"`
+
+	// LastForceSync is the value of the yet-another-secrets.io/force-sync annotation handled by the last
+	// successful reconcile
+	// +optional
+	LastForceSync string `json:"lastForceSync,omitempty"`
+
+	// ResourcePolicyApplied is set once the operator attached spec.resourcePolicy to the AWS secret,
+	// so that clearing it deletes the policy without touching policies attached by someone else
+	// +optional
+	ResourcePolicyApplied bool `json:"resourcePolicyApplied,omitempty"`
+}
+
+// RemoteSecretMetadata describes the AWS secret as reported by DescribeSecret.
+// These timestamps are remote metadata, they do not describe the Kubernetes Secret
+type RemoteSecretMetadata struct {
+	// CreatedDate is when the AWS secret was created
+	// +optional
+	CreatedDate *metav1.Time `json:"createdDate,omitempty"`
+
+	// LastChangedDate is when the AWS secret was last modified
+	// +optional
+	LastChangedDate *metav1.Time `json:"lastChangedDate,omitempty"`
+
+	// LastAccessedDate is the last date the AWS secret was read. AWS only records the date, not the time
+	// +optional
+	LastAccessedDate *metav1.Time `json:"lastAccessedDate,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=asecrets,scope=Namespaced
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Generation",type=integer,JSONPath=`.metadata.generation`
+//+kubebuilder:printcolumn:name="Observed",type=integer,JSONPath=`.status.observedGeneration`
+//+kubebuilder:printcolumn:name="Summary",type=string,JSONPath=`.status.summary`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ASecret is the Schema for the asecrets API
 type ASecret struct {
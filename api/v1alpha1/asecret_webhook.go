@@ -0,0 +1,258 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var secretARNPattern = regexp.MustCompile(`^arn:[a-z-]+:secretsmanager:[a-z0-9-]+:[0-9]{12}:secret:.+$`)
+
+// SetupASecretWebhookWithManager registers the ASecret validating webhook with the manager
+func SetupASecretWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&ASecret{}).
+		WithValidator(&ASecretCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-yet-another-secrets-io-v1alpha1-asecret,mutating=false,failurePolicy=fail,sideEffects=None,groups=yet-another-secrets.io,resources=asecrets,verbs=create;update,versions=v1alpha1,name=vasecret.yet-another-secrets.io,admissionReviewVersions=v1
+
+// ASecretCustomValidator validates ASecret resources at admission time
+// +kubebuilder:object:generate=false
+type ASecretCustomValidator struct{}
+
+var _ admission.CustomValidator = &ASecretCustomValidator{}
+
+// ValidateCreate validates an ASecret on creation
+func (v *ASecretCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	aSecret, ok := obj.(*ASecret)
+	if !ok {
+		return nil, fmt.Errorf("expected an ASecret object but got %T", obj)
+	}
+	return nil, validateASecret(aSecret)
+}
+
+// ValidateUpdate validates an ASecret on update
+func (v *ASecretCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	aSecret, ok := newObj.(*ASecret)
+	if !ok {
+		return nil, fmt.Errorf("expected an ASecret object but got %T", newObj)
+	}
+	return nil, validateASecret(aSecret)
+}
+
+// ValidateDelete does not validate anything, deletion is always allowed
+func (v *ASecretCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateASecret returns an Invalid error listing every misconfiguration found in the spec
+func validateASecret(aSecret *ASecret) error {
+	allErrs := validateASecretSpec(&aSecret.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ASecret").GroupKind(), aSecret.Name, allErrs)
+}
+
+// validateASecretSpec validates the ASecret spec fields that cannot be expressed as CRD schema
+func validateASecretSpec(spec *ASecretSpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.TargetSecretName == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("targetSecretName"), "targetSecretName must not be empty"))
+	} else if msgs := validation.IsDNS1123Subdomain(spec.TargetSecretName); len(msgs) > 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("targetSecretName"), spec.TargetSecretName, strings.Join(msgs, "; ")))
+	}
+
+	if spec.Provider != "none" && spec.AwsSecretPath == "" && len(spec.BinaryKeyMap) == 0 && spec.PathPrefix == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("awsSecretPath"), "awsSecretPath is required unless provider is none, binaryKeyMap or pathPrefix is set"))
+	}
+
+	if spec.RawKey != "" && spec.ValueType != "raw" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("rawKey"), spec.RawKey, "rawKey requires valueType raw"))
+	}
+	if spec.ValueType == "raw" {
+		rawKey := spec.RawKey
+		if rawKey == "" {
+			rawKey = "value"
+		}
+		for key, dataSource := range spec.Data {
+			if key != rawKey {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child("data").Key(key), fmt.Sprintf("a raw secret only holds the key %q", rawKey)))
+			}
+			if dataSource.RemoteRef != nil {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child("data").Key(key).Child("remoteRef"), "remoteRef cannot be used with valueType raw"))
+			}
+		}
+	}
+
+	if spec.RenderAsSingleKey != "" {
+		if spec.ValueType == "binary" || spec.ValueType == "raw" {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("renderAsSingleKey"), spec.RenderAsSingleKey, "renderAsSingleKey requires valueType kv or json"))
+		}
+		if spec.TargetSecretTemplate != nil && spec.TargetSecretTemplate.Dotenv != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("renderAsSingleKey"), "renderAsSingleKey cannot be combined with targetSecretTemplate.dotenv"))
+		}
+	}
+
+	if spec.PreserveStringTypes != nil && *spec.PreserveStringTypes && spec.ValueType != "json" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("preserveStringTypes"), *spec.PreserveStringTypes, "preserveStringTypes requires valueType json"))
+	}
+
+	if len(spec.KeyMappings) > 0 && spec.ValueType != "" && spec.ValueType != "kv" && spec.ValueType != "json" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("valueType"), spec.ValueType, "keyMappings requires valueType kv or json"))
+	}
+	mappedFrom := make(map[string]string, len(spec.KeyMappings))
+	for _, awsKey := range slices.Sorted(maps.Keys(spec.KeyMappings)) {
+		kubeKey := spec.KeyMappings[awsKey]
+		keyPath := specPath.Child("keyMappings").Key(awsKey)
+		if msgs := validation.IsConfigMapKey(kubeKey); len(msgs) > 0 {
+			allErrs = append(allErrs, field.Invalid(keyPath, kubeKey, strings.Join(msgs, "; ")))
+		} else if other, exists := mappedFrom[kubeKey]; exists {
+			allErrs = append(allErrs, field.Duplicate(keyPath, kubeKey+" is already mapped from "+other))
+		}
+		mappedFrom[kubeKey] = awsKey
+	}
+
+	if spec.BinaryEncoding != "" && spec.BinaryEncoding != "raw" && spec.ValueType != "binary" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("binaryEncoding"), spec.BinaryEncoding, "binaryEncoding requires valueType binary"))
+	}
+
+	if len(spec.BinaryKeyMap) > 0 && spec.ValueType != "binary" {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("valueType"), spec.ValueType, "binaryKeyMap requires valueType binary"))
+	}
+	for key, path := range spec.BinaryKeyMap {
+		if path == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("binaryKeyMap").Key(key), "AWS secret path must not be empty"))
+		}
+	}
+
+	if spec.PathPrefix != "" {
+		if spec.ValueType != "" && spec.ValueType != "kv" {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("valueType"), spec.ValueType, "pathPrefix requires valueType kv"))
+		}
+		if spec.Provider == "none" {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("pathPrefix"), "pathPrefix cannot be used with provider none"))
+		}
+		if strings.HasPrefix(spec.PathPrefix, "arn:") {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("pathPrefix"), spec.PathPrefix, "pathPrefix must be a secret name prefix, not an ARN"))
+		}
+	}
+
+	if len(spec.ReplicaRegions) > 0 && (spec.Provider == "none" || len(spec.BinaryKeyMap) > 0 || spec.PathPrefix != "") {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("replicaRegions"), "replicaRegions require a single AWS secret, they cannot be used with provider none, binaryKeyMap or pathPrefix"))
+	}
+
+	if spec.ResourcePolicy != "" {
+		var policy map[string]interface{}
+		if err := json.Unmarshal([]byte(spec.ResourcePolicy), &policy); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("resourcePolicy"), spec.ResourcePolicy, "resourcePolicy must be a JSON object"))
+		}
+		if spec.Provider == "none" || (spec.OnlyImportRemote != nil && *spec.OnlyImportRemote) || len(spec.BinaryKeyMap) > 0 || spec.PathPrefix != "" {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("resourcePolicy"), "resourcePolicy is attached when writing the AWS secret, it cannot be used with provider none, onlyImportRemote, binaryKeyMap or pathPrefix"))
+		}
+	}
+
+	if spec.EndpointURL != "" && spec.Provider == "none" {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("endpointURL"), "endpointURL cannot be used with provider none"))
+	}
+
+	if strings.HasPrefix(spec.AwsSecretPath, "arn:") && !secretARNPattern.MatchString(spec.AwsSecretPath) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("awsSecretPath"), spec.AwsSecretPath, "must be a secretsmanager secret ARN including the account id"))
+	}
+
+	dataPath := specPath.Child("data")
+	for key, dataSource := range spec.Data {
+		keyPath := dataPath.Key(key)
+
+		if dataSource.Value != "" && dataSource.GeneratorRef != nil {
+			allErrs = append(allErrs, field.Forbidden(keyPath, "value and generatorRef are mutually exclusive"))
+		}
+
+		if len(dataSource.GeneratorRefs) > 0 {
+			allErrs = append(allErrs, validateGeneratorSegments(dataSource, keyPath.Child("generatorRefs"))...)
+		}
+
+		if dataSource.RemoteRef != nil && (dataSource.Value != "" || dataSource.GeneratorRef != nil || len(dataSource.GeneratorRefs) > 0) {
+			allErrs = append(allErrs, field.Forbidden(keyPath.Child("remoteRef"), "remoteRef cannot be combined with value, generatorRef or generatorRefs"))
+		}
+
+		if dataSource.ConfigMapRef != nil && (dataSource.Value != "" || dataSource.GeneratorRef != nil || len(dataSource.GeneratorRefs) > 0 || dataSource.RemoteRef != nil) {
+			allErrs = append(allErrs, field.Forbidden(keyPath.Child("configMapRef"), "configMapRef cannot be combined with value, generatorRef, generatorRefs or remoteRef"))
+		}
+
+		if dataSource.SecretRef != nil {
+			if dataSource.Value != "" || dataSource.GeneratorRef != nil || len(dataSource.GeneratorRefs) > 0 || dataSource.ConfigMapRef != nil || dataSource.RemoteRef != nil {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("secretRef"), "secretRef cannot be combined with value, generatorRef, generatorRefs, configMapRef or remoteRef"))
+			}
+			if dataSource.SecretRef.Name == spec.TargetSecretName {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("secretRef", "name"), "secretRef cannot read the target Secret of the ASecret"))
+			}
+		}
+
+		if dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote {
+			if dataSource.Value != "" {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("value"), "a hardcoded value cannot be set when onlyImportRemote is true"))
+			}
+			if dataSource.GeneratorRef != nil {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("generatorRef"), "a generatorRef cannot be set when onlyImportRemote is true"))
+			}
+			if len(dataSource.GeneratorRefs) > 0 {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("generatorRefs"), "generatorRefs cannot be set when onlyImportRemote is true"))
+			}
+			if dataSource.ConfigMapRef != nil {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("configMapRef"), "a configMapRef cannot be set when onlyImportRemote is true"))
+			}
+			if dataSource.SecretRef != nil {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("secretRef"), "a secretRef cannot be set when onlyImportRemote is true"))
+			}
+		}
+
+		if dataSource.DefaultValue != "" {
+			keyImportOnly := dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote
+			secretImportOnly := spec.OnlyImportRemote != nil && *spec.OnlyImportRemote
+			if !keyImportOnly && !secretImportOnly {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("defaultValue"), "defaultValue requires onlyImportRemote"))
+			}
+			if spec.ValueType == "binary" {
+				allErrs = append(allErrs, field.Forbidden(keyPath.Child("defaultValue"), "defaultValue is not supported for binary secrets"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateGeneratorSegments checks the segments of a composite generated value
+func validateGeneratorSegments(dataSource DataSource, segmentsPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if dataSource.Value != "" || dataSource.GeneratorRef != nil {
+		allErrs = append(allErrs, field.Forbidden(segmentsPath, "generatorRefs cannot be combined with value or generatorRef"))
+	}
+	if len(dataSource.GeneratorRefs) < 2 {
+		allErrs = append(allErrs, field.Invalid(segmentsPath, len(dataSource.GeneratorRefs), "generatorRefs needs at least two segments, use generatorRef for a single generator"))
+	}
+	for i, segment := range dataSource.GeneratorRefs {
+		segmentPath := segmentsPath.Index(i)
+		if segment.Name == "" {
+			allErrs = append(allErrs, field.Required(segmentPath.Child("name"), "each segment names an AGenerator"))
+		}
+		if segment.Separator != "" && i == len(dataSource.GeneratorRefs)-1 {
+			allErrs = append(allErrs, field.Forbidden(segmentPath.Child("separator"), "the last segment has no next segment to separate"))
+		}
+	}
+	return allErrs
+}
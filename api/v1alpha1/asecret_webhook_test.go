@@ -0,0 +1,740 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newWebhookTestASecret(spec ASecretSpec) *ASecret {
+	return &ASecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-asecret",
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+func TestASecretCustomValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        ASecretSpec
+		expectError bool
+		errContains []string
+	}{
+		{
+			name: "valid spec",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"username": {Value: "admin"},
+					"password": {GeneratorRef: &GeneratorReference{Name: "password-generator"}},
+					"apiKey":   {OnlyImportRemote: boolPtr(true)},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "empty targetSecretName",
+			spec: ASecretSpec{
+				AwsSecretPath: "/my-app/secrets",
+			},
+			expectError: true,
+			errContains: []string{"spec.targetSecretName", "must not be empty"},
+		},
+		{
+			name: "uppercase targetSecretName",
+			spec: ASecretSpec{
+				TargetSecretName: "My-Secret",
+				AwsSecretPath:    "/my-app/secrets",
+			},
+			expectError: true,
+			errContains: []string{"spec.targetSecretName", "lowercase RFC 1123 subdomain"},
+		},
+		{
+			name: "missing awsSecretPath",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+			},
+			expectError: true,
+			errContains: []string{"spec.awsSecretPath", "required unless provider is none"},
+		},
+		{
+			name: "local-only without awsSecretPath",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				Provider:         "none",
+				Data: map[string]DataSource{
+					"username": {Value: "admin"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "binaryKeyMap without awsSecretPath",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				ValueType:        "binary",
+				BinaryKeyMap: map[string]string{
+					"tls.crt": "/my-app/tls/crt",
+					"tls.key": "/my-app/tls/key",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "binaryKeyMap without binary valueType",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				BinaryKeyMap:     map[string]string{"tls.crt": ""},
+			},
+			expectError: true,
+			errContains: []string{"spec.valueType", "requires valueType binary", "spec.binaryKeyMap[tls.crt]"},
+		},
+		{
+			name: "replicaRegions",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				ReplicaRegions:   []string{"eu-central-1"},
+			},
+			expectError: false,
+		},
+		{
+			name: "replicaRegions with binaryKeyMap",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				ValueType:        "binary",
+				BinaryKeyMap:     map[string]string{"tls.crt": "/my-app/tls/crt"},
+				ReplicaRegions:   []string{"eu-central-1"},
+			},
+			expectError: true,
+			errContains: []string{"spec.replicaRegions", "binaryKeyMap"},
+		},
+		{
+			name: "resourcePolicy",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				ResourcePolicy:   `{"Version":"2012-10-17","Statement":[]}`,
+			},
+			expectError: false,
+		},
+		{
+			name: "resourcePolicy not a JSON object",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				ResourcePolicy:   `{"Version":`,
+			},
+			expectError: true,
+			errContains: []string{"spec.resourcePolicy", "must be a JSON object"},
+		},
+		{
+			name: "resourcePolicy with onlyImportRemote",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				OnlyImportRemote: boolPtr(true),
+				ResourcePolicy:   `{"Version":"2012-10-17","Statement":[]}`,
+			},
+			expectError: true,
+			errContains: []string{"spec.resourcePolicy", "onlyImportRemote"},
+		},
+		{
+			name: "endpointURL",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				EndpointURL:      "http://localstack:4566",
+			},
+			expectError: false,
+		},
+		{
+			name: "endpointURL with provider none",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				Provider:         "none",
+				EndpointURL:      "http://localstack:4566",
+			},
+			expectError: true,
+			errContains: []string{"spec.endpointURL", "provider none"},
+		},
+		{
+			name: "valid secret ARN",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-secret-AbCdEf",
+			},
+			expectError: false,
+		},
+		{
+			name: "secret ARN without account id",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "arn:aws:secretsmanager:eu-west-1::secret:my-secret-AbCdEf",
+			},
+			expectError: true,
+			errContains: []string{"spec.awsSecretPath", "account id"},
+		},
+		{
+			name: "value and generatorRef both set",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"password": {
+						Value:        "hardcoded",
+						GeneratorRef: &GeneratorReference{Name: "password-generator"},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[password]", "mutually exclusive"},
+		},
+		{
+			name: "onlyImportRemote with hardcoded value",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {
+						Value:            "hardcoded",
+						OnlyImportRemote: boolPtr(true),
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].value", "onlyImportRemote"},
+		},
+		{
+			name: "onlyImportRemote with generatorRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {
+						GeneratorRef:     &GeneratorReference{Name: "password-generator"},
+						OnlyImportRemote: boolPtr(true),
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRef", "onlyImportRemote"},
+		},
+		{
+			name: "generatorRefs with two segments",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {GeneratorRefs: []GeneratorSegment{
+						{GeneratorReference: GeneratorReference{Name: "prefix-generator"}, Separator: "_"},
+						{GeneratorReference: GeneratorReference{Name: "token-generator"}},
+					}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "generatorRefs with a single segment",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {GeneratorRefs: []GeneratorSegment{
+						{GeneratorReference: GeneratorReference{Name: "token-generator"}},
+					}},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRefs", "at least two segments"},
+		},
+		{
+			name: "generatorRefs with generatorRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {
+						GeneratorRef: &GeneratorReference{Name: "password-generator"},
+						GeneratorRefs: []GeneratorSegment{
+							{GeneratorReference: GeneratorReference{Name: "prefix-generator"}},
+							{GeneratorReference: GeneratorReference{Name: "token-generator"}},
+						},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRefs", "cannot be combined with value or generatorRef"},
+		},
+		{
+			name: "generatorRefs segment without a name",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {GeneratorRefs: []GeneratorSegment{
+						{GeneratorReference: GeneratorReference{Name: "prefix-generator"}},
+						{Separator: "-"},
+						{GeneratorReference: GeneratorReference{Name: "token-generator"}},
+					}},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRefs[1].name"},
+		},
+		{
+			name: "generatorRefs with a separator on the last segment",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {GeneratorRefs: []GeneratorSegment{
+						{GeneratorReference: GeneratorReference{Name: "prefix-generator"}},
+						{GeneratorReference: GeneratorReference{Name: "token-generator"}, Separator: "_"},
+					}},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRefs[1].separator"},
+		},
+		{
+			name: "onlyImportRemote with generatorRefs",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {
+						GeneratorRefs: []GeneratorSegment{
+							{GeneratorReference: GeneratorReference{Name: "prefix-generator"}},
+							{GeneratorReference: GeneratorReference{Name: "token-generator"}},
+						},
+						OnlyImportRemote: boolPtr(true),
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].generatorRefs", "onlyImportRemote"},
+		},
+		{
+			name: "remoteRef with hardcoded value",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"DB_PASSWORD": {
+						Value:     "hardcoded",
+						RemoteRef: &RemoteReference{Property: ".rds.password"},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[DB_PASSWORD].remoteRef"},
+		},
+		{
+			name: "configMapRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"DB_HOST": {
+						ConfigMapRef: &ConfigMapKeyReference{Name: "db-config", Key: "host"},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "configMapRef with generatorRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"DB_HOST": {
+						ConfigMapRef: &ConfigMapKeyReference{Name: "db-config", Key: "host"},
+						GeneratorRef: &GeneratorReference{Name: "password-generator"},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[DB_HOST].configMapRef", "cannot be combined"},
+		},
+		{
+			name: "onlyImportRemote with configMapRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"DB_HOST": {
+						ConfigMapRef:     &ConfigMapKeyReference{Name: "db-config", Key: "host"},
+						OnlyImportRemote: boolPtr(true),
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[DB_HOST].configMapRef", "onlyImportRemote"},
+		},
+		{
+			name: "secretRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"TOKEN": {
+						SecretRef: &LocalSecretKeyReference{Name: "app-token", Key: "token"},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "secretRef with configMapRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"TOKEN": {
+						SecretRef:    &LocalSecretKeyReference{Name: "app-token", Key: "token"},
+						ConfigMapRef: &ConfigMapKeyReference{Name: "app-config", Key: "token"},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[TOKEN].secretRef", "cannot be combined"},
+		},
+		{
+			name: "secretRef to the target Secret",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"TOKEN": {
+						SecretRef: &LocalSecretKeyReference{Name: "my-secret", Key: "TOKEN"},
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[TOKEN].secretRef.name", "target Secret"},
+		},
+		{
+			name: "onlyImportRemote with secretRef",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"TOKEN": {
+						SecretRef:        &LocalSecretKeyReference{Name: "app-token", Key: "token"},
+						OnlyImportRemote: boolPtr(true),
+					},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[TOKEN].secretRef", "onlyImportRemote"},
+		},
+		{
+			name: "raw secret with its key",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/url",
+				ValueType:        "raw",
+				RawKey:           "url",
+				Data:             map[string]DataSource{"url": {Value: "postgres://db:5432"}},
+			},
+			expectError: false,
+		},
+		{
+			name: "raw secret with another key",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/url",
+				ValueType:        "raw",
+				Data:             map[string]DataSource{"url": {Value: "postgres://db:5432"}},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[url]", `only holds the key "value"`},
+		},
+		{
+			name: "rawKey without raw valueType",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/url",
+				RawKey:           "url",
+			},
+			expectError: true,
+			errContains: []string{"spec.rawKey", "requires valueType raw"},
+		},
+		{
+			name: "defaultValue on onlyImportRemote key",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {DefaultValue: "changeme", OnlyImportRemote: boolPtr(true)},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "defaultValue on onlyImportRemote ASecret",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				OnlyImportRemote: boolPtr(true),
+				Data: map[string]DataSource{
+					"apiKey": {DefaultValue: "changeme"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "defaultValue without onlyImportRemote",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {DefaultValue: "changeme"},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[apiKey].defaultValue", "requires onlyImportRemote"},
+		},
+		{
+			name: "defaultValue on binary secret",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/cert",
+				ValueType:        "binary",
+				Data: map[string]DataSource{
+					"cert": {DefaultValue: "changeme", OnlyImportRemote: boolPtr(true)},
+				},
+			},
+			expectError: true,
+			errContains: []string{"spec.data[cert].defaultValue", "binary"},
+		},
+		{
+			name: "keyMappings with valueType kv",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				KeyMappings:      map[string]string{"db_password": "DATABASE_PASSWORD"},
+			},
+			expectError: false,
+		},
+		{
+			name: "keyMappings with valueType raw",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				ValueType:        "raw",
+				KeyMappings:      map[string]string{"db_password": "DATABASE_PASSWORD"},
+			},
+			expectError: true,
+			errContains: []string{"spec.valueType", "keyMappings requires valueType kv or json"},
+		},
+		{
+			name: "keyMappings with two keys mapped to the same Secret key",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				KeyMappings:      map[string]string{"db_password": "PASSWORD", "password": "PASSWORD"},
+			},
+			expectError: true,
+			errContains: []string{"spec.keyMappings[password]", "already mapped from db_password"},
+		},
+		{
+			name: "keyMappings to an invalid Secret key",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				KeyMappings:      map[string]string{"db_password": "database password"},
+			},
+			expectError: true,
+			errContains: []string{"spec.keyMappings[db_password]"},
+		},
+		{
+			name: "pathPrefix without awsSecretPath",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				PathPrefix:       "app/prod/",
+			},
+			expectError: false,
+		},
+		{
+			name: "pathPrefix with valueType json",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				PathPrefix:       "app/prod/",
+				ValueType:        "json",
+			},
+			expectError: true,
+			errContains: []string{"spec.valueType", "pathPrefix requires valueType kv"},
+		},
+		{
+			name: "pathPrefix as an ARN",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				PathPrefix:       "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/",
+			},
+			expectError: true,
+			errContains: []string{"spec.pathPrefix", "not an ARN"},
+		},
+		{
+			name: "pathPrefix with replicaRegions",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				PathPrefix:       "app/prod/",
+				ReplicaRegions:   []string{"eu-central-1"},
+			},
+			expectError: true,
+			errContains: []string{"spec.replicaRegions"},
+		},
+		{
+			name: "binaryEncoding base64 without valueType binary",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				BinaryEncoding:   "base64",
+			},
+			expectError: true,
+			errContains: []string{"spec.binaryEncoding", "requires valueType binary"},
+		},
+		{
+			name: "binaryEncoding base64 with valueType binary",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/cert",
+				ValueType:        "binary",
+				BinaryEncoding:   "base64",
+			},
+			expectError: false,
+		},
+		{
+			name: "preserveStringTypes without valueType json",
+			spec: ASecretSpec{
+				TargetSecretName:    "my-secret",
+				AwsSecretPath:       "/my-app/secrets",
+				PreserveStringTypes: boolPtr(true),
+			},
+			expectError: true,
+			errContains: []string{"spec.preserveStringTypes", "requires valueType json"},
+		},
+		{
+			name: "renderAsSingleKey with valueType json",
+			spec: ASecretSpec{
+				TargetSecretName:  "my-secret",
+				AwsSecretPath:     "/my-app/secrets",
+				ValueType:         "json",
+				RenderAsSingleKey: "config.json",
+			},
+			expectError: false,
+		},
+		{
+			name: "renderAsSingleKey with valueType raw",
+			spec: ASecretSpec{
+				TargetSecretName:  "my-secret",
+				AwsSecretPath:     "/my-app/secrets",
+				ValueType:         "raw",
+				RenderAsSingleKey: "config.json",
+			},
+			expectError: true,
+			errContains: []string{"spec.renderAsSingleKey", "requires valueType kv or json"},
+		},
+		{
+			name: "renderAsSingleKey with dotenv",
+			spec: ASecretSpec{
+				TargetSecretName:     "my-secret",
+				AwsSecretPath:        "/my-app/secrets",
+				RenderAsSingleKey:    "config.json",
+				TargetSecretTemplate: &TargetSecretTemplate{Dotenv: &DotenvTemplate{}},
+			},
+			expectError: true,
+			errContains: []string{"spec.renderAsSingleKey", "cannot be combined with targetSecretTemplate.dotenv"},
+		},
+		{
+			name: "onlyImportRemote false with hardcoded value",
+			spec: ASecretSpec{
+				TargetSecretName: "my-secret",
+				AwsSecretPath:    "/my-app/secrets",
+				Data: map[string]DataSource{
+					"apiKey": {
+						Value:            "hardcoded",
+						OnlyImportRemote: boolPtr(false),
+					},
+				},
+			},
+			expectError: false,
+		},
+	}
+
+	validator := &ASecretCustomValidator{}
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newWebhookTestASecret(tt.spec)
+
+			_, createErr := validator.ValidateCreate(ctx, aSecret)
+			_, updateErr := validator.ValidateUpdate(ctx, newWebhookTestASecret(ASecretSpec{}), aSecret)
+
+			for _, err := range []error{createErr, updateErr} {
+				if tt.expectError {
+					require.Error(t, err)
+					assert.True(t, apierrors.IsInvalid(err))
+					for _, s := range tt.errContains {
+						assert.Contains(t, err.Error(), s)
+					}
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+}
+
+func TestASecretCustomValidatorDoesNotLeakValues(t *testing.T) {
+	validator := &ASecretCustomValidator{}
+	aSecret := newWebhookTestASecret(ASecretSpec{
+		TargetSecretName: "my-secret",
+		AwsSecretPath:    "/my-app/secrets",
+		Data: map[string]DataSource{
+			"apiKey": {
+				Value:            "super-secret-value",
+				OnlyImportRemote: boolPtr(true),
+			},
+		},
+	})
+
+	_, err := validator.ValidateCreate(context.Background(), aSecret)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "super-secret-value")
+}
+
+func TestASecretCustomValidatorWrongType(t *testing.T) {
+	validator := &ASecretCustomValidator{}
+
+	_, err := validator.ValidateCreate(context.Background(), &corev1.Secret{})
+	assert.Error(t, err)
+
+	_, err = validator.ValidateUpdate(context.Background(), &corev1.Secret{}, &corev1.Secret{})
+	assert.Error(t, err)
+}
+
+func TestASecretCustomValidatorDelete(t *testing.T) {
+	validator := &ASecretCustomValidator{}
+
+	warnings, err := validator.ValidateDelete(context.Background(), newWebhookTestASecret(ASecretSpec{}))
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
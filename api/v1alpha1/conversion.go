@@ -0,0 +1,27 @@
+package v1alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// v1alpha1 is the hub every other API version converts through. Future versions implement
+// conversion.Convertible against these types, and the controllers keep reading the hub
+var (
+	_ conversion.Hub = &AGenerator{}
+	_ conversion.Hub = &ASecret{}
+)
+
+// Hub marks AGenerator v1alpha1 as the conversion hub
+func (*AGenerator) Hub() {}
+
+// Hub marks ASecret v1alpha1 as the conversion hub
+func (*ASecret) Hub() {}
+
+// SetupAGeneratorWebhookWithManager registers the AGenerator webhooks with the manager. The /convert
+// endpoint is only served once a second API version implementing conversion.Convertible is registered
+func SetupAGeneratorWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&AGenerator{}).
+		Complete()
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+func TestConversionHubIsConsistent(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, AddToScheme(s))
+
+	// A single version is not convertible yet, but must not be reported as a partial implementation
+	for _, obj := range []runtime.Object{&AGenerator{}, &ASecret{}} {
+		convertible, err := conversion.IsConvertible(s, obj)
+		require.NoError(t, err)
+		assert.False(t, convertible)
+	}
+}
+
+// TestAGeneratorSpecRoundTrip only checks that AGenerator specs survive the unstructured encoding of
+// conversion requests. No spoke version exists yet, so a real hub to spoke and back round trip through
+// ConvertTo and ConvertFrom stays untested until a v1beta1 is added
+func TestAGeneratorSpecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		spec AGeneratorSpec
+	}{
+		{
+			name: "password",
+			spec: AGeneratorSpec{
+				Type:                "password",
+				Length:              24,
+				IncludeUppercase:    true,
+				IncludeLowercase:    true,
+				IncludeNumbers:      true,
+				IncludeSpecialChars: true,
+				SpecialChars:        "!@#",
+			},
+		},
+		{
+			name: "bootstrap token",
+			spec: AGeneratorSpec{Type: "bootstrap-token"},
+		},
+		{
+			name: "key pair",
+			spec: AGeneratorSpec{
+				Type:    "key-pair",
+				KeyPair: &KeyPairSpec{Algorithm: "ecdsa", Bits: 384, Format: "pem"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &AGenerator{
+				TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "AGenerator"},
+				ObjectMeta: metav1.ObjectMeta{Name: "generator"},
+				Spec:       tt.spec,
+			}
+
+			// Conversion requests carry objects as unstructured content
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+			require.NoError(t, err)
+
+			var roundTripped AGenerator
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(content, &roundTripped))
+			assert.Equal(t, original, &roundTripped)
+		})
+	}
+}
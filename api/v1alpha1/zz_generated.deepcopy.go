@@ -23,7 +23,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -31,7 +31,7 @@ func (in *AGenerator) DeepCopyInto(out *AGenerator) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -88,6 +88,16 @@ func (in *AGeneratorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AGeneratorSpec) DeepCopyInto(out *AGeneratorSpec) {
 	*out = *in
+	if in.KeyPair != nil {
+		in, out := &in.KeyPair, &out.KeyPair
+		*out = new(KeyPairSpec)
+		**out = **in
+	}
+	if in.SeedSecretRef != nil {
+		in, out := &in.SeedSecretRef, &out.SeedSecretRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AGeneratorSpec.
@@ -189,6 +199,21 @@ func (in *ASecretSpec) DeepCopyInto(out *ASecretSpec) {
 		*out = new(TargetSecretTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CreateOwnerReference != nil {
+		in, out := &in.CreateOwnerReference, &out.CreateOwnerReference
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplicaRegions != nil {
+		in, out := &in.ReplicaRegions, &out.ReplicaRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForceOverwriteReplica != nil {
+		in, out := &in.ForceOverwriteReplica, &out.ForceOverwriteReplica
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
 		*out = make(map[string]DataSource, len(*in))
@@ -208,11 +233,65 @@ func (in *ASecretSpec) DeepCopyInto(out *ASecretSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AllowEmptySecret != nil {
+		in, out := &in.AllowEmptySecret, &out.AllowEmptySecret
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreserveUnmanagedKeys != nil {
+		in, out := &in.PreserveUnmanagedKeys, &out.PreserveUnmanagedKeys
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyWrite != nil {
+		in, out := &in.VerifyWrite, &out.VerifyWrite
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RestoreOnDeletion != nil {
+		in, out := &in.RestoreOnDeletion, &out.RestoreOnDeletion
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeyMappings != nil {
+		in, out := &in.KeyMappings, &out.KeyMappings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BinaryKeyMap != nil {
+		in, out := &in.BinaryKeyMap, &out.BinaryKeyMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Compress != nil {
+		in, out := &in.Compress, &out.Compress
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FlattenNested != nil {
+		in, out := &in.FlattenNested, &out.FlattenNested
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreserveStringTypes != nil {
+		in, out := &in.PreserveStringTypes, &out.PreserveStringTypes
+		*out = new(bool)
+		**out = **in
+	}
 	if in.RefreshInterval != nil {
 		in, out := &in.RefreshInterval, &out.RefreshInterval
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ASecretSpec.
@@ -236,6 +315,27 @@ func (in *ASecretStatus) DeepCopyInto(out *ASecretStatus) {
 		}
 	}
 	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RemoteMetadata != nil {
+		in, out := &in.RemoteMetadata, &out.RemoteMetadata
+		*out = new(RemoteSecretMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastRotationTimes != nil {
+		in, out := &in.LastRotationTimes, &out.LastRotationTimes
+		*out = make(map[string]v1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ReplicaRegions != nil {
+		in, out := &in.ReplicaRegions, &out.ReplicaRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ASecretStatus.
@@ -248,12 +348,44 @@ func (in *ASecretStatus) DeepCopy() *ASecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataSource) DeepCopyInto(out *DataSource) {
 	*out = *in
 	if in.GeneratorRef != nil {
 		in, out := &in.GeneratorRef, &out.GeneratorRef
 		*out = new(GeneratorReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GeneratorRefs != nil {
+		in, out := &in.GeneratorRefs, &out.GeneratorRefs
+		*out = make([]GeneratorSegment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalSecretKeyReference)
 		**out = **in
 	}
 	if in.OnlyImportRemote != nil {
@@ -261,6 +393,11 @@ func (in *DataSource) DeepCopyInto(out *DataSource) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RemoteRef != nil {
+		in, out := &in.RemoteRef, &out.RemoteRef
+		*out = new(RemoteReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
@@ -273,9 +410,54 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DotenvTemplate) DeepCopyInto(out *DotenvTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DotenvTemplate.
+func (in *DotenvTemplate) DeepCopy() *DotenvTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DotenvTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneratorReference) DeepCopyInto(out *GeneratorReference) {
 	*out = *in
+	if in.Length != nil {
+		in, out := &in.Length, &out.Length
+		*out = new(int)
+		**out = **in
+	}
+	if in.IncludeUppercase != nil {
+		in, out := &in.IncludeUppercase, &out.IncludeUppercase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IncludeLowercase != nil {
+		in, out := &in.IncludeLowercase, &out.IncludeLowercase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IncludeNumbers != nil {
+		in, out := &in.IncludeNumbers, &out.IncludeNumbers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IncludeSpecialChars != nil {
+		in, out := &in.IncludeSpecialChars, &out.IncludeSpecialChars
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SpecialChars != nil {
+		in, out := &in.SpecialChars, &out.SpecialChars
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorReference.
@@ -288,6 +470,109 @@ func (in *GeneratorReference) DeepCopy() *GeneratorReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorSegment) DeepCopyInto(out *GeneratorSegment) {
+	*out = *in
+	in.GeneratorReference.DeepCopyInto(&out.GeneratorReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorSegment.
+func (in *GeneratorSegment) DeepCopy() *GeneratorSegment {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorSegment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyPairSpec) DeepCopyInto(out *KeyPairSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPairSpec.
+func (in *KeyPairSpec) DeepCopy() *KeyPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSecretKeyReference) DeepCopyInto(out *LocalSecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretKeyReference.
+func (in *LocalSecretKeyReference) DeepCopy() *LocalSecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteReference) DeepCopyInto(out *RemoteReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteReference.
+func (in *RemoteReference) DeepCopy() *RemoteReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretMetadata) DeepCopyInto(out *RemoteSecretMetadata) {
+	*out = *in
+	if in.CreatedDate != nil {
+		in, out := &in.CreatedDate, &out.CreatedDate
+		*out = (*in).DeepCopy()
+	}
+	if in.LastChangedDate != nil {
+		in, out := &in.LastChangedDate, &out.LastChangedDate
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAccessedDate != nil {
+		in, out := &in.LastAccessedDate, &out.LastAccessedDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretMetadata.
+func (in *RemoteSecretMetadata) DeepCopy() *RemoteSecretMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSecretTemplate) DeepCopyInto(out *TargetSecretTemplate) {
 	*out = *in
@@ -310,6 +595,16 @@ func (in *TargetSecretTemplate) DeepCopyInto(out *TargetSecretTemplate) {
 		*out = new(corev1.SecretType)
 		**out = **in
 	}
+	if in.Dotenv != nil {
+		in, out := &in.Dotenv, &out.Dotenv
+		*out = new(DotenvTemplate)
+		**out = **in
+	}
+	if in.Immutable != nil {
+		in, out := &in.Immutable, &out.Immutable
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSecretTemplate.
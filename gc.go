@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// Exit codes of --gc-orphans
+const (
+	// gcExitDone means the orphans were reported, or deleted with --gc-delete
+	gcExitDone = 0
+	// gcExitFailed means the ASecrets or AWS secrets could not be listed, or an orphan could not be deleted
+	gcExitFailed = 1
+)
+
+// managedByTag is the tag key the operator marks its AWS secrets with
+const managedByTag = "managed-by"
+
+// orphanAPI is the part of the SecretsManager API the orphan cleanup calls
+type orphanAPI interface {
+	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+// liveSecretPaths returns the AWS secret names and ARNs referenced by the ASecrets, with the secret path
+// prefix applied. ASecrets being deleted still count, their finalizer may yet need the AWS secret
+func liveSecretPaths(aSecrets []secretsv1alpha1.ASecret, awsClient *awsclient.AwsClient) map[string]bool {
+	live := make(map[string]bool)
+	add := func(secretPath string) {
+		if secretPath == "" {
+			return
+		}
+		resolved := awsClient.ResolveSecretPath(secretPath)
+		live[resolved] = true
+		// A partial ARN has no random suffix, so match its name as well
+		if parsed, err := awsclient.ParseSecretARN(resolved); err == nil {
+			live[parsed.Name] = true
+		}
+	}
+
+	for i := range aSecrets {
+		add(aSecrets[i].Spec.AwsSecretPath)
+		for _, secretPath := range aSecrets[i].Spec.BinaryKeyMap {
+			add(secretPath)
+		}
+	}
+	return live
+}
+
+// livePathPrefixes returns the pathPrefix of every ASecret importing by prefix, with the secret path prefix applied
+func livePathPrefixes(aSecrets []secretsv1alpha1.ASecret, awsClient *awsclient.AwsClient) []string {
+	var prefixes []string
+	for i := range aSecrets {
+		if aSecrets[i].Spec.PathPrefix != "" {
+			prefixes = append(prefixes, awsClient.ResolveSecretPath(aSecrets[i].Spec.PathPrefix))
+		}
+	}
+	return prefixes
+}
+
+// listManagedSecrets lists every AWS secret tagged with the operator's managed-by tag, following pagination.
+// The tag-key and tag-value filters match independently, so the tag pair is checked again on each secret
+func listManagedSecrets(ctx context.Context, api orphanAPI, managedBy string) ([]smtypes.SecretListEntry, error) {
+	input := &secretsmanager.ListSecretsInput{
+		Filters: []smtypes.Filter{
+			{Key: smtypes.FilterNameStringTypeTagKey, Values: []string{managedByTag}},
+			{Key: smtypes.FilterNameStringTypeTagValue, Values: []string{managedBy}},
+		},
+	}
+
+	var managed []smtypes.SecretListEntry
+	for {
+		output, err := api.ListSecrets(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list AWS secrets: %w", err)
+		}
+		for _, entry := range output.SecretList {
+			if hasTag(entry.Tags, managedByTag, managedBy) {
+				managed = append(managed, entry)
+			}
+		}
+		if aws.ToString(output.NextToken) == "" {
+			return managed, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// hasTag reports whether the tags contain key with the given value
+func hasTag(tags []smtypes.Tag, key, value string) bool {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key && aws.ToString(tag.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// findOrphans returns the managed secrets that no live ASecret references by name, ARN or pathPrefix. With a
+// secret path prefix, secrets outside of it are left alone, as they belong to another operator sharing the account
+func findOrphans(managed []smtypes.SecretListEntry, live map[string]bool, livePrefixes []string, prefix string) []smtypes.SecretListEntry {
+	var orphans []smtypes.SecretListEntry
+	for _, entry := range managed {
+		name := aws.ToString(entry.Name)
+		if prefix != "" && !strings.HasPrefix(name, strings.TrimRight(prefix, "/")+"/") {
+			continue
+		}
+		if live[name] || live[aws.ToString(entry.ARN)] || slices.ContainsFunc(livePrefixes, func(p string) bool { return strings.HasPrefix(name, p) }) {
+			continue
+		}
+		orphans = append(orphans, entry)
+	}
+	return orphans
+}
+
+// gcOrphans reports the AWS secrets tagged as managed by the operator that no ASecret references anymore, and
+// deletes them when deleteOrphans is set. Deleted secrets get the default recovery window, so RestoreSecret
+// can bring one back. It returns the names of the orphans
+func gcOrphans(ctx context.Context, k8sReader client.Reader, api orphanAPI, awsClient *awsclient.AwsClient, deleteOrphans bool, log logr.Logger) ([]string, error) {
+	managedBy := awsClient.Config.Tags[managedByTag]
+	if managedBy == "" {
+		return nil, fmt.Errorf("no %s tag is configured, AWS secrets of the operator cannot be told apart", managedByTag)
+	}
+
+	// Every namespace is listed, an ASecret outside the watched namespaces still owns its AWS secret
+	var aSecrets secretsv1alpha1.ASecretList
+	if err := k8sReader.List(ctx, &aSecrets); err != nil {
+		return nil, fmt.Errorf("failed to list ASecrets: %w", err)
+	}
+
+	managed, err := listManagedSecrets(ctx, api, managedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := findOrphans(managed, liveSecretPaths(aSecrets.Items, awsClient), livePathPrefixes(aSecrets.Items, awsClient), awsClient.Config.SecretPathPrefix)
+	names := make([]string, 0, len(orphans))
+	var errs []error
+	for _, orphan := range orphans {
+		name := aws.ToString(orphan.Name)
+		names = append(names, name)
+		if !deleteOrphans {
+			log.Info("Found orphaned AWS secret", "path", name, "arn", aws.ToString(orphan.ARN))
+			continue
+		}
+
+		if _, err := api.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: orphan.ARN}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete orphaned AWS secret %s: %w", name, err))
+			continue
+		}
+		log.Info("Deleted orphaned AWS secret", "path", name, "arn", aws.ToString(orphan.ARN))
+	}
+	return names, errors.Join(errs...)
+}
+
+// runGCOrphans lists the orphaned AWS secrets once, deletes them with --gc-delete, and returns the exit code
+// of the process. --dry-run and --read-only turn deletion back into a report
+func runGCOrphans(ctx context.Context, operatorConfig *awsconfig.OperatorConfig, awsClient *awsclient.AwsClient, setupLog logr.Logger) int {
+	deleteOrphans := operatorConfig.Controller.GCDelete
+	if deleteOrphans && (operatorConfig.Controller.DryRun || operatorConfig.Controller.ReadOnly) {
+		setupLog.Info("Orphaned AWS secrets are only reported with --dry-run or --read-only")
+		deleteOrphans = false
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to load the Kubernetes client configuration")
+		return gcExitFailed
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes client")
+		return gcExitFailed
+	}
+
+	smClient, err := awsClient.CreateSecretsManagerClient(ctx, setupLog)
+	if err != nil {
+		setupLog.Error(err, "unable to create AWS SecretsManager client")
+		return gcExitFailed
+	}
+
+	orphans, err := gcOrphans(ctx, k8sClient, smClient, awsClient, deleteOrphans, setupLog)
+	if err != nil {
+		setupLog.Error(err, "Failed to collect orphaned AWS secrets")
+		return gcExitFailed
+	}
+	setupLog.Info("Collected orphaned AWS secrets", "orphans", len(orphans), "deleted", deleteOrphans)
+	return gcExitDone
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// Values of --log-format
+const (
+	// logFormatJSON writes one JSON object per line, for log pipelines
+	logFormatJSON = "json"
+	// logFormatConsole writes human readable lines
+	logFormatConsole = "console"
+)
+
+// zapOptions maps the logging configuration to the zap options of the operator logger.
+// Without --log-format the encoder follows --debug: console in development mode, JSON otherwise
+func zapOptions(operatorConfig *awsconfig.OperatorConfig) (zap.Options, error) {
+	opts := zap.Options{
+		Development: operatorConfig.Debug,
+	}
+
+	switch operatorConfig.LogFormat {
+	case "":
+	case logFormatJSON:
+		zap.JSONEncoder()(&opts)
+	case logFormatConsole:
+		zap.ConsoleEncoder()(&opts)
+	default:
+		return opts, fmt.Errorf("unknown log format %q, expected %s or %s", operatorConfig.LogFormat, logFormatJSON, logFormatConsole)
+	}
+	return opts, nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -12,10 +14,15 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
 	"github.com/yaso/yet-another-secrets-operator/pkg/controllers"
@@ -23,6 +30,7 @@ import (
 	//+kubebuilder:scaffold:imports
 	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
 	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
 )
 
 var (
@@ -48,8 +56,10 @@ func main() {
 	pflag.Parse()
 
 	// Set the global logger
-	opts := zap.Options{
-		Development: operatorConfig.Debug,
+	opts, err := zapOptions(operatorConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid --log-format:", err)
+		os.Exit(1)
 	}
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
@@ -69,44 +79,154 @@ func main() {
 	// Test AWS connectivity at startup
 	ctx := context.Background()
 
-	setupLog.Info("Testing AWS connectivity...")
-	if err := awsClient.TestConnection(ctx, setupLog); err != nil {
+	if err := awsclient.ValidateMaxRetries(operatorConfig.AWS.MaxRetries); err != nil {
+		setupLog.Error(err, "invalid --aws-max-retries")
+		os.Exit(1)
+	}
+	if err := awsclient.ValidateCABundle(operatorConfig.AWS.CABundle); err != nil {
+		setupLog.Error(err, "invalid --aws-ca-bundle")
+		os.Exit(1)
+	}
+
+	if err := validatePprofBindAddress(operatorConfig.Health); err != nil {
+		setupLog.Error(err, "invalid --pprof-bind-address")
+		os.Exit(1)
+	}
+
+	if err := checkAWSConnection(ctx, operatorConfig.AWS.SkipConnTest, awsClient.TestConnection, setupLog); err != nil {
 		setupLog.Error(err, "Failed to connect to AWS Secrets Manager")
 		os.Exit(1)
-	} else {
-		setupLog.Info("Successfully connected to AWS Secrets Manager")
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		HealthProbeBindAddress: operatorConfig.Health.ProbeBindAddress,
-		LeaderElection:         operatorConfig.Leader.Enabled,
-		LeaderElectionID:       operatorConfig.Leader.ID,
-	})
+	if err := controllers.ValidateReconcileMode(operatorConfig.Controller.ReconcileMode); err != nil {
+		setupLog.Error(err, "invalid --reconcile-mode")
+		os.Exit(1)
+	}
+
+	if err := controllers.ValidateRefreshJitter(operatorConfig.Controller.RefreshJitter); err != nil {
+		setupLog.Error(err, "invalid --refresh-jitter")
+		os.Exit(1)
+	}
 
+	objectLabelSelector, err := controllers.ParseObjectLabelSelector(operatorConfig.Controller.ObjectLabelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --object-label-selector")
+		os.Exit(1)
+	}
+
+	if operatorConfig.Controller.DryRun {
+		setupLog.Info("Dry-run mode enabled, no Kubernetes Secret or AWS secret will be written")
+	}
+	if operatorConfig.Controller.ReadOnly {
+		setupLog.Info("Read-only mode enabled, pushes to AWS secrets are suppressed")
+	}
+
+	// Orphan cleanup is a one-shot maintenance run, it starts no manager either
+	if operatorConfig.Controller.GCOrphans {
+		os.Exit(runGCOrphans(ctx, operatorConfig, awsClient, setupLog))
+	}
+
+	// A one-shot sync, e.g. from an init container, reconciles a single ASecret without a manager or leader election
+	if operatorConfig.Controller.Once != "" {
+		os.Exit(runOnce(ctx, operatorConfig, awsClient, setupLog))
+	}
+
+	// Restrict the cache to the watched namespaces, if any. Leader election is unaffected:
+	// its lease lives in the operator's own namespace, which does not need to be watched
+	cacheOptions := cache.Options{}
+	if len(operatorConfig.Controller.WatchNamespaces) > 0 {
+		setupLog.Info("Restricting reconciliation to namespaces", "namespaces", operatorConfig.Controller.WatchNamespaces)
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config)
+		for _, ns := range operatorConfig.Controller.WatchNamespaces {
+			cacheOptions.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+
+	// Only ASecrets matching the selector are cached, another operator shard caches the others
+	if objectLabelSelector != nil {
+		setupLog.Info("Restricting reconciliation to ASecrets matching the label selector", "selector", objectLabelSelector.String())
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&secretsv1alpha1.ASecret{}: {Label: objectLabelSelector},
+		}
+	}
+
+	// The resync re-reconciles every ASecret as a safety net, the per-secret refresh still sets the normal cadence
+	if period := operatorConfig.Controller.GlobalResyncPeriod; period > 0 {
+		setupLog.Info("Global resync enabled", "period", period)
+		cacheOptions.SyncPeriod = &period
+	}
+
+	if operatorConfig.Health.PprofBindAddress != "" {
+		setupLog.Info("Profiling enabled", "address", operatorConfig.Health.PprofBindAddress)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions(operatorConfig, cacheOptions))
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// A namespace-scoped install must grant its Role in every watched namespace, check it before reconciling
+	if namespaces := operatorConfig.Controller.WatchNamespaces; len(namespaces) > 0 && !operatorConfig.Controller.SkipNamespaceAccessCheck {
+		if err := controllers.VerifyNamespaceAccess(ctx, mgr.GetClient(), namespaces); err != nil {
+			setupLog.Error(err, "ServiceAccount lacks the permissions needed in the watched namespaces")
+			os.Exit(1)
+		}
+		setupLog.Info("Verified access to the watched namespaces", "namespaces", namespaces)
+	}
+
+	// Claims of AWS secrets are only tracked in memory, by the replica holding the leader lease
+	var remotePaths *controllers.RemotePathRegistry
+	if operatorConfig.Controller.DetectRemoteConflicts {
+		setupLog.Info("Detecting ASecrets writing the same AWS secret")
+		remotePaths = controllers.NewRemotePathRegistry()
+	}
+
 	if err = (&controllers.ASecretReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Log:       log.Log.WithName("controllers").WithName("ASecret"),
-		AwsClient: awsClient,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Log:                     log.Log.WithName("controllers").WithName("ASecret"),
+		AwsClient:               awsClient,
+		StartupSweepSpread:      operatorConfig.Controller.StartupSweepSpread,
+		MaxConcurrentReconciles: operatorConfig.Controller.MaxConcurrentReconciles,
+		DryRun:                  operatorConfig.Controller.DryRun,
+		ReconcileMode:           operatorConfig.Controller.ReconcileMode,
+		RefreshJitter:           operatorConfig.Controller.RefreshJitter,
+		GlobalResyncPeriod:      operatorConfig.Controller.GlobalResyncPeriod,
+		RecordLastChange:        operatorConfig.Controller.RecordLastChange,
+		ReadOnly:                operatorConfig.Controller.ReadOnly,
+		ObjectLabelSelector:     objectLabelSelector,
+		GeneratorsDisabled:      !operatorConfig.Controller.EnableGeneratorController,
+		RemotePaths:             remotePaths,
+		ShutdownGracePeriod:     operatorConfig.Controller.GracefulShutdownTimeout,
+		StartupSpread:           operatorConfig.Controller.StartupSpread,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ASecret")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.AGeneratorReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    log.Log.WithName("controllers").WithName("AGenerator"),
-	}).SetupWithManager(mgr); err != nil {
+	if err = setupGeneratorController(operatorConfig.Controller.EnableGeneratorController, func() error {
+		return (&controllers.AGeneratorReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      log.Log.WithName("controllers").WithName("AGenerator"),
+			Recorder: mgr.GetEventRecorderFor("agenerator-controller"),
+		}).SetupWithManager(mgr)
+	}, setupLog); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AGenerator")
 		os.Exit(1)
 	}
+
+	if operatorConfig.Webhook.Enabled {
+		if err = secretsv1alpha1.SetupASecretWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ASecret")
+			os.Exit(1)
+		}
+		if err = secretsv1alpha1.SetupAGeneratorWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AGenerator")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -118,9 +238,102 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Generators cannot produce values without a working random source, readiness fails if it breaks
+	entropyCheck := &utils.EntropyCheck{}
+	if err := entropyCheck.Test(); err != nil {
+		setupLog.Error(err, "entropy source is not functioning, generators will fail")
+	}
+	if err := mgr.AddReadyzCheck("entropy", entropyCheck.Check); err != nil {
+		setupLog.Error(err, "unable to set up entropy check")
+		os.Exit(1)
+	}
+
+	// Readiness reflects AWS reachability, tested in the background so probes never wait on AWS
+	if interval := operatorConfig.Health.AWSCheckInterval; interval > 0 {
+		smClient, err := awsClient.CreateSecretsManagerClient(ctx, setupLog)
+		if err != nil {
+			setupLog.Error(err, "unable to create AWS SecretsManager client for the readiness check")
+			os.Exit(1)
+		}
+		reachabilityCheck := &awsclient.ReachabilityCheck{Lister: smClient, Interval: interval, Log: setupLog.WithName("aws-readiness")}
+		if err := mgr.Add(reachabilityCheck); err != nil {
+			setupLog.Error(err, "unable to start AWS reachability test")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("aws", reachabilityCheck.Check); err != nil {
+			setupLog.Error(err, "unable to set up AWS readiness check")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// setupGeneratorController registers the AGenerator controller unless it is disabled, for deployments that only
+// import remote secrets and do not grant the AGenerator RBAC
+func setupGeneratorController(enabled bool, setup func() error, log logr.Logger) error {
+	if !enabled {
+		log.Info("AGenerator controller disabled, ASecret keys that need a generated value fail to sync")
+		return nil
+	}
+	return setup()
+}
+
+// checkAWSConnection runs the startup connectivity test unless it is skipped, for endpoints that are
+// not reachable at boot but become available later
+func checkAWSConnection(ctx context.Context, skip bool, testConnection func(context.Context, logr.Logger) error, log logr.Logger) error {
+	if skip {
+		log.Info("Skipping AWS connectivity test")
+		return nil
+	}
+
+	log.Info("Testing AWS connectivity...")
+	if err := testConnection(ctx, log); err != nil {
+		return err
+	}
+	log.Info("Successfully connected to AWS Secrets Manager")
+	return nil
+}
+
+// validatePprofBindAddress rejects a --pprof-bind-address sharing its port with the probe or metrics endpoint.
+// Profiles must stay on their own listener, so exposing the probes never exposes pprof as well
+func validatePprofBindAddress(health awsconfig.HealthConfig) error {
+	if health.PprofBindAddress == "" || health.PprofBindAddress == "0" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(health.PprofBindAddress)
+	if err != nil {
+		return fmt.Errorf("%q is not a host:port address: %w", health.PprofBindAddress, err)
+	}
+
+	for _, other := range []string{health.ProbeBindAddress, health.MetricsBindAddress} {
+		if _, otherPort, err := net.SplitHostPort(other); err == nil && otherPort == port && port != "0" {
+			return fmt.Errorf("%q shares its port with %q, the pprof endpoint needs a separate address", health.PprofBindAddress, other)
+		}
+	}
+	return nil
+}
+
+// managerOptions returns the options of the controller manager. The pprof endpoint is only served when
+// --pprof-bind-address is set, on a listener of its own that serves nothing but /debug/pprof/
+func managerOptions(operatorConfig *awsconfig.OperatorConfig, cacheOptions cache.Options) ctrl.Options {
+	return ctrl.Options{
+		Scheme:                  scheme,
+		Cache:                   cacheOptions,
+		HealthProbeBindAddress:  operatorConfig.Health.ProbeBindAddress,
+		Metrics:                 metricsserver.Options{BindAddress: operatorConfig.Health.MetricsBindAddress},
+		PprofBindAddress:        operatorConfig.Health.PprofBindAddress,
+		LeaderElection:          operatorConfig.Leader.Enabled,
+		LeaderElectionID:        operatorConfig.Leader.ID,
+		LeaderElectionNamespace: operatorConfig.Leader.Namespace,
+		GracefulShutdownTimeout: &operatorConfig.Controller.GracefulShutdownTimeout,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    operatorConfig.Webhook.Port,
+			CertDir: operatorConfig.Webhook.CertDir,
+		}),
+	}
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/controllers"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func TestCheckAWSConnection(t *testing.T) {
+	tests := []struct {
+		name          string
+		skip          bool
+		testErr       error
+		expectTested  bool
+		expectedError string
+	}{
+		{name: "connection succeeds", expectTested: true},
+		{name: "connection fails", testErr: errors.New("endpoint unreachable"), expectTested: true, expectedError: "endpoint unreachable"},
+		{name: "skipped test never connects", skip: true, testErr: errors.New("endpoint unreachable"), expectTested: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tested := false
+			err := checkAWSConnection(context.Background(), tt.skip, func(context.Context, logr.Logger) error {
+				tested = true
+				return tt.testErr
+			}, logr.Discard())
+
+			assert.Equal(t, tt.expectTested, tested)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetupGeneratorController(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		setupErr      error
+		expectSetup   bool
+		expectedError string
+	}{
+		{name: "enabled controller is registered", enabled: true, expectSetup: true},
+		{name: "registration error is returned", enabled: true, setupErr: errors.New("no kind AGenerator"), expectSetup: true, expectedError: "no kind AGenerator"},
+		{name: "disabled controller is never registered", enabled: false, expectSetup: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := false
+			err := setupGeneratorController(tt.enabled, func() error {
+				registered = true
+				return tt.setupErr
+			}, logr.Discard())
+
+			assert.Equal(t, tt.expectSetup, registered)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseOnceTarget(t *testing.T) {
+	tests := []struct {
+		target        string
+		expected      k8sTypes.NamespacedName
+		expectedError bool
+	}{
+		{target: "default/app-secrets", expected: k8sTypes.NamespacedName{Namespace: "default", Name: "app-secrets"}},
+		{target: "app-secrets", expectedError: true},
+		{target: "/app-secrets", expectedError: true},
+		{target: "default/", expectedError: true},
+		{target: "default/app/secrets", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target, err := parseOnceTarget(tt.target)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestOnceExitCode(t *testing.T) {
+	syncedASecret := func(status metav1.ConditionStatus, observedGeneration int64) *secretsv1alpha1.ASecret {
+		aSecret := &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+		aSecret.Status.Conditions = []metav1.Condition{{
+			Type:               controllers.ConditionTypeSynced,
+			Status:             status,
+			ObservedGeneration: observedGeneration,
+		}}
+		return aSecret
+	}
+
+	tests := []struct {
+		name         string
+		reconcileErr error
+		aSecret      *secretsv1alpha1.ASecret
+		dryRun       bool
+		expected     int
+	}{
+		{name: "synced", aSecret: syncedASecret(metav1.ConditionTrue, 2), expected: onceExitSynced},
+		{name: "reconcile error", reconcileErr: errors.New("access denied"), aSecret: syncedASecret(metav1.ConditionTrue, 2), expected: onceExitFailed},
+		{name: "not synced", aSecret: syncedASecret(metav1.ConditionFalse, 2), expected: onceExitFailed},
+		{name: "synced an older generation", aSecret: syncedASecret(metav1.ConditionTrue, 1), expected: onceExitFailed},
+		{name: "never synced", aSecret: &secretsv1alpha1.ASecret{}, expected: onceExitFailed},
+		{name: "dry run without error", aSecret: &secretsv1alpha1.ASecret{}, dryRun: true, expected: onceExitSynced},
+		{name: "dry run with error", reconcileErr: errors.New("access denied"), aSecret: &secretsv1alpha1.ASecret{}, dryRun: true, expected: onceExitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, onceExitCode(tt.reconcileErr, tt.aSecret, tt.dryRun))
+		})
+	}
+}
+
+func TestZapOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		debug       bool
+		logFormat   string
+		expectJSON  bool
+		expectError bool
+	}{
+		{name: "production defaults to json", expectJSON: true},
+		{name: "debug defaults to console", debug: true},
+		{name: "json in debug mode", debug: true, logFormat: "json", expectJSON: true},
+		{name: "console in production", logFormat: "console"},
+		{name: "unknown format", logFormat: "logfmt", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := awsconfig.NewDefaultConfig()
+			cfg.Debug = tt.debug
+			cfg.LogFormat = tt.logFormat
+
+			opts, err := zapOptions(cfg)
+			if tt.expectError {
+				assert.ErrorContains(t, err, `unknown log format "logfmt"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.debug, opts.Development)
+
+			var out bytes.Buffer
+			zap.New(zap.UseFlagOptions(&opts), zap.WriteTo(&out)).Info("synced", "asecret", "default/app")
+			assert.Equal(t, tt.expectJSON, json.Valid(out.Bytes()), "log line: %s", out.String())
+		})
+	}
+}
+
+// fakeOrphanAPI serves ListSecrets from pages chained by NextToken and records deletions
+type fakeOrphanAPI struct {
+	pages     [][]smtypes.SecretListEntry
+	deleteErr error
+	filters   []smtypes.Filter
+	deleted   []string
+}
+
+func (f *fakeOrphanAPI) ListSecrets(_ context.Context, params *secretsmanager.ListSecretsInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	f.filters = params.Filters
+	page := 0
+	if params.NextToken != nil {
+		page, _ = strconv.Atoi(*params.NextToken)
+	}
+	output := &secretsmanager.ListSecretsOutput{}
+	if page < len(f.pages) {
+		output.SecretList = f.pages[page]
+	}
+	if page+1 < len(f.pages) {
+		output.NextToken = aws.String(strconv.Itoa(page + 1))
+	}
+	return output, nil
+}
+
+func (f *fakeOrphanAPI) DeleteSecret(_ context.Context, params *secretsmanager.DeleteSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	if f.deleteErr != nil {
+		return nil, f.deleteErr
+	}
+	f.deleted = append(f.deleted, aws.ToString(params.SecretId))
+	return &secretsmanager.DeleteSecretOutput{}, nil
+}
+
+func managedEntry(name, managedBy string) smtypes.SecretListEntry {
+	return smtypes.SecretListEntry{
+		Name: aws.String(name),
+		ARN:  aws.String("arn:aws:secretsmanager:eu-west-1:123456789012:secret:" + name + "-AbCdEf"),
+		Tags: []smtypes.Tag{{Key: aws.String("managed-by"), Value: aws.String(managedBy)}},
+	}
+}
+
+func TestGCOrphans(t *testing.T) {
+	aSecret := func(name, secretPath string, binaryKeyMap map[string]string) *secretsv1alpha1.ASecret {
+		return &secretsv1alpha1.ASecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       secretsv1alpha1.ASecretSpec{AwsSecretPath: secretPath, BinaryKeyMap: binaryKeyMap},
+		}
+	}
+	deleting := aSecret("deleting", "app/deleting", nil)
+	deleting.DeletionTimestamp = &metav1.Time{}
+	deleting.Finalizers = []string{"example.com/cleanup"}
+
+	tests := []struct {
+		name            string
+		prefix          string
+		tags            map[string]string
+		aSecrets        []*secretsv1alpha1.ASecret
+		pages           [][]smtypes.SecretListEntry
+		deleteOrphans   bool
+		deleteErr       error
+		expectedOrphans []string
+		expectedDeleted []string
+		expectedError   string
+	}{
+		{
+			name:     "orphans across pages are reported",
+			aSecrets: []*secretsv1alpha1.ASecret{aSecret("live", "app/live", nil)},
+			pages: [][]smtypes.SecretListEntry{
+				{managedEntry("app/live", "yaso"), managedEntry("app/gone", "yaso")},
+				{managedEntry("app/also-gone", "yaso")},
+			},
+			expectedOrphans: []string{"app/gone", "app/also-gone"},
+		},
+		{
+			name: "secrets of ASecrets being deleted, binary key maps and ARNs are live",
+			aSecrets: []*secretsv1alpha1.ASecret{
+				deleting,
+				aSecret("binary", "", map[string]string{"tls.crt": "app/cert"}),
+				aSecret("full-arn", "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/full-AbCdEf", nil),
+				aSecret("partial-arn", "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/partial", nil),
+			},
+			pages: [][]smtypes.SecretListEntry{{
+				managedEntry("app/deleting", "yaso"),
+				managedEntry("app/cert", "yaso"),
+				managedEntry("app/full", "yaso"),
+				managedEntry("app/partial", "yaso"),
+			}},
+			expectedOrphans: []string{},
+		},
+		{
+			name: "secrets under the pathPrefix of an ASecret are live",
+			aSecrets: []*secretsv1alpha1.ASecret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "prefixed", Namespace: "default"},
+				Spec:       secretsv1alpha1.ASecretSpec{PathPrefix: "app/prod/"},
+			}},
+			pages:           [][]smtypes.SecretListEntry{{managedEntry("app/prod/db", "yaso"), managedEntry("app/staging/db", "yaso")}},
+			expectedOrphans: []string{"app/staging/db"},
+		},
+		{
+			name:            "secrets where the tag pair does not match are ignored",
+			pages:           [][]smtypes.SecretListEntry{{managedEntry("app/other", "other-operator"), managedEntry("app/gone", "yaso")}},
+			expectedOrphans: []string{"app/gone"},
+		},
+		{
+			name:            "prefix is applied and scopes the orphans",
+			prefix:          "team-a/",
+			aSecrets:        []*secretsv1alpha1.ASecret{aSecret("live", "app/live", nil)},
+			pages:           [][]smtypes.SecretListEntry{{managedEntry("team-a/app/live", "yaso"), managedEntry("team-a/app/gone", "yaso"), managedEntry("team-b/app/gone", "yaso")}},
+			expectedOrphans: []string{"team-a/app/gone"},
+		},
+		{
+			name:            "orphans are deleted with deleteOrphans",
+			aSecrets:        []*secretsv1alpha1.ASecret{aSecret("live", "app/live", nil)},
+			pages:           [][]smtypes.SecretListEntry{{managedEntry("app/live", "yaso"), managedEntry("app/gone", "yaso")}},
+			deleteOrphans:   true,
+			expectedOrphans: []string{"app/gone"},
+			expectedDeleted: []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/gone-AbCdEf"},
+		},
+		{
+			name:            "failed deletion is reported",
+			pages:           [][]smtypes.SecretListEntry{{managedEntry("app/gone", "yaso")}},
+			deleteOrphans:   true,
+			deleteErr:       errors.New("access denied"),
+			expectedOrphans: []string{"app/gone"},
+			expectedError:   "failed to delete orphaned AWS secret app/gone",
+		},
+		{
+			name:          "no managed-by tag",
+			tags:          map[string]string{},
+			pages:         [][]smtypes.SecretListEntry{{managedEntry("app/gone", "yaso")}},
+			expectedError: "no managed-by tag is configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, aSecret := range tt.aSecrets {
+				builder = builder.WithObjects(aSecret.DeepCopy())
+			}
+			awsConfig := awsconfig.NewDefaultConfig().AWS
+			awsConfig.SecretPathPrefix = tt.prefix
+			if tt.tags != nil {
+				awsConfig.Tags = tt.tags
+			}
+			api := &fakeOrphanAPI{pages: tt.pages, deleteErr: tt.deleteErr}
+
+			orphans, err := gcOrphans(context.Background(), builder.Build(), api, awsclient.NewClient(awsConfig), tt.deleteOrphans, logr.Discard())
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Contains(t, api.filters, smtypes.Filter{Key: smtypes.FilterNameStringTypeTagValue, Values: []string{"yaso"}})
+			}
+			assert.ElementsMatch(t, tt.expectedOrphans, orphans)
+			assert.Equal(t, tt.expectedDeleted, api.deleted)
+		})
+	}
+}
+
+func TestValidatePprofBindAddress(t *testing.T) {
+	tests := []struct {
+		name          string
+		pprofAddress  string
+		expectedError string
+	}{
+		{name: "disabled by default"},
+		{name: "disabled with 0", pprofAddress: "0"},
+		{name: "separate port", pprofAddress: "127.0.0.1:6060"},
+		{name: "missing port", pprofAddress: "localhost", expectedError: "not a host:port address"},
+		{name: "probe port", pprofAddress: "127.0.0.1:8081", expectedError: `shares its port with ":8081"`},
+		{name: "metrics port", pprofAddress: ":8080", expectedError: `shares its port with ":8080"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := awsconfig.NewDefaultConfig().Health
+			health.PprofBindAddress = tt.pprofAddress
+
+			err := validatePprofBindAddress(health)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManagerOptionsMetricsBindAddress(t *testing.T) {
+	operatorConfig := awsconfig.NewDefaultConfig()
+	operatorConfig.Health.MetricsBindAddress = ":9090"
+
+	// The address validatePprofBindAddress compares against is the one the manager binds
+	options := managerOptions(operatorConfig, cache.Options{})
+	assert.Equal(t, metricsserver.Options{BindAddress: ":9090"}, options.Metrics)
+}
+
+func TestPprofEndpoint(t *testing.T) {
+	// Reserve a free port for the pprof listener, the manager binds it again on start
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	pprofAddress := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	operatorConfig := awsconfig.NewDefaultConfig()
+	operatorConfig.Health.ProbeBindAddress = "0"
+	operatorConfig.Health.MetricsBindAddress = "0"
+	operatorConfig.Health.PprofBindAddress = pprofAddress
+	options := managerOptions(operatorConfig, cache.Options{})
+
+	// Nothing is watched, so the manager starts without reaching the API server
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, options)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- mgr.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://" + pprofAddress + "/debug/pprof/")
+		return err == nil
+	}, 10*time.Second, 50*time.Millisecond)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Only the pprof handlers are served, not the probes or anything registered on the default mux
+	healthResp, err := http.Get("http://" + pprofAddress + "/healthz")
+	require.NoError(t, err)
+	defer healthResp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, healthResp.StatusCode)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/controllers"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// Exit codes of --once
+const (
+	// onceExitSynced means the ASecret was synced
+	onceExitSynced = 0
+	// onceExitFailed means the ASecret does not exist or did not sync
+	onceExitFailed = 1
+	// onceExitUsage means the --once argument is not a namespace/name
+	onceExitUsage = 2
+)
+
+// parseOnceTarget parses the <namespace>/<name> argument of --once
+func parseOnceTarget(target string) (k8sTypes.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(target, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return k8sTypes.NamespacedName{}, fmt.Errorf("--once expects <namespace>/<name>, got %q", target)
+	}
+	return k8sTypes.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// onceExitCode maps the outcome of the one-shot reconcile to the exit code. A reconcile can return without
+// an error and still not sync, e.g. when it refuses to empty the Secret, so the Synced condition decides.
+// A dry run never updates Synced and only fails on an error
+func onceExitCode(reconcileErr error, aSecret *secretsv1alpha1.ASecret, dryRun bool) int {
+	if reconcileErr != nil {
+		return onceExitFailed
+	}
+	if dryRun {
+		return onceExitSynced
+	}
+	synced := meta.FindStatusCondition(aSecret.Status.Conditions, controllers.ConditionTypeSynced)
+	if synced == nil || synced.Status != "True" || synced.ObservedGeneration != aSecret.Generation {
+		return onceExitFailed
+	}
+	return onceExitSynced
+}
+
+// runOnce reconciles the ASecret named by --once a single time, reading and writing through the API
+// server directly instead of a manager cache, and returns the exit code of the process
+func runOnce(ctx context.Context, operatorConfig *awsconfig.OperatorConfig, awsClient *awsclient.AwsClient, setupLog logr.Logger) int {
+	target, err := parseOnceTarget(operatorConfig.Controller.Once)
+	if err != nil {
+		setupLog.Error(err, "invalid --once")
+		return onceExitUsage
+	}
+	setupLog.Info("Syncing a single ASecret", "asecret", target)
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to load the Kubernetes client configuration")
+		return onceExitFailed
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes client")
+		return onceExitFailed
+	}
+
+	reconciler := &controllers.ASecretReconciler{
+		Client:             k8sClient,
+		Scheme:             scheme,
+		Log:                log.Log.WithName("controllers").WithName("ASecret"),
+		AwsClient:          awsClient,
+		DryRun:             operatorConfig.Controller.DryRun,
+		RecordLastChange:   operatorConfig.Controller.RecordLastChange,
+		ReadOnly:           operatorConfig.Controller.ReadOnly,
+		GeneratorsDisabled: !operatorConfig.Controller.EnableGeneratorController,
+	}
+	if err := reconciler.SetupSecretsManagers(ctx); err != nil {
+		setupLog.Error(err, "unable to create AWS SecretsManager clients")
+		return onceExitFailed
+	}
+
+	_, reconcileErr := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: target})
+	if reconcileErr != nil {
+		setupLog.Error(reconcileErr, "Failed to sync ASecret", "asecret", target)
+	}
+
+	// The status written by the reconcile tells whether it synced
+	var aSecret secretsv1alpha1.ASecret
+	if err := k8sClient.Get(ctx, target, &aSecret); err != nil {
+		setupLog.Error(err, "unable to read ASecret after the sync", "asecret", target)
+		return onceExitFailed
+	}
+	code := onceExitCode(reconcileErr, &aSecret, operatorConfig.Controller.DryRun)
+	if code == onceExitSynced {
+		setupLog.Info("Synced ASecret", "asecret", target)
+	} else if reconcileErr == nil {
+		setupLog.Info("ASecret did not sync", "asecret", target, "conditions", aSecret.Status.Conditions)
+	}
+	return code
+}
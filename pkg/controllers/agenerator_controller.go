@@ -4,25 +4,36 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
 	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
 )
 
+// ConditionTypeReady reports whether the AGenerator spec is valid and can generate values
+const ConditionTypeReady = "Ready"
+
 // AGeneratorReconciler reconciles a AGenerator object
 type AGeneratorReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Log    logr.Logger
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=agenerators,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=agenerators/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=agenerators/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *AGeneratorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -43,18 +54,50 @@ func (r *AGeneratorReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// AGenerator is a passive object that is referenced by ASecret resources
 	// No active reconciliation is needed besides validation
 
-	// Validate the generator specification
+	// Validate the generator specification. An invalid spec is not retried, it is reconciled again once edited
 	if err := utils.ValidateGeneratorSpec(aGenerator.Spec); err != nil {
 		log.Error(err, "Invalid generator specification")
-		return ctrl.Result{}, err
+		if r.setReadyCondition(ctx, &aGenerator, metav1.ConditionFalse, "ValidationFailed", err.Error(), log) {
+			r.Recorder.Event(&aGenerator, corev1.EventTypeWarning, "ValidationFailed", err.Error())
+		}
+		return ctrl.Result{}, nil
+	}
+	if r.setReadyCondition(ctx, &aGenerator, metav1.ConditionTrue, "ValidationSucceeded", "Generator specification is valid", log) {
+		// The condition records each generation, so the reminder is emitted once per spec edit
+		if aGenerator.Spec.Deterministic {
+			r.Recorder.Event(&aGenerator, corev1.EventTypeWarning, "DeterministicGenerator",
+				"Values are derived from the seed Secret and are only as secret as the seed, prefer random generation where reproducibility is not needed")
+		}
+		r.Recorder.Event(&aGenerator, corev1.EventTypeNormal, "ValidationSucceeded", "Generator specification is valid")
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// setReadyCondition records the outcome of the spec validation, only writing the status when it changed
+// so that the status update does not trigger another reconcile. It reports whether the condition changed,
+// so that events are only emitted on transitions
+func (r *AGeneratorReconciler) setReadyCondition(ctx context.Context, aGenerator *secretsv1alpha1.AGenerator, status metav1.ConditionStatus, reason, message string, log logr.Logger) bool {
+	changed := meta.SetStatusCondition(&aGenerator.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		ObservedGeneration: aGenerator.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if !changed {
+		return false
+	}
+
+	if err := r.Status().Update(ctx, aGenerator); err != nil {
+		log.Error(err, "Failed to update AGenerator status")
+	}
+	return true
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AGeneratorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.AGenerator{}).
+		For(&secretsv1alpha1.AGenerator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
@@ -2,14 +2,17 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -44,13 +47,16 @@ func setupAGeneratorController(t testingInterface) (*AGeneratorReconciler, clien
 	// Create a fake client
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(s).
+		WithStatusSubresource(&secretsv1alpha1.AGenerator{}).
 		Build()
 
 	// Create the reconciler
+	// Events are dropped unless a test installs a recorder with a buffered channel
 	reconciler := &AGeneratorReconciler{
-		Client: fakeClient,
-		Scheme: s,
-		Log:    zap.New(zap.UseDevMode(true)),
+		Client:   fakeClient,
+		Scheme:   s,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Recorder: &record.FakeRecorder{},
 	}
 
 	return reconciler, fakeClient
@@ -99,6 +105,12 @@ func TestAGeneratorReconciler_ReconcileValidGenerator(t *testing.T) {
 	err = fakeClient.Get(ctx, req.NamespacedName, &retrievedGenerator)
 	assert.NoError(t, err)
 	assert.Equal(t, "test-generator", retrievedGenerator.Name)
+
+	ready := meta.FindStatusCondition(retrievedGenerator.Status.Conditions, ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, "ValidationSucceeded", ready.Reason)
+	assert.Equal(t, "Generator specification is valid", ready.Message)
 }
 
 func TestAGeneratorReconciler_ReconcileInvalidGenerator(t *testing.T) {
@@ -135,10 +147,29 @@ func TestAGeneratorReconciler_ReconcileInvalidGenerator(t *testing.T) {
 	// Reconcile
 	result, err := reconciler.Reconcile(ctx, req)
 
-	// Assertions - should return an error due to invalid spec
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "at least one character type")
+	// Assertions - an invalid spec is reported on the condition, not retried
+	assert.NoError(t, err)
 	assert.Equal(t, ctrl.Result{}, result)
+
+	// The validation failure is recorded on the generator itself
+	var updated secretsv1alpha1.AGenerator
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "ValidationFailed", ready.Reason)
+	assert.Contains(t, ready.Message, "at least one character type")
+
+	// Fixing the spec flips the condition
+	updated.Spec.IncludeLowercase = true
+	require.NoError(t, fakeClient.Update(ctx, &updated))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	ready = meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, "ValidationSucceeded", ready.Reason)
 }
 
 func TestAGeneratorReconciler_ReconcileInvalidGeneratorZeroLength(t *testing.T) {
@@ -175,10 +206,14 @@ func TestAGeneratorReconciler_ReconcileInvalidGeneratorZeroLength(t *testing.T)
 	// Reconcile
 	result, err := reconciler.Reconcile(ctx, req)
 
-	// Assertions - should return an error due to zero length
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "length must be greater than 0")
+	// Assertions - an invalid spec is reported on the condition, not retried
+	assert.NoError(t, err)
 	assert.Equal(t, ctrl.Result{}, result)
+
+	var updated secretsv1alpha1.AGenerator
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, ConditionTypeReady))
+	assert.Equal(t, "ValidationFailed", meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady).Reason)
 }
 
 func TestAGeneratorReconciler_ReconcileNotFound(t *testing.T) {
@@ -304,6 +339,153 @@ func TestAGeneratorReconciler_ReconcileValidGeneratorAllCombinations(t *testing.
 	}
 }
 
+func TestAGeneratorReconciler_ValidationEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           secretsv1alpha1.AGeneratorSpec
+		expectedEvents []string
+	}{
+		{
+			name: "valid spec",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length:           16,
+				IncludeLowercase: true,
+			},
+			expectedEvents: []string{"Normal ValidationSucceeded Generator specification is valid"},
+		},
+		{
+			name: "no character types",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length: 16,
+			},
+			expectedEvents: []string{"Warning ValidationFailed at least one character type (uppercase, lowercase, numbers, or special chars) must be enabled"},
+		},
+		{
+			name: "deterministic spec",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length:           16,
+				IncludeLowercase: true,
+				Deterministic:    true,
+				SeedSecretRef:    &secretsv1alpha1.SecretKeyReference{Name: "seed", Namespace: "default", Key: "seed"},
+			},
+			expectedEvents: []string{
+				"Warning DeterministicGenerator Values are derived from the seed Secret and are only as secret as the seed, prefer random generation where reproducibility is not needed",
+				"Normal ValidationSucceeded Generator specification is valid",
+			},
+		},
+		{
+			name: "zero length",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length:           0,
+				IncludeLowercase: true,
+			},
+			expectedEvents: []string{"Warning ValidationFailed length must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler, fakeClient := setupAGeneratorController(t)
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+			ctx := context.Background()
+
+			generator := &secretsv1alpha1.AGenerator{
+				ObjectMeta: metav1.ObjectMeta{Name: "event-generator"},
+				Spec:       tt.spec,
+			}
+			require.NoError(t, fakeClient.Create(ctx, generator))
+
+			_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "event-generator"}})
+
+			require.Len(t, recorder.Events, len(tt.expectedEvents))
+			for _, expected := range tt.expectedEvents {
+				assert.Equal(t, expected, <-recorder.Events)
+			}
+		})
+	}
+}
+
+func TestAGeneratorReconciler_EventsOnlyOnTransitions(t *testing.T) {
+	reconciler, fakeClient := setupAGeneratorController(t)
+	recorder := record.NewFakeRecorder(10)
+	reconciler.Recorder = recorder
+	ctx := context.Background()
+
+	generator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "event-generator", Generation: 1},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 16},
+	}
+	require.NoError(t, fakeClient.Create(ctx, generator))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "event-generator"}}
+
+	// Reconciling an unchanged generator again does not repeat the event
+	for range 2 {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning ValidationFailed at least one character type (uppercase, lowercase, numbers, or special chars) must be enabled", <-recorder.Events)
+
+	// Fixing the spec is a transition
+	var updated secretsv1alpha1.AGenerator
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	updated.Spec.IncludeLowercase = true
+	require.NoError(t, fakeClient.Update(ctx, &updated))
+	for range 2 {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal ValidationSucceeded Generator specification is valid", <-recorder.Events)
+}
+
+func TestAGeneratorReconciler_DeterministicWarningOncePerGeneration(t *testing.T) {
+	reconciler, fakeClient := setupAGeneratorController(t)
+	recorder := record.NewFakeRecorder(10)
+	reconciler.Recorder = recorder
+	ctx := context.Background()
+
+	generator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "deterministic-generator", Generation: 1},
+		Spec: secretsv1alpha1.AGeneratorSpec{
+			Length:           16,
+			IncludeLowercase: true,
+			Deterministic:    true,
+			SeedSecretRef:    &secretsv1alpha1.SecretKeyReference{Name: "seed", Namespace: "default", Key: "seed"},
+		},
+	}
+	require.NoError(t, fakeClient.Create(ctx, generator))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "deterministic-generator"}}
+
+	countWarnings := func() int {
+		warnings := 0
+		for len(recorder.Events) > 0 {
+			if strings.HasPrefix(<-recorder.Events, "Warning DeterministicGenerator") {
+				warnings++
+			}
+		}
+		return warnings
+	}
+
+	for range 3 {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, countWarnings())
+
+	// A new generation is validated again and reminds once more
+	var updated secretsv1alpha1.AGenerator
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	updated.Generation = 2
+	require.NoError(t, fakeClient.Update(ctx, &updated))
+	for range 3 {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, countWarnings())
+}
+
 func TestAGeneratorReconciler_SetupWithManager(t *testing.T) {
 	reconciler, _ := setupAGeneratorController(t)
 
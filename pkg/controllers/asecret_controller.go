@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,15 +19,22 @@ import (
 	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sTypes "k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
 	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
 	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
 )
@@ -34,6 +46,39 @@ type ASecretReconciler struct {
 	Log            logr.Logger
 	AwsClient      *awsclient.AwsClient
 	SecretsManager awsclient.SecretsManagerAPI
+	// EndpointSecretsManagers serves the ASecrets that set spec.endpointURL
+	EndpointSecretsManagers *awsclient.EndpointSecretsManagers
+
+	// StartupSweepSpread is the window over which all ASecrets are re-enqueued on startup (0 disables the sweep)
+	StartupSweepSpread time.Duration
+	// MaxConcurrentReconciles is the number of ASecrets reconciled in parallel
+	MaxConcurrentReconciles int
+	// DryRun computes the changes of each reconcile and reports them without writing anything
+	DryRun bool
+	// ReconcileMode selects event-driven or polling reconciles, see ReconcileModeEvent and ReconcileModePoll
+	ReconcileMode string
+	// RefreshJitter is the percentage by which each refresh requeue is randomly shortened or lengthened (0 disables it)
+	RefreshJitter int
+	// GlobalResyncPeriod is the informer resync period, resync events re-reconcile every ASecret (0 disables them)
+	GlobalResyncPeriod time.Duration
+	// ReadOnly never creates, updates, tags or replicates AWS secrets, whatever the spec of the ASecret
+	ReadOnly bool
+	// RecordLastChange records the key changes of each Secret update in LastChangeAnnotation
+	RecordLastChange bool
+	// GeneratorsDisabled fails keys that need a generated value instead of reading their AGenerator, for operators
+	// running without the AGenerator controller and its RBAC
+	GeneratorsDisabled bool
+	// RemotePaths refuses ASecrets writing an AWS secret another ASecret writes already (nil disables the detection)
+	RemotePaths *RemotePathRegistry
+	// ObjectLabelSelector restricts reconciles to the ASecrets whose labels match it (nil reconciles all)
+	ObjectLabelSelector labels.Selector
+	// ShutdownGracePeriod lets in-flight reconciles finish their writes for that long after the manager stops
+	ShutdownGracePeriod time.Duration
+	// StartupSpread defers the first reconcile of each ASecret already in sync by a random delay within it (0 disables)
+	StartupSpread time.Duration
+
+	// startupSeen holds the ASecrets reconciled since the operator started, for StartupSpread
+	startupSeen sync.Map
 }
 
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=asecrets,verbs=get;list;watch;create;update;patch;delete
@@ -41,35 +86,141 @@ type ASecretReconciler struct {
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=asecrets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=yet-another-secrets.io,resources=agenerators,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *ASecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("asecret", req.NamespacedName)
 	log.V(1).Info("Reconciling ASecret")
 
+	// The manager cancels ctx on shutdown, a started reconcile still gets the grace period to finish its writes
+	ctx, cancel := withShutdownGrace(ctx, r.ShutdownGracePeriod)
+	defer cancel()
+
 	// Fetch the ASecret instance
 	var aSecret secretsv1alpha1.ASecret
 	if err := r.Get(ctx, req.NamespacedName, &aSecret); err != nil {
 		if apierrors.IsNotFound(err) {
+			// A deleted ASecret no longer claims its AWS secret
+			if r.RemotePaths != nil {
+				r.RemotePaths.Release(req.NamespacedName)
+			}
+			r.forgetStartupDelay(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
+	// A paused ASecret is left as it is, toggling the annotation triggers the next reconcile
+	if isPaused(&aSecret) {
+		log.Info("ASecret is paused, skipping reconcile", "annotation", PausedAnnotation)
+		r.setPausedCondition(ctx, &aSecret, log)
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(&aSecret.Status.Conditions, ConditionTypePaused)
+
+	// ASecrets already in sync wait a random delay on their first reconcile, spreading the startup load on AWS
+	if delay := r.startupDelay(&aSecret); delay > 0 {
+		log.V(1).Info("Deferring first reconcile after startup", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// A new force-sync value reads AWS directly, a cached value could predate the change being waited for
+	if forceSyncPending(&aSecret) {
+		log.Info("Forced sync requested", "annotation", ForceSyncAnnotation, "value", aSecret.Annotations[ForceSyncAnnotation])
+		ctx = awsclient.WithoutCache(ctx)
+	}
+
+	// An ambiguous spec is not retried, the fix is a spec edit which triggers the next reconcile
+	if err := errors.Join(validateDataSources(&aSecret), validateSecretRefs(&aSecret), validateKeyMappings(&aSecret), validateResourcePolicy(&aSecret)); err != nil {
+		log.Error(err, "Invalid ASecret spec, skipping reconcile")
+		r.setSyncFailedCondition(ctx, &aSecret, ReasonInvalidSpec, err, log)
+		return ctrl.Result{}, nil
+	}
+	if err := validateTargetSecretName(&aSecret); err != nil {
+		log.Error(err, "Invalid target Secret name, skipping reconcile")
+		r.setSyncFailedCondition(ctx, &aSecret, ReasonInvalidTargetName, err, log)
+		return ctrl.Result{}, nil
+	}
+
+	// Two ASecrets writing the same AWS secret would overwrite each other on alternating reconciles
+	if owner, claimed := r.claimRemotePath(&aSecret); !claimed {
+		log.Info("AWS secret is managed by another ASecret, skipping reconcile", "owner", owner)
+		r.setRemotePathConflictCondition(ctx, &aSecret, owner, log)
+		return ctrl.Result{RequeueAfter: r.refreshInterval(&aSecret)}, nil
+	}
+
 	// Use the injected AWS client
 	awsClient := r.AwsClient
 	smClient := r.SecretsManager
+	localOnly := isLocalOnly(&aSecret)
 
-	// Log which credential provider is being used
-	if providerName, err := awsClient.GetCredentialProviderInfo(ctx, log); err == nil {
-		log.V(1).Info("AWS credential provider", "provider", providerName)
-	}
+	var awsSecretData map[string]string
+	awsSecretExists := false
+	if localOnly {
+		log.V(1).Info("Provider is none, skipping AWS SecretsManager")
+	} else {
+		// Log which credential provider is being used, only resolved when debug logging is enabled
+		if log.V(1).Enabled() {
+			if providerName, err := awsClient.GetCredentialProviderInfo(ctx, log); err == nil {
+				log.V(1).Info("AWS credential provider", "provider", providerName)
+			}
+		}
 
-	// Check if the secret exists in AWS SecretsManager
-	awsSecretData, awsSecretExists, err := r.getAwsSecret(ctx, smClient, &aSecret, log)
-	if err != nil {
-		log.Error(err, "Failed to check AWS SecretsManager")
-		return ctrl.Result{RequeueAfter: time.Second * 30}, err
+		// Refuse secrets referenced by ARN in another account than the assumed role's
+		for _, secretPath := range awsSecretPaths(&aSecret) {
+			if err := awsClient.ValidateSecretAccount(secretPath); err != nil {
+				log.Error(err, "AWS secret ARN does not match the configured account", "secretPath", secretPath)
+				r.setSyncFailedCondition(ctx, &aSecret, ReasonAccountMismatch, err, log)
+				return ctrl.Result{}, err
+			}
+		}
+
+		if r.AwsClient.Config.SecretPathPrefix != "" {
+			log.V(1).Info("Resolved AWS secret path", "awsSecretPath", aSecret.Spec.AwsSecretPath, "path", r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath))
+		}
+
+		// ASecrets overriding the endpoint, e.g. to reach a LocalStack instance, use a client of their own
+		if aSecret.Spec.EndpointURL != "" {
+			endpointClient, err := r.EndpointSecretsManagers.For(ctx, aSecret.Spec.EndpointURL)
+			// An endpoint the operator does not allow stays refused until the spec or the flags change
+			if errors.Is(err, awsclient.ErrEndpointNotAllowed) {
+				log.Error(err, "ASecret endpoint is not allowed, skipping reconcile", "endpoint", aSecret.Spec.EndpointURL)
+				r.setSyncFailedCondition(ctx, &aSecret, ReasonInvalidSpec, err, log)
+				return ctrl.Result{}, nil
+			}
+			if err != nil {
+				log.Error(err, "Failed to create AWS SecretsManager client for endpoint", "endpoint", aSecret.Spec.EndpointURL)
+				r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
+				return ctrl.Result{}, err
+			}
+			smClient = endpointClient
+		}
+
+		// Check if the secret exists in AWS SecretsManager
+		var err error
+		awsSecretData, awsSecretExists, err = r.getOrRestoreAwsSecret(ctx, smClient, &aSecret, log)
+		var deleting *awsSecretDeletingError
+		if errors.As(err, &deleting) {
+			r.setAwsSecretDeletingCondition(ctx, &aSecret, err, log)
+			// The secret stays unreadable until it is restored, retrying sooner would not help
+			return ctrl.Result{RequeueAfter: r.jitterRefresh(r.refreshInterval(&aSecret))}, nil
+		}
+		if err != nil {
+			log.Error(err, "Failed to check AWS SecretsManager")
+			r.setAwsUnavailableCondition(ctx, &aSecret, err, log)
+			return ctrl.Result{RequeueAfter: time.Second * 30}, err
+		}
+
+		// Decode values stored with an encoding before they reach the Kubernetes Secret
+		if err := r.decodeAwsSecretData(&aSecret, awsSecretData); err != nil {
+			log.Error(err, "Failed to decode AWS secret data")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonDecodeFailed, err, log)
+			return ctrl.Result{}, err
+		}
+
+		// Normalize line endings of imported text values so CRLF values do not cause diff churn
+		r.normalizeAwsSecretData(&aSecret, awsSecretData)
 	}
 
 	// Look for existing Kubernetes secret
@@ -90,99 +241,283 @@ func (r *ASecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			}
 		} else {
 			log.Error(err, "Failed to get Secret")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Two ASecrets writing the same Secret would overwrite each other on every reconcile
+	if owner := conflictingOwner(&aSecret, existingSecret); kubeSecretExists && owner != "" {
+		err := fmt.Errorf("refusing to write Secret %s, it is managed by ASecret %s", existingSecret.Name, owner)
+		log.Error(err, "Kubernetes Secret is managed by another ASecret", "owner", owner)
+		r.setConflictCondition(ctx, &aSecret, owner, err, log)
+		return ctrl.Result{RequeueAfter: r.refreshInterval(&aSecret)}, nil
+	}
+
 	// Prepare the secret data using extracted function
 	secretData := r.prepareSecretData(&aSecret, existingSecret, awsSecretData, awsSecretExists, kubeSecretExists, log)
 
 	// Process ASecret data specifications if not onlyImportRemote
-	onlyImportRemote := aSecret.Spec.OnlyImportRemote != nil && *aSecret.Spec.OnlyImportRemote
+	onlyImportRemote := isImportOnly(&aSecret)
+	now := time.Now()
+	var rotatedKeys, generatedKeys []string
 	if !onlyImportRemote {
+		// Drop generated values that are due for rotation so they are generated again
+		rotatedKeys = r.rotateGeneratedKeys(&aSecret, secretData, now, log)
+		generatedKeys = missingGeneratedKeys(&aSecret, secretData)
+
 		if err := r.processASecretData(ctx, &aSecret, secretData, log); err != nil {
+			// A cancelled context cannot update the status either, the next run starts again from a consistent state
+			if ctx.Err() != nil {
+				log.Info("Reconcile interrupted by shutdown while processing ASecret data")
+				return ctrl.Result{}, err
+			}
 			log.Error(err, "Failed to process ASecret data")
+			var genErr *generatorError
+			if errors.As(err, &genErr) {
+				r.setGeneratorErrorCondition(ctx, &aSecret, genErr, log)
+			} else {
+				r.setSyncFailedCondition(ctx, &aSecret, dataErrorReason(err), err, log)
+			}
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Render the Kubernetes Secret data, including derived keys such as the dotenv output
+	kubeSecretData, err := r.renderKubeSecretData(&aSecret, secretData)
+	if err != nil {
+		log.Error(err, "Failed to render Kubernetes Secret data")
+		r.setSyncFailedCondition(ctx, &aSecret, ReasonTemplateError, err, log)
+		return ctrl.Result{}, err
+	}
+
+	// Keys other writers added to the Secret are written back as they are
+	kubeSecretData, ownedKeys := mergeExternalKeys(kubeSecretData, preservedKeys(&aSecret, existingSecret, awsSecretData), log)
+
+	// In dry-run mode, report what would change and stop before any write
+	if r.DryRun {
+		plan := r.planDryRun(&aSecret, existingSecret, kubeSecretExists, kubeSecretData, secretData, awsSecretData, awsSecretExists, len(rotatedKeys) > 0)
+		return r.reportDryRun(ctx, &aSecret, plan, log)
+	}
+
+	// Refuse to wipe an existing Secret, which is most likely a spec mistake
+	if kubeSecretExists && wouldEmptySecret(&aSecret, existingSecret, kubeSecretData) {
+		err := fmt.Errorf("refusing to remove all %d keys from Secret %s, set spec.allowEmptySecret to allow it", len(existingSecret.Data), existingSecret.Name)
+		log.Error(err, "Kubernetes Secret update would remove all keys")
+		r.setWouldEmptySecretCondition(ctx, &aSecret, err, log)
+		return ctrl.Result{RequeueAfter: r.refreshInterval(&aSecret)}, nil
+	}
+
+	// Rotated values, ConfigMap and Secret updates and values winning over AWS keep their keys, so they have to be pushed explicitly
+	needsAwsUpdate := false
+	if !localOnly && !onlyImportRemote {
+		configMapKeys := changedReferencedKeys(&aSecret, secretData, awsSecretData)
+		divergedKeys := r.divergedRemoteKeys(&aSecret, secretData, awsSecretData)
+		needsAwsUpdate = len(rotatedKeys) > 0 || len(configMapKeys) > 0 || len(divergedKeys) > 0 || r.shouldUpdateAwsSecret(&aSecret, secretData, awsSecretData, awsSecretExists)
+	}
+
+	// Refuse an oversized AWS value before any write, so the Kubernetes Secret does not run ahead of AWS
+	if needsAwsUpdate && !r.ReadOnly {
+		if _, err := r.awsSecretValue(&aSecret, secretData); isSecretTooLarge(err) {
+			// The value only shrinks when the data changes, so backing off would not help
+			log.Error(err, "AWS Secret value is too large", "path", r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath))
+			r.setSecretTooLargeCondition(ctx, &aSecret, err, log)
+			return ctrl.Result{RequeueAfter: r.refreshInterval(&aSecret)}, nil
+		}
+	}
+
+	// Stop before the first write when the grace period is over, the next run starts again from a consistent state
+	if err := ctx.Err(); err != nil {
+		log.Info("Reconcile interrupted by shutdown before writing the Secret")
+		return ctrl.Result{}, err
+	}
+
 	// Create or update the Kubernetes secret
 	if !kubeSecretExists {
-		existingSecret.Data = secretData
+		existingSecret.Data = kubeSecretData
 		existingSecret.Type = corev1.SecretTypeOpaque
 
 		// Apply target secret template if specified
 		r.applyTargetSecretTemplate(&aSecret, existingSecret)
+		applyImmutability(&aSecret, existingSecret)
+		setManagedKeysAnnotation(existingSecret, ownedKeys)
+		setSourceAnnotations(&aSecret, existingSecret, ownedKeys, log)
 
-		if err := controllerutil.SetControllerReference(&aSecret, existingSecret, r.Scheme); err != nil {
-			log.Error(err, "Failed to set controller reference on Secret")
+		if err := r.applyOwnership(&aSecret, existingSecret, false); err != nil {
+			log.Error(err, "Failed to set ownership of Secret")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
 			return ctrl.Result{}, err
 		}
 
 		if err := r.Create(ctx, existingSecret); err != nil {
 			log.Error(err, "Failed to create Secret")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
 			return ctrl.Result{}, err
 		}
 		log.Info("Created Kubernetes Secret", "name", existingSecret.Name)
 	} else {
-		existingSecret.Data = secretData
+		change := diffSecretData(existingSecret.Data, kubeSecretData)
+		previousSecret := existingSecret.DeepCopy()
+		existingSecret.Data = kubeSecretData
 
 		// Apply target secret template if specified
 		r.applyTargetSecretTemplate(&aSecret, existingSecret)
+		applyImmutability(&aSecret, existingSecret)
+		setManagedKeysAnnotation(existingSecret, ownedKeys)
+		setSourceAnnotations(&aSecret, existingSecret, ownedKeys, log)
+		if r.RecordLastChange {
+			recordLastChange(existingSecret, change)
+		}
 
-		if err := r.Update(ctx, existingSecret); err != nil {
+		if err := r.applyOwnership(&aSecret, existingSecret, true); err != nil {
+			log.Error(err, "Failed to set ownership of Secret")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
+			return ctrl.Result{}, err
+		}
+
+		if requiresRecreate(&aSecret, previousSecret, existingSecret) {
+			if err := r.recreateSecret(ctx, &aSecret, previousSecret, existingSecret, log); err != nil {
+				log.Error(err, "Failed to recreate immutable Secret")
+				r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
+				return ctrl.Result{}, err
+			}
+			log.Info("Updated Kubernetes Secret", "name", existingSecret.Name,
+				"addedKeys", change.added, "changedKeys", change.changed, "removedKeys", change.removed)
+		} else if equality.Semantic.DeepEqual(previousSecret, existingSecret) {
+			// An update without changes still bumps the resourceVersion and wakes up every watcher of the Secret
+			log.V(1).Info("Kubernetes Secret up to date, skipping update", "name", existingSecret.Name)
+		} else if err := r.Update(ctx, existingSecret); err != nil {
 			log.Error(err, "Failed to update Secret")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonKubernetesError, err, log)
 			return ctrl.Result{}, err
+		} else {
+			log.Info("Updated Kubernetes Secret", "name", existingSecret.Name,
+				"addedKeys", change.added, "changedKeys", change.changed, "removedKeys", change.removed)
 		}
-		log.Info("Updated Kubernetes Secret", "name", existingSecret.Name)
+	}
+	markKubeSecretReady(&aSecret)
+
+	// The push, replica, description, KMS key and metadata steps share one DescribeSecret until one of them writes
+	if !localOnly {
+		smClient = newDescribeOnceSecretsManager(smClient, r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath))
 	}
 
 	// Update AWS secret if needed
-	if !onlyImportRemote {
-		needsUpdate := r.shouldUpdateAwsSecret(&aSecret, secretData, awsSecretData, awsSecretExists)
-		if needsUpdate {
+	if err := ctx.Err(); err != nil {
+		log.Info("Reconcile interrupted by shutdown before writing the AWS Secret")
+		return ctrl.Result{}, err
+	}
+	if localOnly {
+		log.V(1).Info("Provider is none, nothing updated on AWS Secret", "name", existingSecret.Name)
+	} else if !onlyImportRemote {
+		if needsAwsUpdate && r.ReadOnly {
+			log.Info("Read-only mode, suppressed push to AWS Secret", "path", r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath))
+		} else if needsAwsUpdate {
 			if err := r.createOrUpdateAwsSecret(ctx, smClient, &aSecret, secretData, log); err != nil {
 				log.Error(err, "Failed to create AWS Secret")
+				r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
 				return ctrl.Result{}, err
 			}
 			log.Info("Updated AWS Secret", "name", existingSecret.Name)
 		}
+
+		// Replicating, describing, re-encrypting and sharing are writes to AWS too
+		if r.ReadOnly {
+			log.V(1).Info("Read-only mode, replica regions, description, KMS key and resource policy left unchanged")
+		} else if err := r.reconcileReplicaRegions(ctx, smClient, &aSecret, log); err != nil {
+			log.Error(err, "Failed to reconcile AWS Secret replica regions")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
+			return ctrl.Result{}, err
+		} else if err := r.reconcileAwsDescription(ctx, smClient, &aSecret, log); err != nil {
+			log.Error(err, "Failed to reconcile AWS Secret description")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
+			return ctrl.Result{}, err
+		} else if err := r.reconcileAwsKmsKey(ctx, smClient, &aSecret, log); err != nil {
+			log.Error(err, "Failed to reconcile AWS Secret KMS key")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
+			return ctrl.Result{}, err
+		} else if err := r.reconcileAwsResourcePolicy(ctx, smClient, &aSecret, log); err != nil {
+			log.Error(err, "Failed to reconcile AWS Secret resource policy")
+			r.setSyncFailedCondition(ctx, &aSecret, ReasonAWSError, err, log)
+			return ctrl.Result{}, err
+		}
 	} else {
 		log.V(1).Info("OnlyImportRemote set, nothing updated on AWS Secret", "name", existingSecret.Name)
 	}
 
 	// Update status
 	aSecret.Status.LastSyncTime = metav1.Now()
-	aSecret.Status.Conditions = []metav1.Condition{
-		{
-			Type:               "Synced",
-			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
-			Reason:             "ReconciliationSucceeded",
-			Message:            "Secret successfully synced",
-		},
+	aSecret.Status.ObservedGeneration = aSecret.Generation
+	if localOnly || aSecret.Spec.AwsSecretPath == "" {
+		aSecret.Status.RemoteMetadata = nil
+	} else {
+		r.refreshRemoteMetadata(ctx, smClient, &aSecret, log)
 	}
+	recordRotationTimes(&aSecret, generatedKeys, now)
+	aSecret.Status.Summary = summarizeKeys(&aSecret, secretData).String()
+	recordForceSync(&aSecret)
+	markSynced(&aSecret, needsAwsUpdate && r.ReadOnly)
 
 	if err := r.Status().Update(ctx, &aSecret); err != nil {
 		log.Error(err, "Failed to update ASecret status")
 		return ctrl.Result{}, err
 	}
 
-	// Compute per-secret refresh interval (defaults to 1h if not set)
-	requeue := time.Hour
-	if aSecret.Spec.RefreshInterval != nil && aSecret.Spec.RefreshInterval.Duration > 0 {
-		requeue = aSecret.Spec.RefreshInterval.Duration
+	requeue := r.jitterRefresh(r.refreshInterval(&aSecret))
+	if untilRotation, ok := timeUntilNextRotation(&aSecret, now); ok && untilRotation < requeue {
+		requeue = untilRotation
 	}
 
 	return ctrl.Result{RequeueAfter: requeue}, nil
 }
 
+// wouldEmptySecret reports whether the update would remove every key of a non-empty Secret without allowEmptySecret.
+// kubeSecretData is the data written to the Secret, preserved keys included
+func wouldEmptySecret(aSecret *secretsv1alpha1.ASecret, existingSecret *corev1.Secret, kubeSecretData map[string][]byte) bool {
+	if aSecret.Spec.AllowEmptySecret != nil && *aSecret.Spec.AllowEmptySecret {
+		return false
+	}
+	return len(kubeSecretData) == 0 && len(existingSecret.Data) > 0
+}
+
+// isLocalOnly reports whether the ASecret is managed without any remote provider
+func isLocalOnly(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.Provider == "none"
+}
+
+// isImportOnly reports whether the ASecret only imports from AWS, either through onlyImportRemote
+// or because its keys are read from the separate secrets of a binaryKeyMap
+func isImportOnly(aSecret *secretsv1alpha1.ASecret) bool {
+	return (aSecret.Spec.OnlyImportRemote != nil && *aSecret.Spec.OnlyImportRemote) || len(aSecret.Spec.BinaryKeyMap) > 0 || aSecret.Spec.PathPrefix != ""
+}
+
+// isFlattenNested reports whether nested objects of the json AWS secret are mapped to dotted keys
+func isFlattenNested(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.ValueType == "json" && aSecret.Spec.FlattenNested != nil && *aSecret.Spec.FlattenNested
+}
+
+// isPreserveStringTypes reports whether scalars of the json AWS secret are always written as strings
+func isPreserveStringTypes(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.ValueType == "json" && aSecret.Spec.PreserveStringTypes != nil && *aSecret.Spec.PreserveStringTypes
+}
+
+// awsSecretPaths returns every AWS secret path the ASecret reads from
+func awsSecretPaths(aSecret *secretsv1alpha1.ASecret) []string {
+	if len(aSecret.Spec.BinaryKeyMap) == 0 {
+		return []string{aSecret.Spec.AwsSecretPath}
+	}
+
+	paths := make([]string, 0, len(aSecret.Spec.BinaryKeyMap))
+	for _, key := range sortedKeys(aSecret.Spec.BinaryKeyMap) {
+		paths = append(paths, aSecret.Spec.BinaryKeyMap[key])
+	}
+	return paths
+}
+
 // prepareSecretData handles the logic for preparing secret data from various sources
 func (r *ASecretReconciler) prepareSecretData(aSecret *secretsv1alpha1.ASecret, existingSecret *corev1.Secret, awsSecretData map[string]string, awsSecretExists, kubeSecretExists bool, log logr.Logger) map[string][]byte {
-	onlyImportRemote := aSecret.Spec.OnlyImportRemote != nil && *aSecret.Spec.OnlyImportRemote
-
-	if onlyImportRemote {
-		return r.prepareOnlyImportRemoteData(awsSecretData, awsSecretExists, log)
+	if isImportOnly(aSecret) {
+		return r.prepareOnlyImportRemoteData(aSecret, awsSecretData, awsSecretExists, log)
 	}
 
 	return r.prepareNormalMergeData(aSecret, existingSecret, awsSecretData, awsSecretExists, kubeSecretExists)
@@ -224,8 +559,45 @@ func (r *ASecretReconciler) applyTargetSecretTemplate(aSecret *secretsv1alpha1.A
 	}
 }
 
+// renderKubeSecretData returns the data written to the Kubernetes Secret, adding the dotenv key when configured
+// or collapsing the data into the JSON of renderAsSingleKey
+func (r *ASecretReconciler) renderKubeSecretData(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte) (map[string][]byte, error) {
+	if singleKey := aSecret.Spec.RenderAsSingleKey; singleKey != "" {
+		return r.renderSingleKey(aSecret, singleKey, secretData)
+	}
+
+	key := dotenvKey(aSecret)
+	if key == "" {
+		return secretData, nil
+	}
+
+	dotenv := aSecret.Spec.TargetSecretTemplate.Dotenv
+	rendered, err := utils.RenderDotenv(secretData, dotenv.Quoting, dotenv.Newlines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render dotenv key %s: %w", key, err)
+	}
+
+	kubeSecretData := make(map[string][]byte, len(secretData)+1)
+	for k, v := range secretData {
+		kubeSecretData[k] = v
+	}
+	kubeSecretData[key] = rendered
+	return kubeSecretData, nil
+}
+
+// dotenvKey returns the Kubernetes Secret key holding the dotenv output, or "" if dotenv output is disabled
+func dotenvKey(aSecret *secretsv1alpha1.ASecret) string {
+	if aSecret.Spec.TargetSecretTemplate == nil || aSecret.Spec.TargetSecretTemplate.Dotenv == nil {
+		return ""
+	}
+	if aSecret.Spec.TargetSecretTemplate.Dotenv.Key == "" {
+		return ".env"
+	}
+	return aSecret.Spec.TargetSecretTemplate.Dotenv.Key
+}
+
 // prepareOnlyImportRemoteData prepares data when onlyImportRemote is true
-func (r *ASecretReconciler) prepareOnlyImportRemoteData(awsSecretData map[string]string, awsSecretExists bool, log logr.Logger) map[string][]byte {
+func (r *ASecretReconciler) prepareOnlyImportRemoteData(aSecret *secretsv1alpha1.ASecret, awsSecretData map[string]string, awsSecretExists bool, log logr.Logger) map[string][]byte {
 	log.Info("OnlyImportRemote enabled - importing only from AWS")
 	secretData := make(map[string][]byte)
 
@@ -238,22 +610,46 @@ func (r *ASecretReconciler) prepareOnlyImportRemoteData(awsSecretData map[string
 		log.Info("No AWS secret found and OnlyImportRemote is true - creating empty secret")
 	}
 
+	// Keys absent in AWS fall back to their default, nothing is written to AWS
+	for key, dataSource := range aSecret.Spec.Data {
+		if _, exists := secretData[key]; !exists && dataSource.DefaultValue != "" {
+			secretData[key] = []byte(dataSource.DefaultValue)
+			log.V(1).Info("Using default value for key absent in AWS", "key", key)
+		}
+	}
+
 	return secretData
 }
 
-// prepareNormalMergeData prepares data with normal merging logic
+// prepareNormalMergeData prepares data with normal merging logic, the source winning over the other one
+// following the merge policy
 func (r *ASecretReconciler) prepareNormalMergeData(aSecret *secretsv1alpha1.ASecret, existingSecret *corev1.Secret, awsSecretData map[string]string, awsSecretExists, kubeSecretExists bool) map[string][]byte {
 	secretData := make(map[string][]byte)
 
-	// Start with Kubernetes secret data if it exists
+	// AWS overrides the Kubernetes Secret, unless the merge policy lets the Kubernetes Secret win
+	kubeWins := mergePolicy(aSecret) == MergePolicyKubeWins
+	if awsSecretExists && kubeWins {
+		for k, v := range awsSecretData {
+			secretData[k] = []byte(v)
+		}
+	}
+
+	// Take Kubernetes secret data if it exists, skipping derived and preserved keys
+	derivedKey := dotenvKey(aSecret)
 	if kubeSecretExists && existingSecret.Data != nil {
-		for k, v := range existingSecret.Data {
+		preserved := preservedKeys(aSecret, existingSecret, awsSecretData)
+		for k, v := range r.existingKubeSecretData(aSecret, existingSecret) {
+			if derivedKey != "" && k == derivedKey {
+				continue
+			}
+			if _, isPreserved := preserved[k]; isPreserved {
+				continue
+			}
 			secretData[k] = v
 		}
 	}
 
-	// AWS data takes precedence
-	if awsSecretExists {
+	if awsSecretExists && !kubeWins {
 		for k, v := range awsSecretData {
 			secretData[k] = []byte(v)
 		}
@@ -269,10 +665,7 @@ func (r *ASecretReconciler) prepareNormalMergeData(aSecret *secretsv1alpha1.ASec
 
 // pruneUnmanagedKeys removes keys that are no longer managed by the ASecret
 func (r *ASecretReconciler) pruneUnmanagedKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte) {
-	managedKeys := make(map[string]bool)
-	for key := range aSecret.Spec.Data {
-		managedKeys[key] = true
-	}
+	managedKeys := specKeys(aSecret)
 
 	keysToDelete := []string{}
 	for k := range secretData {
@@ -286,6 +679,33 @@ func (r *ASecretReconciler) pruneUnmanagedKeys(aSecret *secretsv1alpha1.ASecret,
 	}
 }
 
+// specKeys returns the keys the data of the ASecret defines, including the keys derived from them
+func specKeys(aSecret *secretsv1alpha1.ASecret) map[string]bool {
+	managedKeys := make(map[string]bool)
+	for key, dataSource := range aSecret.Spec.Data {
+		managedKeys[key] = true
+
+		// Keep the public key generated next to a key pair, whichever generator type this turns out to be
+		if dataSource.GeneratorRef != nil {
+			managedKeys[key+utils.PublicKeySuffix] = true
+		}
+
+		// Keep the top-level property a remoteRef reads from, so pushing back never drops it from AWS
+		if dataSource.RemoteRef != nil {
+			if root := remoteRefRoot(dataSource.RemoteRef.Property); root != "" {
+				managedKeys[root] = true
+			}
+		}
+	}
+	return managedKeys
+}
+
+// remoteRefRoot returns the top-level property name of a remoteRef property path
+func remoteRefRoot(property string) string {
+	root, _, _ := strings.Cut(strings.TrimPrefix(property, "."), ".")
+	return root
+}
+
 // shouldUpdateAwsSecret determines if AWS secret needs to be updated
 func (r *ASecretReconciler) shouldUpdateAwsSecret(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, awsSecretData map[string]string, awsSecretExists bool) bool {
 	if !awsSecretExists {
@@ -295,9 +715,21 @@ func (r *ASecretReconciler) shouldUpdateAwsSecret(aSecret *secretsv1alpha1.ASecr
 	// Prepare data for AWS update, excluding onlyImportRemote keys
 	awsUpdateData := r.filterAwsUpdateData(aSecret, secretData)
 
+	// Keys extracted through a remoteRef do not exist at the top level of the AWS secret
+	remoteData := awsSecretData
+	if hasRemoteRefs(aSecret) {
+		remoteData = make(map[string]string, len(awsSecretData))
+		for k, v := range awsSecretData {
+			if dataSource, exists := aSecret.Spec.Data[k]; exists && dataSource.RemoteRef != nil {
+				continue
+			}
+			remoteData[k] = v
+		}
+	}
+
 	// Check for differences
-	hasMissingKeys, hasExtraKeys := r.calculateKeyDifferences(awsUpdateData, awsSecretData)
-	return hasMissingKeys || hasExtraKeys
+	hasMissingKeys, hasExtraKeys := r.calculateKeyDifferences(awsUpdateData, remoteData)
+	return hasMissingKeys || hasExtraKeys || hasChangedValues(aSecret, awsUpdateData, remoteData)
 }
 
 // filterAwsUpdateData filters out keys that shouldn't be written to AWS
@@ -313,11 +745,15 @@ func (r *ASecretReconciler) filterAwsUpdateData(aSecret *secretsv1alpha1.ASecret
 
 // shouldSkipKeyForAwsUpdate checks if a key should be skipped for AWS updates
 func (r *ASecretReconciler) shouldSkipKeyForAwsUpdate(aSecret *secretsv1alpha1.ASecret, key string) bool {
-	if dataSource, exists := aSecret.Spec.Data[key]; exists &&
-		dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote {
-		return true
+	dataSource, exists := aSecret.Spec.Data[key]
+	if !exists {
+		return false
 	}
-	return false
+	// A key with a default is pushed so that the seeded value reaches AWS
+	if dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote {
+		return dataSource.DefaultValue == ""
+	}
+	return dataSource.RemoteRef != nil
 }
 
 // calculateKeyDifferences checks for missing or extra keys between local and AWS data
@@ -343,9 +779,26 @@ func (r *ASecretReconciler) calculateKeyDifferences(secretData map[string][]byte
 	return hasMissingKeys, hasExtraKeys
 }
 
+// hasChangedValues reports whether a key present on both sides holds a different value locally than in AWS
+func hasChangedValues(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, awsSecretData map[string]string) bool {
+	for k, v := range secretData {
+		if remote, exists := awsSecretData[k]; exists && !sameRemoteValue(aSecret, v, remote) {
+			return true
+		}
+	}
+	return false
+}
+
 // getAwsSecret gets a secret from AWS SecretsManager
 func (r *ASecretReconciler) getAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, secret *secretsv1alpha1.ASecret, log logr.Logger) (map[string]string, bool, error) {
-	secretID := secret.Spec.AwsSecretPath
+	if len(secret.Spec.BinaryKeyMap) > 0 {
+		return r.getMappedBinarySecrets(ctx, smClient, secret, log)
+	}
+	if secret.Spec.PathPrefix != "" {
+		return r.getPrefixedSecrets(ctx, smClient, secret, log)
+	}
+
+	secretID := r.AwsClient.ResolveSecretPath(secret.Spec.AwsSecretPath)
 	input := &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretID),
 	}
@@ -386,30 +839,187 @@ func (r *ASecretReconciler) getAwsSecret(ctx context.Context, smClient awsclient
 		return secretData, true, nil
 	}
 
-	// Handle string secrets (kv and json)
+	// Handle string secrets (kv, json and raw)
 	if result.SecretString == nil {
 		log.Error(nil, "AWS secret value is nil", "secretPath", secretID)
 		return nil, true, fmt.Errorf("secret value is nil for %s", secretID)
 	}
 
-	secretData, err := r.parseAwsSecretValue(*result.SecretString, secret.Spec.ValueType)
+	// A raw secret string is not parsed, it is stored as-is under its single key
+	if secret.Spec.ValueType == "raw" {
+		log.V(1).Info("Successfully retrieved AWS raw secret", "path", secretID, "key", rawKey(secret))
+		return map[string]string{rawKey(secret): *result.SecretString}, true, nil
+	}
+
+	// Secrets with remoteRef keys are nested documents, which only the json parser can flatten
+	valueType := secret.Spec.ValueType
+	if hasRemoteRefs(secret) {
+		valueType = "json"
+	}
+
+	secretData, err := r.parseAwsSecretValue(*result.SecretString, valueType, isFlattenNested(secret))
 	if err != nil {
 		log.Error(err, "Failed to unmarshal AWS secret", "secretPath", secretID)
 		return nil, true, err
 	}
 
+	// Keys are renamed before remoteRefs are extracted, which already use Kubernetes Secret keys
+	secretData, err = mapAwsKeys(secret, secretData)
+	if err != nil {
+		log.Error(err, "Failed to map AWS secret keys", "secretPath", secretID)
+		return nil, true, err
+	}
+
+	if err := r.extractRemoteRefs(secret, *result.SecretString, secretData, log); err != nil {
+		log.Error(err, "Failed to extract remoteRef properties", "secretPath", secretID)
+		return nil, true, err
+	}
+
 	log.V(1).Info("Successfully retrieved AWS secret", "path", secretID, "keys", len(secretData))
 	return secretData, true, nil
 }
 
+// getMappedBinarySecrets reads every key of the binaryKeyMap from the SecretBinary of its own AWS secret.
+// A missing secret only leaves its key out; the result exists as soon as one mapped secret does
+func (r *ASecretReconciler) getMappedBinarySecrets(ctx context.Context, smClient awsclient.SecretsManagerAPI, secret *secretsv1alpha1.ASecret, log logr.Logger) (map[string]string, bool, error) {
+	secretData := make(map[string]string)
+
+	for _, keyName := range sortedKeys(secret.Spec.BinaryKeyMap) {
+		secretID := r.AwsClient.ResolveSecretPath(secret.Spec.BinaryKeyMap[keyName])
+
+		log.V(1).Info("Getting AWS binary secret", "path", secretID, "key", keyName)
+		result, err := smClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretID),
+		})
+		if err != nil {
+			if _, _, err := r.handleAwsSecretError(err, secretID, log); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		if result.SecretBinary == nil {
+			log.Error(nil, "AWS secret binary value is nil", "secretPath", secretID)
+			return nil, true, fmt.Errorf("secret binary value is nil for %s", secretID)
+		}
+		secretData[keyName] = string(result.SecretBinary)
+	}
+
+	log.V(1).Info("Successfully retrieved mapped AWS binary secrets", "keys", len(secretData))
+	return secretData, len(secretData) > 0, nil
+}
+
+// extractRemoteRefs populates keys with a remoteRef from the nested properties of the raw AWS secret
+func (r *ASecretReconciler) extractRemoteRefs(secret *secretsv1alpha1.ASecret, secretString string, secretData map[string]string, log logr.Logger) error {
+	for key, dataSource := range secret.Spec.Data {
+		if dataSource.RemoteRef == nil {
+			continue
+		}
+
+		value, exists, err := utils.ExtractJSONProperty(secretString, dataSource.RemoteRef.Property)
+		if err != nil {
+			return fmt.Errorf("failed to extract property %s for key %s: %w", dataSource.RemoteRef.Property, key, err)
+		}
+		if !exists {
+			log.Info("RemoteRef property not found in AWS secret", "key", key, "property", dataSource.RemoteRef.Property)
+			continue
+		}
+		secretData[key] = value
+	}
+	return nil
+}
+
+// hasRemoteRefs reports whether any data key is extracted from a nested AWS property
+func hasRemoteRefs(aSecret *secretsv1alpha1.ASecret) bool {
+	for _, dataSource := range aSecret.Spec.Data {
+		if dataSource.RemoteRef != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAwsSecretData decompresses and then decodes in place the AWS values of keys declaring an encoding.
+// Binary values are only encoded to base64 when the ASecret sets binaryEncoding base64
+func (r *ASecretReconciler) decodeAwsSecretData(aSecret *secretsv1alpha1.ASecret, awsSecretData map[string]string) error {
+	if aSecret.Spec.ValueType == "binary" {
+		if isBase64BinaryEncoding(aSecret) {
+			encodeBinaryForKube(awsSecretData)
+		}
+		return nil
+	}
+
+	// Compressed values are recognised by their marker, so disabling compress keeps them readable
+	for key, value := range awsSecretData {
+		decompressed, err := utils.DecompressValue(value)
+		if err != nil {
+			return fmt.Errorf("failed to decompress key %s: %w", key, err)
+		}
+		awsSecretData[key] = string(decompressed)
+	}
+
+	for key, dataSource := range aSecret.Spec.Data {
+		value, exists := awsSecretData[key]
+		if !exists {
+			continue
+		}
+
+		decoded, err := utils.DecodeValue(value, dataSource.Encoding)
+		if err != nil {
+			return fmt.Errorf("failed to decode key %s as %s: %w", key, dataSource.Encoding, err)
+		}
+		awsSecretData[key] = string(decoded)
+	}
+	return nil
+}
+
+// normalizeAwsSecretData converts in place the line endings of imported text values, skipping keys declaring an encoding
+func (r *ASecretReconciler) normalizeAwsSecretData(aSecret *secretsv1alpha1.ASecret, awsSecretData map[string]string) {
+	mode := aSecret.Spec.NormalizeLineEndings
+	if aSecret.Spec.ValueType == "binary" || mode == "" || mode == utils.LineEndingsNone {
+		return
+	}
+
+	for key, value := range awsSecretData {
+		if encoding := aSecret.Spec.Data[key].Encoding; encoding != "" && encoding != utils.EncodingNone {
+			continue
+		}
+		awsSecretData[key] = utils.NormalizeLineEndings(value, mode)
+	}
+}
+
+// encodeAwsSecretData returns a copy of the data with keys declaring an encoding encoded for AWS,
+// compressing the encoded values when the ASecret sets compress
+func (r *ASecretReconciler) encodeAwsSecretData(aSecret *secretsv1alpha1.ASecret, data map[string][]byte) (map[string][]byte, error) {
+	compress := aSecret.Spec.Compress != nil && *aSecret.Spec.Compress
+	encoded := make(map[string][]byte, len(data))
+	for k, v := range data {
+		encodedValue, err := utils.EncodeValue(v, aSecret.Spec.Data[k].Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode key %s: %w", k, err)
+		}
+		if compress {
+			if encodedValue, err = utils.CompressValue(encodedValue); err != nil {
+				return nil, fmt.Errorf("failed to compress key %s: %w", k, err)
+			}
+		}
+		encoded[k] = encodedValue
+	}
+	return encoded, nil
+}
+
 // handleAwsSecretError handles errors from AWS SecretsManager operations
 func (r *ASecretReconciler) handleAwsSecretError(err error, secretID string, log logr.Logger) (map[string]string, bool, error) {
-	var resourceNotFound *smTypes.ResourceNotFoundException
-	if errors.As(err, &resourceNotFound) {
+	if errors.Is(awsclient.ClassifyError(err), yasoerrors.ErrNotFound) {
 		log.Info("AWS secret not found", "path", secretID)
 		return nil, false, nil
 	}
 
+	if awsclient.IsMarkedForDeletion(err) {
+		log.Info("AWS secret is scheduled for deletion", "path", secretID)
+		return nil, false, &awsSecretDeletingError{secretID: secretID, cause: err}
+	}
+
 	if err.Error() == "not found, ResolveEndpointV2" {
 		log.Error(err, "Failed to resolve AWS endpoint - check AWS region and endpoint configuration",
 			"secretPath", secretID,
@@ -421,14 +1031,19 @@ func (r *ASecretReconciler) handleAwsSecretError(err error, secretID string, log
 	return nil, false, err
 }
 
-// parseAwsSecretValue parses the AWS secret value based on the valueType
-func (r *ASecretReconciler) parseAwsSecretValue(secretValue, valueType string) (map[string]string, error) {
+// parseAwsSecretValue parses the AWS secret value based on the valueType.
+// With flattenNested, nested objects of a json value become dotted keys
+func (r *ASecretReconciler) parseAwsSecretValue(secretValue, valueType string, flattenNested bool) (map[string]string, error) {
 	if valueType == "json" {
 		var obj map[string]interface{}
-		if err := json.Unmarshal([]byte(secretValue), &obj); err != nil {
+		if err := unmarshalJSON([]byte(secretValue), &obj); err != nil {
 			return nil, err
 		}
 
+		if flattenNested {
+			return utils.FlattenJSON(obj)
+		}
+
 		secretData := make(map[string]string)
 		for k, v := range obj {
 			switch t := v.(type) {
@@ -455,72 +1070,145 @@ func (r *ASecretReconciler) parseAwsSecretValue(secretValue, valueType string) (
 
 // createOrUpdateAwsSecret creates or updates a secret in AWS SecretsManager
 func (r *ASecretReconciler) createOrUpdateAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, data map[string][]byte, log logr.Logger) error {
-	secretPath := aSecret.Spec.AwsSecretPath
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
 	tags := r.prepareTags(aSecret)
 
+	value, err := r.awsSecretValue(aSecret, data)
+	if err != nil {
+		return err
+	}
+	// If there's no data, this is likely an import-only scenario
+	// Don't fail, just skip AWS update
+	if !value.present {
+		log.V(1).Info("Secret has no data to push to AWS (likely import-only)", "path", secretPath, "valueType", aSecret.Spec.ValueType)
+		return nil
+	}
+
+	// Check if secret exists
+	described, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretPath),
+	})
+
+	// The describe may have outlived the grace period, never start a write that would be cut off
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	// Only a missing secret is created, creating on a throttled or denied describe would fail or hide the cause
+	exists := err == nil
+	if err != nil && !errors.Is(awsclient.ClassifyError(err), yasoerrors.ErrNotFound) {
+		return fmt.Errorf("failed to describe AWS secret %s: %w", secretPath, err)
+	}
+
 	// Handle binary secrets differently
+	if value.binary != nil {
+		if !exists {
+			err = r.createAwsSecretBinary(ctx, smClient, aSecret, value.binary, tags, log)
+		} else if err = r.updateAwsSecretBinary(ctx, smClient, aSecret, value.binary, currentVersionID(described), tags); err == nil {
+			err = r.pruneAwsTags(ctx, smClient, secretPath, described, tags, log)
+		}
+		if err != nil {
+			return err
+		}
+		return r.verifyAwsWrite(ctx, smClient, aSecret, func(output *secretsmanager.GetSecretValueOutput) bool {
+			return bytes.Equal(output.SecretBinary, value.binary)
+		}, log)
+	}
+
+	if !exists {
+		err = r.createAwsSecret(ctx, smClient, aSecret, value.str, tags, log)
+	} else if err = r.updateAwsSecret(ctx, smClient, aSecret, value.str, currentVersionID(described), tags); err == nil {
+		err = r.pruneAwsTags(ctx, smClient, secretPath, described, tags, log)
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.verifyAwsWrite(ctx, smClient, aSecret, func(output *secretsmanager.GetSecretValueOutput) bool {
+		return aws.ToString(output.SecretString) == value.str
+	}, log)
+}
+
+// awsValue is the value written to the AWS secret, binary for a binary valueType and str otherwise
+type awsValue struct {
+	str     string
+	binary  []byte
+	present bool
+}
+
+// awsSecretValue builds the value written to the AWS secret from the data, checked against --max-secret-bytes
+func (r *ASecretReconciler) awsSecretValue(aSecret *secretsv1alpha1.ASecret, data map[string][]byte) (awsValue, error) {
 	if aSecret.Spec.ValueType == "binary" {
 		// For binary type, get the single key's value
 		var secretBinary []byte
 		keyCount := 0
-		for _, v := range data {
+		for k, v := range data {
 			if keyCount > 0 {
-				return fmt.Errorf("binary secret can only have one key")
+				return awsValue{}, fmt.Errorf("binary secret can only have one key")
+			}
+			decoded, err := binaryForAws(aSecret, k, v)
+			if err != nil {
+				return awsValue{}, err
 			}
-			secretBinary = v
+			secretBinary = decoded
 			keyCount++
 		}
-
-		// If there's no data, this is likely an import-only scenario
-		// Don't fail, just skip AWS update
 		if len(secretBinary) == 0 {
-			log.V(1).Info("Binary secret has no data to push to AWS (likely import-only)", "path", secretPath)
-			return nil
+			return awsValue{}, nil
 		}
+		return awsValue{binary: secretBinary, present: true}, r.checkSecretSize(len(secretBinary))
+	}
 
-		// Check if secret exists
-		_, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
-			SecretId: aws.String(secretPath),
-		})
+	// Re-encode values declaring an encoding
+	encodedData, err := r.encodeAwsSecretData(aSecret, data)
+	if err != nil {
+		return awsValue{}, err
+	}
 
-		if err != nil {
-			return r.createAwsSecretBinary(ctx, smClient, aSecret, secretBinary, tags, log)
+	// Handle string secrets (kv, json and raw)
+	if aSecret.Spec.ValueType == "raw" {
+		secretString, present, err := rawSecretString(aSecret, encodedData)
+		if err != nil || !present {
+			return awsValue{}, err
 		}
-		return r.updateAwsSecretBinary(ctx, smClient, aSecret, secretBinary, tags)
+		return awsValue{str: secretString, present: true}, r.checkSecretSize(len(secretString))
 	}
-
-	// Check if secret exists (for non-binary secrets)
-	_, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
-		SecretId: aws.String(secretPath),
-	})
-
-	// Handle string secrets (kv and json)
-	secretString, stringErr := r.prepareAwsSecretString(data, aSecret.Spec.ValueType)
-	if stringErr != nil {
-		return stringErr
+	awsData, err := unmapKubeKeys(aSecret, encodedData)
+	if err != nil {
+		return awsValue{}, err
 	}
-
+	secretString, err := r.prepareAwsSecretString(awsData, aSecret.Spec.ValueType, isFlattenNested(aSecret), isPreserveStringTypes(aSecret))
 	if err != nil {
-		return r.createAwsSecret(ctx, smClient, aSecret, secretString, tags, log)
+		return awsValue{}, err
 	}
-
-	return r.updateAwsSecret(ctx, smClient, aSecret, secretString, tags)
+	return awsValue{str: secretString, present: true}, nil
 }
 
-// prepareAwsSecretString prepares the secret string for AWS
-func (r *ASecretReconciler) prepareAwsSecretString(data map[string][]byte, valueType string) (string, error) {
+// prepareAwsSecretString prepares the secret string for AWS.
+// With flattenNested, dotted keys of a json value are nested back into objects.
+// With preserveStringTypes, only objects and arrays are parsed, any other value is written as a string
+func (r *ASecretReconciler) prepareAwsSecretString(data map[string][]byte, valueType string, flattenNested, preserveStringTypes bool) (string, error) {
 	if valueType == "json" {
 		obj := make(map[string]interface{})
 		for k, v := range data {
 			var vObj interface{}
-			if json.Unmarshal(v, &vObj) == nil {
+			if unmarshalJSON(v, &vObj) == nil && (!preserveStringTypes || isJSONContainer(vObj)) {
 				obj[k] = vObj
 			} else {
 				obj[k] = string(v)
 			}
 		}
+		if flattenNested {
+			nested, err := utils.UnflattenJSON(obj)
+			if err != nil {
+				return "", err
+			}
+			obj = nested
+		}
 		secretString, err := json.Marshal(obj)
-		return string(secretString), err
+		if err != nil {
+			return "", err
+		}
+		return string(secretString), r.checkSecretSize(len(secretString))
 	}
 
 	// legacy: marshal as object/map
@@ -529,7 +1217,40 @@ func (r *ASecretReconciler) prepareAwsSecretString(data map[string][]byte, value
 		stringData[k] = string(v)
 	}
 	secretString, err := json.Marshal(stringData)
-	return string(secretString), err
+	if err != nil {
+		return "", err
+	}
+	return string(secretString), r.checkSecretSize(len(secretString))
+}
+
+// isJSONContainer reports whether the parsed JSON value is an object or an array
+func isJSONContainer(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// rawKey returns the Kubernetes Secret key holding the SecretString of a raw secret
+func rawKey(aSecret *secretsv1alpha1.ASecret) string {
+	if aSecret.Spec.RawKey != "" {
+		return aSecret.Spec.RawKey
+	}
+	return "value"
+}
+
+// rawSecretString returns the value of the raw key as the SecretString, reporting false when it is not in the data.
+// A raw secret holds a single string, so any other key is an error
+func rawSecretString(aSecret *secretsv1alpha1.ASecret, data map[string][]byte) (string, bool, error) {
+	key := rawKey(aSecret)
+	for k := range data {
+		if k != key {
+			return "", false, fmt.Errorf("raw secret can only hold key %q, found %q", key, k)
+		}
+	}
+	value, present := data[key]
+	return string(value), present, nil
 }
 
 // prepareTags prepares AWS tags from config and ASecret spec
@@ -559,11 +1280,18 @@ func (r *ASecretReconciler) prepareTags(aSecret *secretsv1alpha1.ASecret) []smTy
 
 // createAwsSecret creates a new AWS secret
 func (r *ASecretReconciler) createAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretString string, tags []smTypes.Tag, log logr.Logger) error {
-	secretPath := aSecret.Spec.AwsSecretPath
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	if awsclient.IsARN(secretPath) {
+		return fmt.Errorf("cannot create AWS secret referenced by ARN %s, it must already exist", secretPath)
+	}
 	createInput := &secretsmanager.CreateSecretInput{
-		Name:         aws.String(secretPath),
-		SecretString: aws.String(secretString),
-		Tags:         tags,
+		Name:                        aws.String(secretPath),
+		ClientRequestToken:          aws.String(awsclient.ClientRequestToken(secretPath, []byte(secretString))),
+		SecretString:                aws.String(secretString),
+		Description:                 aws.String(awsSecretDescription(aSecret)),
+		Tags:                        tags,
+		AddReplicaRegions:           replicaRegionTypes(aSecret.Spec.ReplicaRegions),
+		ForceOverwriteReplicaSecret: isForceOverwriteReplica(aSecret),
 	}
 
 	// Determine KMS key
@@ -578,14 +1306,15 @@ func (r *ASecretReconciler) createAwsSecret(ctx context.Context, smClient awscli
 	return err
 }
 
-// updateAwsSecret updates an existing AWS secret
-func (r *ASecretReconciler) updateAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretString string, tags []smTypes.Tag) error {
-	secretPath := aSecret.Spec.AwsSecretPath
+// updateAwsSecret updates an existing AWS secret, replacing its currentVersion
+func (r *ASecretReconciler) updateAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretString, currentVersion string, tags []smTypes.Tag) error {
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
 
 	// Update secret value
 	_, err := smClient.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
-		SecretId:     aws.String(secretPath),
-		SecretString: aws.String(secretString),
+		SecretId:           aws.String(secretPath),
+		ClientRequestToken: aws.String(awsclient.PutSecretValueToken(secretPath, currentVersion, []byte(secretString))),
+		SecretString:       aws.String(secretString),
 	})
 
 	// Update tags if no error and tags exist
@@ -599,13 +1328,30 @@ func (r *ASecretReconciler) updateAwsSecret(ctx context.Context, smClient awscli
 	return err
 }
 
+// currentVersionID returns the id of the AWSCURRENT version of a described secret, empty when it has none
+func currentVersionID(described *secretsmanager.DescribeSecretOutput) string {
+	for versionID, stages := range described.VersionIdsToStages {
+		if slices.Contains(stages, "AWSCURRENT") {
+			return versionID
+		}
+	}
+	return ""
+}
+
 // createAwsSecretBinary creates a new AWS secret with binary data
 func (r *ASecretReconciler) createAwsSecretBinary(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretBinary []byte, tags []smTypes.Tag, log logr.Logger) error {
-	secretPath := aSecret.Spec.AwsSecretPath
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	if awsclient.IsARN(secretPath) {
+		return fmt.Errorf("cannot create AWS secret referenced by ARN %s, it must already exist", secretPath)
+	}
 	createInput := &secretsmanager.CreateSecretInput{
-		Name:         aws.String(secretPath),
-		SecretBinary: secretBinary,
-		Tags:         tags,
+		Name:                        aws.String(secretPath),
+		ClientRequestToken:          aws.String(awsclient.ClientRequestToken(secretPath, secretBinary)),
+		SecretBinary:                secretBinary,
+		Description:                 aws.String(awsSecretDescription(aSecret)),
+		Tags:                        tags,
+		AddReplicaRegions:           replicaRegionTypes(aSecret.Spec.ReplicaRegions),
+		ForceOverwriteReplicaSecret: isForceOverwriteReplica(aSecret),
 	}
 
 	// Determine KMS key
@@ -620,14 +1366,15 @@ func (r *ASecretReconciler) createAwsSecretBinary(ctx context.Context, smClient
 	return err
 }
 
-// updateAwsSecretBinary updates an existing AWS secret with binary data
-func (r *ASecretReconciler) updateAwsSecretBinary(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretBinary []byte, tags []smTypes.Tag) error {
-	secretPath := aSecret.Spec.AwsSecretPath
+// updateAwsSecretBinary updates an existing AWS secret with binary data, replacing its currentVersion
+func (r *ASecretReconciler) updateAwsSecretBinary(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, secretBinary []byte, currentVersion string, tags []smTypes.Tag) error {
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
 
 	// Update secret value
 	_, err := smClient.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
-		SecretId:     aws.String(secretPath),
-		SecretBinary: secretBinary,
+		SecretId:           aws.String(secretPath),
+		ClientRequestToken: aws.String(awsclient.PutSecretValueToken(secretPath, currentVersion, secretBinary)),
+		SecretBinary:       secretBinary,
 	})
 
 	// Update tags if no error and tags exist
@@ -641,6 +1388,46 @@ func (r *ASecretReconciler) updateAwsSecretBinary(ctx context.Context, smClient
 	return err
 }
 
+// pruneAwsTags removes the tags of an updated AWS secret that are no longer desired, when --prune-aws-tags is set.
+// Tags reserved by AWS, prefixed with "aws:", cannot be removed and are left alone
+func (r *ASecretReconciler) pruneAwsTags(ctx context.Context, smClient awsclient.SecretsManagerAPI, secretPath string, described *secretsmanager.DescribeSecretOutput, tags []smTypes.Tag, log logr.Logger) error {
+	if !r.AwsClient.Config.PruneTags || described == nil {
+		return nil
+	}
+
+	removed := staleTagKeys(described.Tags, tags)
+	if len(removed) == 0 {
+		return nil
+	}
+
+	if _, err := smClient.UntagResource(ctx, &secretsmanager.UntagResourceInput{
+		SecretId: aws.String(secretPath),
+		TagKeys:  removed,
+	}); err != nil {
+		return fmt.Errorf("failed to remove tags %v from AWS secret %s: %w", removed, secretPath, err)
+	}
+	log.Info("Removed stale AWS secret tags", "path", secretPath, "tags", removed)
+	return nil
+}
+
+// staleTagKeys returns the sorted keys of the existing tags missing from the desired ones
+func staleTagKeys(existing, desired []smTypes.Tag) []string {
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, tag := range desired {
+		desiredKeys[aws.ToString(tag.Key)] = true
+	}
+
+	var removed []string
+	for _, tag := range existing {
+		key := aws.ToString(tag.Key)
+		if !desiredKeys[key] && !strings.HasPrefix(key, "aws:") {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
 // determineKmsKey determines which KMS key to use
 func (r *ASecretReconciler) determineKmsKey(aSecret *secretsv1alpha1.ASecret, log logr.Logger, secretPath string) string {
 	if aSecret.Spec.KmsKeyId != "" {
@@ -659,18 +1446,62 @@ func (r *ASecretReconciler) determineKmsKey(aSecret *secretsv1alpha1.ASecret, lo
 // processASecretData processes the data from the ASecret, generating values as needed
 func (r *ASecretReconciler) processASecretData(ctx context.Context, aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, log logr.Logger) error {
 	for key, dataSource := range aSecret.Spec.Data {
+		// Stop between keys on shutdown or timeout, rather than reading generators for a result nobody waits for
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		if dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote {
+			// A key absent in AWS is seeded with its default, the remote value wins once it exists
+			if _, exists := secretData[key]; !exists && dataSource.DefaultValue != "" {
+				secretData[key] = []byte(dataSource.DefaultValue)
+				log.V(1).Info("Seeded onlyImportRemote key with its default value", "key", key)
+				continue
+			}
 			log.V(1).Info("Skipping key with onlyImportRemote=true", "key", key)
 			continue
 		}
 
-		if _, exists := secretData[key]; exists {
+		if dataSource.RemoteRef != nil {
+			log.V(1).Info("Skipping key with remoteRef", "key", key, "property", dataSource.RemoteRef.Property)
+			continue
+		}
+
+		// ConfigMap values are resolved on every reconcile so the key follows the ConfigMap
+		if dataSource.ConfigMapRef != nil {
+			value, err := r.configMapValue(ctx, aSecret, dataSource.ConfigMapRef, log)
+			if err != nil {
+				return err
+			}
+			secretData[key] = value
+			continue
+		}
+
+		if dataSource.SecretRef != nil {
+			value, err := r.secretRefValue(ctx, aSecret, dataSource.SecretRef, log)
+			if err != nil {
+				return err
+			}
+			secretData[key] = value
+			continue
+		}
+
+		// Other sources win over values unless the merge policy lets the spec win
+		if _, exists := secretData[key]; exists && (dataSource.Value == "" || mergePolicy(aSecret) != MergePolicySpecWins) {
 			continue
 		}
 
 		if dataSource.Value != "" {
-			// For binary secrets, decode base64-encoded values
+			// For binary secrets, decode base64-encoded values unless the Secret keeps them as base64
 			if aSecret.Spec.ValueType == "binary" {
+				if isBase64BinaryEncoding(aSecret) {
+					if _, err := base64.StdEncoding.DecodeString(dataSource.Value); err != nil {
+						log.Error(err, "Invalid base64 for binary secret key", "key", key)
+						return fmt.Errorf("failed to decode base64 for binary secret key %s: %w", key, err)
+					}
+					secretData[key] = []byte(dataSource.Value)
+					continue
+				}
 				decoded, err := base64.StdEncoding.DecodeString(dataSource.Value)
 				if err != nil {
 					log.Error(err, "Failed to decode base64 for binary secret key", "key", key)
@@ -685,44 +1516,149 @@ func (r *ASecretReconciler) processASecretData(ctx context.Context, aSecret *sec
 		}
 
 		if dataSource.GeneratorRef != nil {
-			generatedValue, err := r.generateValue(ctx, dataSource.GeneratorRef.Name, log)
+			generatedValues, err := r.generateValues(ctx, aSecret, key, dataSource.GeneratorRef, log)
 			if err != nil {
 				return err
 			}
-			secretData[key] = []byte(generatedValue)
+			for k, v := range generatedValues {
+				secretData[k] = v
+			}
+			continue
+		}
+
+		if len(dataSource.GeneratorRefs) > 0 {
+			value, err := r.generateCompositeValue(ctx, aSecret, key, dataSource.GeneratorRefs, log)
+			if err != nil {
+				return err
+			}
+			secretData[key] = value
 			continue
 		}
 	}
 	return nil
 }
 
-// generateValue generates a value using the specified generator
-func (r *ASecretReconciler) generateValue(ctx context.Context, generatorName string, log logr.Logger) (string, error) {
+// generateValues generates the values of the key using the specified generator.
+// Key pair generators also produce the public key, stored under the key with a ".pub" suffix
+func (r *ASecretReconciler) generateValues(ctx context.Context, aSecret *secretsv1alpha1.ASecret, key string, ref *secretsv1alpha1.GeneratorReference, log logr.Logger) (map[string][]byte, error) {
+	generatorName := ref.Name
+
+	// Without the AGenerator RBAC, reading the generator would only wait on a cache that never syncs
+	if r.GeneratorsDisabled {
+		return nil, &generatorError{key: key, generator: generatorName, cause: errGeneratorsDisabled}
+	}
+
+	// Generators are always read as the v1alpha1 hub. v1alpha1 is the only version today; once a second one is
+	// convertible, other stored versions are only converted through the operator's own /convert webhook, which
+	// is registered with --enable-webhooks alone (off by default)
 	var generator secretsv1alpha1.AGenerator
 	if err := r.Get(ctx, k8sTypes.NamespacedName{Name: generatorName}, &generator); err != nil {
 		log.Error(err, "Failed to get generator", "name", generatorName)
-		return "", err
+		if apierrors.IsNotFound(err) {
+			return nil, &generatorError{key: key, generator: generatorName, cause: err}
+		}
+		return nil, err
+	}
+
+	// Overrides only apply to the copy read for this key, the AGenerator itself is left alone
+	if hasGeneratorOverrides(ref) {
+		generator.Spec = applyGeneratorOverrides(generator.Spec, ref)
+		if err := utils.ValidateGeneratorSpec(generator.Spec); err != nil {
+			return nil, &generatorError{key: key, generator: generatorName, cause: fmt.Errorf("invalid overrides: %w", err)}
+		}
+	}
+
+	if generator.Spec.Type == utils.GeneratorTypeKeyPair {
+		privateKey, publicKey, err := utils.GenerateKeyPair(generator.Spec.KeyPair)
+		if err != nil {
+			return nil, &generatorError{key: key, generator: generatorName, cause: err}
+		}
+		return map[string][]byte{
+			key:                         []byte(privateKey),
+			key + utils.PublicKeySuffix: []byte(publicKey),
+		}, nil
+	}
+
+	if generator.Spec.Deterministic {
+		value, err := r.generateDeterministicValue(ctx, aSecret, key, &generator)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{key: value}, nil
 	}
 
-	value, err := utils.GenerateRandomString(generator.Spec)
+	value, err := utils.GenerateValue(generator.Spec)
 	if err != nil {
-		return "", err
+		return nil, &generatorError{key: key, generator: generatorName, cause: err}
 	}
 
-	return value, nil
+	return map[string][]byte{key: []byte(value)}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *ASecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	var err error
-	ctx := context.Background()
-	r.SecretsManager, err = r.AwsClient.CreateSecretsManagerClient(ctx, r.Log)
+// controllerOptions returns the controller options derived from the reconciler settings
+func (r *ASecretReconciler) controllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}
+}
+
+// SetupSecretsManagers creates the AWS SecretsManager clients the reconciler reads and writes through,
+// for a reconciler that is not set up with a manager
+func (r *ASecretReconciler) SetupSecretsManagers(ctx context.Context) error {
+	smClient, err := r.AwsClient.CreateSecretsManagerClient(ctx, r.Log)
 	if err != nil {
 		return fmt.Errorf("failed to create AWS SecretsManager client: %w", err)
 	}
+	// Secrets referenced by an ARN of another region are served by a client of that region
+	arnRegional := r.AwsClient.CreateARNRegionSecretsManager(smClient, r.Log)
+	regional, err := r.AwsClient.CreateRegionFallbackSecretsManager(ctx, arnRegional, r.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS SecretsManager fallback region clients: %w", err)
+	}
+
+	// Cache hits are served before the rate limiter so they do not consume the request budget, while every
+	// retry of a throttled call does
+	rateLimited := awsclient.NewRateLimitedSecretsManager(regional, r.AwsClient.Config.RateLimit, r.AwsClient.Config.RateBurst)
+	retrying := awsclient.NewRetryingSecretsManager(rateLimited, r.AwsClient.Config.ThrottleRetries, r.AwsClient.Config.ThrottleRetryDelay)
+	r.SecretsManager = awsclient.NewCachingSecretsManager(retrying, r.AwsClient.Config.SecretCacheTTL)
+	r.EndpointSecretsManagers = r.AwsClient.CreateEndpointSecretsManagers(r.Log)
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ASecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.SetupSecretsManagers(context.Background()); err != nil {
+		return err
+	}
+
+	// Only spec edits, toggling the paused annotation, a new force-sync value and, when enabled, periodic
+	// resyncs trigger a reconcile of the ASecret, its own status updates do not
+	asecretPredicates := []predicate.Predicate{predicate.GenerationChangedPredicate{}, pausedChangedPredicate(), forceSyncChangedPredicate()}
+	if r.GlobalResyncPeriod > 0 {
+		asecretPredicates = append(asecretPredicates, resyncPredicate())
+	}
+	asecretPredicate := predicate.Or(asecretPredicates...)
+	if r.ObjectLabelSelector != nil {
+		asecretPredicate = predicate.And(objectLabelSelectorPredicate(r.ObjectLabelSelector), asecretPredicate)
+	}
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&secretsv1alpha1.ASecret{}, builder.WithPredicates(asecretPredicate)).
+		WithOptions(r.controllerOptions())
+
+	// Secrets created without an owner reference are mapped back through their managed-by annotation.
+	// Poll mode skips both watches and leaves Secret drift to the next refresh
+	if r.watchesSecrets() {
+		controllerBuilder = controllerBuilder.
+			Owns(&corev1.Secret{}).
+			Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(managedByRequests))
+	} else {
+		r.Log.Info("Poll reconcile mode, changes to managed Secrets are repaired on the next refresh")
+	}
+
+	if r.StartupSweepSpread > 0 {
+		controllerBuilder = controllerBuilder.WatchesRawSource(r.startupSweepSource())
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.ASecret{}).
-		Owns(&corev1.Secret{}).
-		Complete(r)
+	return controllerBuilder.Complete(r)
 }
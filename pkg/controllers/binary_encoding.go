@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// BinaryEncodingBase64 keeps the values of a binary secret as base64 text in the Kubernetes Secret
+const BinaryEncodingBase64 = "base64"
+
+// isBase64BinaryEncoding reports whether the Kubernetes Secret holds the SecretBinary as base64 text
+func isBase64BinaryEncoding(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.ValueType == "binary" && aSecret.Spec.BinaryEncoding == BinaryEncodingBase64
+}
+
+// encodeBinaryForKube replaces in place the SecretBinary bytes read from AWS with their base64 text
+func encodeBinaryForKube(awsSecretData map[string]string) {
+	for key, value := range awsSecretData {
+		awsSecretData[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+}
+
+// binaryForAws returns the SecretBinary to write for the Kubernetes Secret value of a binary key
+func binaryForAws(aSecret *secretsv1alpha1.ASecret, key string, value []byte) ([]byte, error) {
+	if !isBase64BinaryEncoding(aSecret) {
+		return value, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 for binary secret key %s: %w", key, err)
+	}
+	return decoded, nil
+}
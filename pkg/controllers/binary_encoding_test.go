@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestBinaryEncodingRoundTrip(t *testing.T) {
+	der := []byte{0x30, 0x82, 0x01, 0x0a, 0x00, 0xff}
+	tests := []struct {
+		name     string
+		encoding string
+		expected []byte
+	}{
+		{name: "raw keeps the bytes", encoding: "", expected: der},
+		{name: "base64 keeps base64 text", encoding: "base64", expected: []byte(base64.StdEncoding.EncodeToString(der))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "cert-secret",
+					AwsSecretPath:    "/cert",
+					ValueType:        "binary",
+					BinaryEncoding:   tt.encoding,
+					OnlyImportRemote: boolPtr(true),
+					Data: map[string]secretsv1alpha1.DataSource{
+						"tls.crt": {},
+					},
+				},
+			}
+
+			// The imported value matches the AWS secret once encoded, so nothing is pushed back
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{SecretBinary: der}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "cert", Namespace: "default"}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+			_, err = r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "cert-secret", Namespace: "default"}, &secret))
+			assert.Equal(t, tt.expected, secret.Data["tls.crt"])
+			mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBinaryEncodingBase64Value(t *testing.T) {
+	der := []byte{0x30, 0x82, 0x01, 0x0a, 0x00, 0xff}
+	encoded := base64.StdEncoding.EncodeToString(der)
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "cert-secret",
+			AwsSecretPath:    "/cert",
+			ValueType:        "binary",
+			BinaryEncoding:   "base64",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"tls.crt": {Value: encoded},
+			},
+		},
+	}
+	notFound := &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+
+	// The Secret keeps the base64 text while AWS receives the decoded bytes
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, notFound)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, notFound).Once()
+	mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+		return bytes.Equal(input.SecretBinary, der)
+	})).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "cert", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "cert-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, []byte(encoded), secret.Data["tls.crt"])
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// unmarshalJSON decodes a single JSON value, keeping numbers as json.Number so they encode back
+// to their original text instead of going through float64, e.g. 12345678901234567890 or 1e3
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// Trailing data makes the value invalid, as it does for json.Unmarshal
+	if _, err := decoder.Token(); err != io.EOF {
+		return &json.SyntaxError{Offset: decoder.InputOffset()}
+	}
+	return nil
+}
+
+// canonicalJSON returns the canonical encoding of a JSON value, with object keys sorted at every level
+// and no insignificant whitespace, reporting false when the value is not JSON
+func canonicalJSON(value []byte) (string, bool) {
+	var parsed interface{}
+	if unmarshalJSON(value, &parsed) != nil {
+		return "", false
+	}
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return "", false
+	}
+	return string(canonical), true
+}
+
+// sameRemoteValue reports whether a value pushed to AWS equals the one stored there. Nested values of a
+// json secret are read back re-encoded, so they are compared in canonical form rather than byte for byte
+func sameRemoteValue(aSecret *secretsv1alpha1.ASecret, local []byte, remote string) bool {
+	if string(local) == remote {
+		return true
+	}
+	if aSecret.Spec.ValueType != "json" {
+		return false
+	}
+	localCanonical, ok := canonicalJSON(local)
+	if !ok {
+		return false
+	}
+	remoteCanonical, ok := canonicalJSON([]byte(remote))
+	return ok && localCanonical == remoteCanonical
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestPrepareAwsSecretStringIsCanonical(t *testing.T) {
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"id":       []byte("12345678901234567890"),
+		"ratio":    []byte("1.50"),
+		"config":   []byte(`{"zone": "b", "hosts": [{"port": 5432, "name": "db"}], "alpha": {"z": 1, "a": 2}}`),
+		"db.host":  []byte("localhost"),
+		"db.port":  []byte("5432"),
+	}
+	expected := `{"config":{"alpha":{"a":2,"z":1},"hosts":[{"name":"db","port":5432}],"zone":"b"},` +
+		`"db.host":"localhost","db.port":5432,"id":12345678901234567890,"ratio":1.50,"username":"admin"}`
+
+	r := &ASecretReconciler{}
+	for range 50 {
+		secretString, err := r.prepareAwsSecretString(data, "json", false, false)
+		require.NoError(t, err)
+		require.Equal(t, expected, secretString)
+	}
+
+	// Reading the pushed value back yields data that pushes byte-identical again
+	imported, err := r.parseAwsSecretValue(expected, "json", false)
+	require.NoError(t, err)
+	reimported := make(map[string][]byte, len(imported))
+	for k, v := range imported {
+		reimported[k] = []byte(v)
+	}
+	secretString, err := r.prepareAwsSecretString(reimported, "json", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, secretString)
+}
+
+func TestSameRemoteValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		valueType string
+		local     string
+		remote    string
+		expected  bool
+	}{
+		{name: "identical values", valueType: "json", local: "secret", remote: "secret", expected: true},
+		{name: "different strings", valueType: "json", local: "secret", remote: "other", expected: false},
+		{name: "reordered object", valueType: "json", local: `{"b": 1, "a": [true, null]}`, remote: `{"a":[true,null],"b":1}`, expected: true},
+		{name: "changed object", valueType: "json", local: `{"b": 1, "a": 2}`, remote: `{"a":2,"b":3}`, expected: false},
+		{name: "large number keeps its precision", valueType: "json", local: "12345678901234567891", remote: "12345678901234567890", expected: false},
+		{name: "kv values are compared as is", valueType: "kv", local: `{"b": 1, "a": 2}`, remote: `{"a":2,"b":1}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{ValueType: tt.valueType}}
+			assert.Equal(t, tt.expected, sameRemoteValue(aSecret, []byte(tt.local), tt.remote))
+		})
+	}
+}
+
+func TestDivergedRemoteKeysIgnoresReencodedJSON(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{
+			ValueType:   "json",
+			MergePolicy: MergePolicyKubeWins,
+			Data:        map[string]secretsv1alpha1.DataSource{"config": {}, "token": {}},
+		},
+	}
+	secretData := map[string][]byte{
+		"config": []byte(`{"timeout": 30, "host": "db"}`),
+		"token":  []byte("new"),
+	}
+	awsSecretData := map[string]string{
+		"config": `{"host":"db","timeout":30}`,
+		"token":  "old",
+	}
+
+	r := &ASecretReconciler{}
+	assert.Equal(t, []string{"token"}, r.divergedRemoteKeys(aSecret, secretData, awsSecretData))
+}
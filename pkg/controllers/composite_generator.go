@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// errKeyPairSegment is the cause of a generatorError when a key pair generator is a segment of a composite value
+var errKeyPairSegment = errors.New("a key pair generator produces two values and cannot be a segment of generatorRefs")
+
+// hasGenerator reports whether the value of the data source is generated, by one AGenerator or several segments
+func hasGenerator(dataSource secretsv1alpha1.DataSource) bool {
+	return dataSource.GeneratorRef != nil || len(dataSource.GeneratorRefs) > 0
+}
+
+// segmentKey names a segment of a composite key, e.g. token[1]. Errors name it, and deterministic generators
+// derive each segment from it, so two segments of the same generator still differ
+func segmentKey(key string, index int) string {
+	return fmt.Sprintf("%s[%d]", key, index)
+}
+
+// generateCompositeValue generates every segment of the key and joins them in order, each followed by its separator
+func (r *ASecretReconciler) generateCompositeValue(ctx context.Context, aSecret *secretsv1alpha1.ASecret, key string, segments []secretsv1alpha1.GeneratorSegment, log logr.Logger) ([]byte, error) {
+	var value []byte
+	for i := range segments {
+		segment := &segments[i]
+		name := segmentKey(key, i)
+		generated, err := r.generateValues(ctx, aSecret, name, &segment.GeneratorReference, log)
+		if err != nil {
+			return nil, err
+		}
+		if len(generated) != 1 {
+			return nil, &generatorError{key: name, generator: segment.Name, cause: errKeyPairSegment}
+		}
+
+		value = append(value, generated[name]...)
+		if i < len(segments)-1 {
+			value = append(value, segment.Separator...)
+		}
+	}
+	return value, nil
+}
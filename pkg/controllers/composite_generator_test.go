@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
+)
+
+func TestReconcileCompositeGeneratedValue(t *testing.T) {
+	prefixGenerator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefix-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 4, IncludeUppercase: true},
+	}
+	tokenGenerator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "token-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 32, IncludeLowercase: true, IncludeNumbers: true},
+	}
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "composite", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "composite-secret",
+			AwsSecretPath:    "/composite",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"apiKey": {GeneratorRefs: []secretsv1alpha1.GeneratorSegment{
+					{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "prefix-generator"}, Separator: "_"},
+					{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "token-generator"}},
+				}},
+				"serial": {GeneratorRefs: []secretsv1alpha1.GeneratorSegment{
+					{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "prefix-generator"}, Separator: "-"},
+					{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "token-generator", Length: intPtr(6)}, Separator: "-"},
+					{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "prefix-generator"}},
+				}},
+			},
+		},
+	}
+	notFound := &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, notFound)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, notFound).Once()
+	mockClient.On("CreateSecret", mock.Anything, mock.Anything).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, prefixGenerator, tokenGenerator)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "composite", Namespace: "default"}}
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "composite-secret", Namespace: "default"}, &secret))
+
+	prefix, token, found := strings.Cut(string(secret.Data["apiKey"]), "_")
+	require.True(t, found, "the separator joins the two segments")
+	assert.Len(t, prefix, 4)
+	assert.Empty(t, strings.Trim(prefix, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), "the prefix only holds uppercase letters")
+	assert.Len(t, token, 32)
+
+	segments := strings.Split(string(secret.Data["serial"]), "-")
+	require.Len(t, segments, 3)
+	assert.Len(t, segments[0], 4)
+	assert.Len(t, segments[1], 6, "the override of a segment only applies to that segment")
+	assert.Len(t, segments[2], 4)
+	assert.Equal(t, SourceGenerator, secret.Annotations[SourceAnnotationPrefix+"apiKey"])
+}
+
+func TestGenerateCompositeValueRejectsKeyPair(t *testing.T) {
+	prefixGenerator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefix-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 4, IncludeUppercase: true},
+	}
+	keyPairGenerator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Type: utils.GeneratorTypeKeyPair},
+	}
+	r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, prefixGenerator, keyPairGenerator)
+
+	segments := []secretsv1alpha1.GeneratorSegment{
+		{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "prefix-generator"}},
+		{GeneratorReference: secretsv1alpha1.GeneratorReference{Name: "ssh-generator"}},
+	}
+	_, err := r.generateCompositeValue(context.Background(), &secretsv1alpha1.ASecret{}, "key", segments, r.Log)
+
+	var genErr *generatorError
+	require.ErrorAs(t, err, &genErr)
+	assert.Equal(t, "key[1]", genErr.key)
+	assert.ErrorIs(t, err, errKeyPairSegment)
+}
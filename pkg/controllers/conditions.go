@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeSynced reports whether the last reconciliation succeeded
+	ConditionTypeSynced = "Synced"
+	// ConditionTypeAwsUnavailable reports that AWS SecretsManager could not be reached
+	ConditionTypeAwsUnavailable = "AwsUnavailable"
+	// ConditionTypeWouldEmptySecret reports that an update to an empty Kubernetes Secret was refused
+	ConditionTypeWouldEmptySecret = "WouldEmptySecret"
+	// ConditionTypeDryRun reports the changes a dry-run reconcile would have applied
+	ConditionTypeDryRun = "DryRun"
+	// ConditionTypeConflict reports that the target Secret is managed by another ASecret
+	ConditionTypeConflict = "Conflict"
+	// ConditionTypePaused reports that reconciliation is suspended by the paused annotation
+	ConditionTypePaused = "Paused"
+	// ConditionTypeGeneratorError reports that a referenced AGenerator is missing or invalid
+	ConditionTypeGeneratorError = "GeneratorError"
+	// ConditionTypeAWSSecretDeleting reports that the AWS secret is scheduled for deletion
+	ConditionTypeAWSSecretDeleting = "AWSSecretDeleting"
+	// ConditionTypeRemotePathConflict reports that the AWS secret is written by another ASecret
+	ConditionTypeRemotePathConflict = "RemotePathConflict"
+	// ConditionTypeSecretTooLarge reports that the value to write to AWS exceeds --max-secret-bytes
+	ConditionTypeSecretTooLarge = "SecretTooLarge"
+	// ConditionTypeKubeSecretReady reports whether the Kubernetes Secret was written, independently of AWS
+	ConditionTypeKubeSecretReady = "KubeSecretReady"
+	// ConditionTypeRemoteSecretReady reports whether the AWS secret was read and written, independently of Kubernetes
+	ConditionTypeRemoteSecretReady = "RemoteSecretReady"
+)
+
+// ReasonPushSuppressedReadOnly means the AWS secret was read but the values it lacks were not pushed, with --read-only
+const ReasonPushSuppressedReadOnly = "PushSuppressedReadOnly"
+
+// Reasons of a Synced=False condition, one per failure path of the reconcile
+const (
+	// ReasonAWSError means AWS SecretsManager could not be read or written
+	ReasonAWSError = "AWSError"
+	// ReasonAccountMismatch means the secret ARN belongs to another account than the assumed role
+	ReasonAccountMismatch = "AccountMismatch"
+	// ReasonDecodeFailed means a value read from AWS could not be decoded
+	ReasonDecodeFailed = "DecodeFailed"
+	// ReasonGeneratorMissing means a referenced AGenerator does not exist
+	ReasonGeneratorMissing = "GeneratorMissing"
+	// ReasonConfigMapMissing means a ConfigMap or ConfigMap key referenced by configMapRef does not exist
+	ReasonConfigMapMissing = "ConfigMapMissing"
+	// ReasonSecretMissing means a Secret or Secret key referenced by secretRef does not exist
+	ReasonSecretMissing = "SecretMissing"
+	// ReasonGeneratorsDisabled means a data key needs a generated value but the AGenerator controller is disabled
+	ReasonGeneratorsDisabled = "GeneratorsDisabled"
+	// ReasonInvalidSpec means the ASecret spec is ambiguous, e.g. a data key with several value sources, or
+	// sets an endpointURL the operator does not allow
+	ReasonInvalidSpec = "InvalidSpec"
+	// ReasonInvalidTargetName means targetSecretName is not a valid Kubernetes Secret name
+	ReasonInvalidTargetName = "InvalidTargetName"
+	// ReasonInvalidData means a data source value could not be produced, e.g. by an invalid AGenerator
+	ReasonInvalidData = "InvalidData"
+	// ReasonTemplateError means the target Secret could not be rendered from its template
+	ReasonTemplateError = "TemplateError"
+	// ReasonKubernetesError means the Kubernetes Secret could not be read or written
+	ReasonKubernetesError = "KubernetesError"
+	// ReasonWouldEmptySecret means the update was refused because it would empty the Secret
+	ReasonWouldEmptySecret = "WouldEmptySecret"
+	// ReasonConflict means the target Secret is managed by another ASecret
+	ReasonConflict = "Conflict"
+	// ReasonAWSSecretDeleting means the AWS secret is scheduled for deletion and was not restored
+	ReasonAWSSecretDeleting = "AWSSecretDeleting"
+	// ReasonRemotePathConflict means the AWS secret is written by another ASecret, with --detect-remote-conflicts
+	ReasonRemotePathConflict = "RemotePathConflict"
+	// ReasonSecretTooLarge means the value to write to AWS exceeds --max-secret-bytes
+	ReasonSecretTooLarge = "SecretTooLarge"
+)
+
+// remoteFailureReasons are the Synced=False reasons of a failure on the AWS side, which set RemoteSecretReady=False
+var remoteFailureReasons = []string{
+	ReasonAWSError,
+	ReasonAccountMismatch,
+	ReasonDecodeFailed,
+	ReasonAWSSecretDeleting,
+	ReasonRemotePathConflict,
+	ReasonSecretTooLarge,
+}
+
+// kubeFailureReasons are the Synced=False reasons of a failure on the Kubernetes side, which set KubeSecretReady=False.
+// Other reasons, such as an invalid spec or a missing AGenerator, stop the reconcile before either side is written
+var kubeFailureReasons = []string{
+	ReasonInvalidTargetName,
+	ReasonTemplateError,
+	ReasonKubernetesError,
+	ReasonWouldEmptySecret,
+	ReasonConflict,
+}
+
+// failureConditionTypes only describe an ongoing failure and are removed once it is resolved
+var failureConditionTypes = []string{
+	ConditionTypeAwsUnavailable,
+	ConditionTypeWouldEmptySecret,
+	ConditionTypeConflict,
+	ConditionTypeGeneratorError,
+	ConditionTypeAWSSecretDeleting,
+	ConditionTypeRemotePathConflict,
+	ConditionTypeSecretTooLarge,
+}
+
+// markSynced records a successful reconciliation and clears the conditions of resolved failures.
+// pushSuppressed tells that the AWS secret lacks values --read-only kept from being pushed
+func markSynced(aSecret *secretsv1alpha1.ASecret, pushSuppressed bool) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "ReconciliationSucceeded",
+		Message:            "Secret successfully synced",
+	})
+
+	for _, conditionType := range failureConditionTypes {
+		meta.RemoveStatusCondition(&aSecret.Status.Conditions, conditionType)
+	}
+
+	// A previous dry run no longer describes the Secret once changes are applied
+	meta.RemoveStatusCondition(&aSecret.Status.Conditions, ConditionTypeDryRun)
+
+	if isLocalOnly(aSecret) {
+		meta.RemoveStatusCondition(&aSecret.Status.Conditions, ConditionTypeRemoteSecretReady)
+	} else if pushSuppressed {
+		meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeRemoteSecretReady,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: aSecret.Generation,
+			Reason:             ReasonPushSuppressedReadOnly,
+			Message:            "AWS secret read, the push of changed values was suppressed by --read-only",
+		})
+	} else {
+		meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeRemoteSecretReady,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: aSecret.Generation,
+			Reason:             "RemoteSecretSynced",
+			Message:            "AWS secret successfully synced",
+		})
+	}
+
+	aSecret.Status.LastError = ""
+	aSecret.Status.LastErrorTime = nil
+}
+
+// markKubeSecretReady records that the Kubernetes Secret was written, so that a later failure to write AWS
+// still shows the Secret itself is up to date
+func markKubeSecretReady(aSecret *secretsv1alpha1.ASecret) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeKubeSecretReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "KubeSecretWritten",
+		Message:            fmt.Sprintf("Secret %s is up to date", aSecret.Spec.TargetSecretName),
+	})
+}
+
+// setSyncFailedCondition records a failed reconciliation on the ASecret status, and on the KubeSecretReady or
+// RemoteSecretReady condition of the side that failed
+func (r *ASecretReconciler) setSyncFailedCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, reason string, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             reason,
+		Message:            cause.Error(),
+	})
+
+	var sideConditionType string
+	switch {
+	case slices.Contains(remoteFailureReasons, reason):
+		sideConditionType = ConditionTypeRemoteSecretReady
+	case slices.Contains(kubeFailureReasons, reason):
+		sideConditionType = ConditionTypeKubeSecretReady
+	}
+	if sideConditionType != "" {
+		meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+			Type:               sideConditionType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: aSecret.Generation,
+			Reason:             reason,
+			Message:            cause.Error(),
+		})
+	}
+
+	// Unlike the condition message, the last error survives later partial status updates until a sync succeeds
+	now := metav1.Now()
+	aSecret.Status.LastError = cause.Error()
+	aSecret.Status.LastErrorTime = &now
+
+	if err := r.Status().Update(ctx, aSecret); err != nil {
+		log.Error(err, "Failed to update ASecret status")
+	}
+}
+
+// setAwsUnavailableCondition records that AWS SecretsManager could not be queried
+func (r *ASecretReconciler) setAwsUnavailableCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeAwsUnavailable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "GetSecretValueFailed",
+		Message:            cause.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonAWSError, cause, log)
+}
+
+// setWouldEmptySecretCondition records that updating the Kubernetes Secret was refused because it would empty it
+func (r *ASecretReconciler) setWouldEmptySecretCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeWouldEmptySecret,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "UpdateRefused",
+		Message:            cause.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonWouldEmptySecret, cause, log)
+}
+
+// setSecretTooLargeCondition records that neither the Kubernetes Secret nor AWS was written because the AWS
+// value exceeds --max-secret-bytes
+func (r *ASecretReconciler) setSecretTooLargeCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeSecretTooLarge,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "WriteRefused",
+		Message:            cause.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonSecretTooLarge, cause, log)
+}
+
+// setConflictCondition records that the target Secret was left alone because another ASecret manages it
+func (r *ASecretReconciler) setConflictCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, owner string, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeConflict,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "SecretManagedByAnotherASecret",
+		Message:            fmt.Sprintf("Secret %s is managed by ASecret %s", aSecret.Spec.TargetSecretName, owner),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonConflict, cause, log)
+}
+
+// setAwsSecretDeletingCondition records that the AWS secret is scheduled for deletion and was left as it is
+func (r *ASecretReconciler) setAwsSecretDeletingCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, cause error, log logr.Logger) {
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeAWSSecretDeleting,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "ScheduledForDeletion",
+		Message:            cause.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonAWSSecretDeleting, cause, log)
+}
+
+// dataErrorReason returns the Synced=False reason of a failure to produce the data source values
+func dataErrorReason(err error) string {
+	if isConfigMapMissing(err) {
+		return ReasonConfigMapMissing
+	}
+	if isSourceSecretMissing(err) {
+		return ReasonSecretMissing
+	}
+	if errors.Is(err, errGeneratorsDisabled) {
+		return ReasonGeneratorsDisabled
+	}
+	if apierrors.IsNotFound(err) {
+		return ReasonGeneratorMissing
+	}
+	return ReasonInvalidData
+}
@@ -0,0 +1,501 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestMarkSynced(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+	}{
+		{
+			name:       "no previous conditions",
+			conditions: nil,
+		},
+		{
+			name: "resolved AWS failure is cleared",
+			conditions: []metav1.Condition{
+				{Type: ConditionTypeSynced, Status: metav1.ConditionFalse, Reason: "AwsUnavailable"},
+				{Type: ConditionTypeAwsUnavailable, Status: metav1.ConditionTrue, Reason: "GetSecretValueFailed"},
+			},
+		},
+		{
+			name: "unrelated conditions are kept",
+			conditions: []metav1.Condition{
+				{Type: "Custom", Status: metav1.ConditionTrue, Reason: "Kept"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				Status: secretsv1alpha1.ASecretStatus{Conditions: tt.conditions},
+			}
+
+			markSynced(aSecret, false)
+
+			assert.True(t, meta.IsStatusConditionTrue(aSecret.Status.Conditions, ConditionTypeSynced))
+			for _, conditionType := range failureConditionTypes {
+				assert.Nil(t, meta.FindStatusCondition(aSecret.Status.Conditions, conditionType))
+			}
+			for _, c := range tt.conditions {
+				if c.Type == "Custom" {
+					assert.NotNil(t, meta.FindStatusCondition(aSecret.Status.Conditions, "Custom"))
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileClearsResolvedAwsUnavailableCondition(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flaky",
+			Namespace: "default",
+		},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "flaky-secret",
+			AwsSecretPath:    "/test/flaky",
+			OnlyImportRemote: boolPtr(true),
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username": "admin"}`),
+	}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "flaky", Namespace: "default"}}
+
+	// First reconcile fails to reach AWS
+	_, err := r.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeAwsUnavailable))
+	assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, ConditionTypeSynced))
+
+	// Second reconcile succeeds and clears the failure
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeAwsUnavailable))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileRecordsLastError(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flaky",
+			Namespace: "default",
+		},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "flaky-secret",
+			AwsSecretPath:    "/test/flaky",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username": "admin", "stale": "value"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException: not allowed")).Once()
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil).Once()
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "flaky", Namespace: "default"}}
+
+	// Pushing the pruned key fails on the first reconcile
+	_, err := r.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Equal(t, "AccessDeniedException: not allowed", updated.Status.LastError)
+	require.NotNil(t, updated.Status.LastErrorTime)
+
+	// The next reconcile succeeds and clears it
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Empty(t, updated.Status.LastError)
+	assert.Nil(t, updated.Status.LastErrorTime)
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileReportsKubeAndRemoteReadiness(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "half-synced", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "half-synced-secret",
+			AwsSecretPath:    "/test/half-synced",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username": "admin", "stale": "value"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException: not allowed")).Once()
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil).Once()
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "half-synced", Namespace: "default"}}
+
+	// The Kubernetes Secret is written, pushing the pruned key to AWS fails
+	_, err := r.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "half-synced-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, "admin", string(secret.Data["username"]))
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, ConditionTypeSynced))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeKubeSecretReady))
+	remote := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeRemoteSecretReady)
+	require.NotNil(t, remote)
+	assert.Equal(t, metav1.ConditionFalse, remote.Status)
+	assert.Equal(t, ReasonAWSError, remote.Reason)
+	assert.Contains(t, remote.Message, "AccessDeniedException")
+
+	// Once the push succeeds, both sides are ready
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeKubeSecretReady))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeRemoteSecretReady))
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileKubeFailureLeavesRemoteReadiness(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "local-secret",
+			Provider:         "none",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "local", Namespace: "default"}}
+
+	// Without AWS there is no remote side to report
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeKubeSecretReady))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeRemoteSecretReady))
+
+	// A template error only marks the Kubernetes side, tls.crt is no valid dotenv variable name
+	updated.Spec.Data["tls.crt"] = secretsv1alpha1.DataSource{Value: "certificate"}
+	updated.Spec.TargetSecretTemplate = &secretsv1alpha1.TargetSecretTemplate{
+		Dotenv: &secretsv1alpha1.DotenvTemplate{},
+	}
+	require.NoError(t, fakeClient.Update(ctx, &updated))
+	_, err = r.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	kube := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeKubeSecretReady)
+	require.NotNil(t, kube)
+	assert.Equal(t, metav1.ConditionFalse, kube.Status)
+	assert.Equal(t, ReasonTemplateError, kube.Reason)
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeRemoteSecretReady))
+}
+
+func TestReconcileEmptySecretGuard(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowEmptySecret *bool
+		expectBlocked    bool
+	}{
+		{
+			name:             "update to an empty Secret is refused by default",
+			allowEmptySecret: nil,
+			expectBlocked:    true,
+		},
+		{
+			name:             "update to an empty Secret is refused when explicitly disallowed",
+			allowEmptySecret: boolPtr(false),
+			expectBlocked:    true,
+		},
+		{
+			name:             "update to an empty Secret is applied when allowed",
+			allowEmptySecret: boolPtr(true),
+			expectBlocked:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "import", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "import-secret",
+					AwsSecretPath:    "/test/missing",
+					OnlyImportRemote: boolPtr(true),
+					AllowEmptySecret: tt.allowEmptySecret,
+				},
+				Status: secretsv1alpha1.ASecretStatus{
+					Conditions: []metav1.Condition{
+						{Type: ConditionTypeWouldEmptySecret, Status: metav1.ConditionTrue, Reason: "UpdateRefused", LastTransitionTime: metav1.Now()},
+					},
+				},
+			}
+			existingSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "import-secret", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("in-use")},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")}).Maybe()
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, existingSecret)
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "import", Namespace: "default"}})
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "import-secret", Namespace: "default"}, &secret))
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "import", Namespace: "default"}, &updated))
+
+			if tt.expectBlocked {
+				assert.Equal(t, []byte("in-use"), secret.Data["apiKey"])
+				assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeWouldEmptySecret))
+				synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+				require.NotNil(t, synced)
+				assert.Equal(t, metav1.ConditionFalse, synced.Status)
+				assert.Equal(t, "WouldEmptySecret", synced.Reason)
+			} else {
+				assert.Empty(t, secret.Data)
+				assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeWouldEmptySecret))
+				assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+			}
+		})
+	}
+}
+
+func TestReconcileKeepsSecretWithOnlyPreservedKeys(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "import", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName:      "import-secret",
+			AwsSecretPath:         "/test/missing",
+			OnlyImportRemote:      boolPtr(true),
+			PreserveUnmanagedKeys: boolPtr(true),
+		},
+	}
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "import-secret", Namespace: "default"},
+		Data:       map[string][]byte{"apiKey": []byte("in-use")},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")}).Maybe()
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, existingSecret)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "import", Namespace: "default"}})
+	require.NoError(t, err)
+
+	// The preserved key is written back, so the update does not empty the Secret
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "import-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, map[string][]byte{"apiKey": []byte("in-use")}, secret.Data)
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "import", Namespace: "default"}, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeWouldEmptySecret))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+}
+
+func TestWouldEmptySecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		allow      *bool
+		existing   map[string][]byte
+		secretData map[string][]byte
+		expected   bool
+	}{
+		{name: "non-empty update", existing: map[string][]byte{"a": []byte("1")}, secretData: map[string][]byte{"b": []byte("2")}, expected: false},
+		{name: "empty Secret stays empty", existing: nil, secretData: map[string][]byte{}, expected: false},
+		{name: "wiping a non-empty Secret", existing: map[string][]byte{"a": []byte("1")}, secretData: map[string][]byte{}, expected: true},
+		{name: "wiping allowed", allow: boolPtr(true), existing: map[string][]byte{"a": []byte("1")}, secretData: map[string][]byte{}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{AllowEmptySecret: tt.allow}}
+
+			assert.Equal(t, tt.expected, wouldEmptySecret(aSecret, &corev1.Secret{Data: tt.existing}, tt.secretData))
+		})
+	}
+}
+
+func TestReconcileFailureReasons(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           map[string]secretsv1alpha1.DataSource
+		template       *secretsv1alpha1.TargetSecretTemplate
+		setupMock      func(m *MockSecretsManagerClient)
+		expectedReason string
+	}{
+		{
+			name: "AWS read failure",
+			data: map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+			setupMock: func(m *MockSecretsManagerClient) {
+				m.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("throttled"))
+			},
+			expectedReason: ReasonAWSError,
+		},
+		{
+			name: "AWS write failure",
+			data: map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+			setupMock: func(m *MockSecretsManagerClient) {
+				m.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+				m.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+				m.On("CreateSecret", mock.Anything, mock.Anything).Return(nil, errors.New("access denied"))
+			},
+			expectedReason: ReasonAWSError,
+		},
+		{
+			name: "generator does not exist",
+			data: map[string]secretsv1alpha1.DataSource{
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "missing-generator"}},
+			},
+			setupMock: func(m *MockSecretsManagerClient) {
+				m.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+			},
+			expectedReason: ReasonGeneratorMissing,
+		},
+		{
+			name: "dotenv template cannot be rendered",
+			data: map[string]secretsv1alpha1.DataSource{"tls.crt": {Value: "certificate"}},
+			template: &secretsv1alpha1.TargetSecretTemplate{
+				Dotenv: &secretsv1alpha1.DotenvTemplate{},
+			},
+			setupMock: func(m *MockSecretsManagerClient) {
+				m.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+			},
+			expectedReason: ReasonTemplateError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default", Generation: 3},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName:     "failing-secret",
+					AwsSecretPath:        "/test/failing",
+					Data:                 tt.data,
+					TargetSecretTemplate: tt.template,
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			tt.setupMock(mockClient)
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "failing", Namespace: "default"}})
+			require.Error(t, err)
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "failing", Namespace: "default"}, &updated))
+			synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+			require.NotNil(t, synced)
+			assert.Equal(t, metav1.ConditionFalse, synced.Status)
+			assert.Equal(t, tt.expectedReason, synced.Reason)
+			assert.Equal(t, err.Error(), synced.Message)
+			assert.Equal(t, updated.Generation, synced.ObservedGeneration)
+		})
+	}
+}
+
+func TestMarkSyncedPushSuppressed(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{AwsSecretPath: "/app"}}
+
+	markSynced(aSecret, true)
+	remoteReady := meta.FindStatusCondition(aSecret.Status.Conditions, ConditionTypeRemoteSecretReady)
+	require.NotNil(t, remoteReady)
+	assert.Equal(t, ReasonPushSuppressedReadOnly, remoteReady.Reason)
+	assert.Contains(t, remoteReady.Message, "--read-only")
+
+	// Once AWS holds the values, the AWS secret is reported as synced again
+	markSynced(aSecret, false)
+	remoteReady = meta.FindStatusCondition(aSecret.Status.Conditions, ConditionTypeRemoteSecretReady)
+	require.NotNil(t, remoteReady)
+	assert.Equal(t, "RemoteSecretSynced", remoteReady.Reason)
+}
+
+func TestMarkSyncedKeepsTransitionTimeAndSetsObservedGeneration(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Generation: 7},
+		Status: secretsv1alpha1.ASecretStatus{
+			Conditions: []metav1.Condition{
+				{Type: ConditionTypeSynced, Status: metav1.ConditionTrue, Reason: "ReconciliationSucceeded", LastTransitionTime: transition, ObservedGeneration: 6},
+			},
+		},
+	}
+
+	markSynced(aSecret, false)
+
+	synced := meta.FindStatusCondition(aSecret.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, int64(7), synced.ObservedGeneration)
+	assert.True(t, synced.LastTransitionTime.Equal(&transition), "an unchanged status must not move the transition time")
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// configMapMissingError reports that the ConfigMap or the key a data source refers to does not exist
+type configMapMissingError struct {
+	namespace string
+	ref       secretsv1alpha1.ConfigMapKeyReference
+	cause     error
+}
+
+func (e *configMapMissingError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("ConfigMap %s/%s referenced by configMapRef is missing: %v", e.namespace, e.ref.Name, e.cause)
+	}
+	return fmt.Sprintf("ConfigMap %s/%s has no key %q", e.namespace, e.ref.Name, e.ref.Key)
+}
+
+func (e *configMapMissingError) Unwrap() error {
+	return e.cause
+}
+
+// isConfigMapMissing reports whether err comes from a configMapRef that could not be resolved
+func isConfigMapMissing(err error) bool {
+	var missing *configMapMissingError
+	return errors.As(err, &missing)
+}
+
+// configMapValue reads the value a configMapRef points to, from the ConfigMap in the ASecret namespace.
+// Both data and binaryData are looked up, so binary secrets can be sourced as well
+func (r *ASecretReconciler) configMapValue(ctx context.Context, aSecret *secretsv1alpha1.ASecret, ref *secretsv1alpha1.ConfigMapKeyReference, log logr.Logger) ([]byte, error) {
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, k8sTypes.NamespacedName{Namespace: aSecret.Namespace, Name: ref.Name}, &configMap); err != nil {
+		log.Error(err, "Failed to get ConfigMap", "name", ref.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, &configMapMissingError{namespace: aSecret.Namespace, ref: *ref, cause: err}
+		}
+		return nil, err
+	}
+
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return []byte(value), nil
+	}
+	if value, ok := configMap.BinaryData[ref.Key]; ok {
+		return value, nil
+	}
+	return nil, &configMapMissingError{namespace: aSecret.Namespace, ref: *ref}
+}
+
+// changedReferencedKeys returns the configMapRef and secretRef keys whose value differs from the one stored in AWS.
+// Such keys exist on both sides, so an update of the ConfigMap or Secret has to be pushed explicitly
+func changedReferencedKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, awsSecretData map[string]string) []string {
+	var changed []string
+	for key, dataSource := range aSecret.Spec.Data {
+		if dataSource.ConfigMapRef == nil && dataSource.SecretRef == nil {
+			continue
+		}
+		value, exists := secretData[key]
+		if !exists {
+			continue
+		}
+		if remote, ok := awsSecretData[key]; ok && !sameRemoteValue(aSecret, value, remote) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func newConfigMapASecret(ref secretsv1alpha1.ConfigMapKeyReference) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "composed", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "composed-secret",
+			AwsSecretPath:    "/composed",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"DB_HOST": {ConfigMapRef: &ref},
+			},
+		},
+	}
+}
+
+func TestProcessASecretDataConfigMapRef(t *testing.T) {
+	dbConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-config", Namespace: "default"},
+		Data:       map[string]string{"host": "db.internal"},
+		BinaryData: map[string][]byte{"ca.der": {0x30, 0x82}},
+	}
+	otherNamespace := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-config", Namespace: "other"},
+		Data:       map[string]string{"host": "db.other"},
+	}
+
+	tests := []struct {
+		name          string
+		ref           secretsv1alpha1.ConfigMapKeyReference
+		secretData    map[string][]byte
+		expected      []byte
+		expectMissing bool
+	}{
+		{
+			name:     "value read from the ConfigMap",
+			ref:      secretsv1alpha1.ConfigMapKeyReference{Name: "db-config", Key: "host"},
+			expected: []byte("db.internal"),
+		},
+		{
+			name:       "ConfigMap value replaces the previous one",
+			ref:        secretsv1alpha1.ConfigMapKeyReference{Name: "db-config", Key: "host"},
+			secretData: map[string][]byte{"DB_HOST": []byte("db.old")},
+			expected:   []byte("db.internal"),
+		},
+		{
+			name:     "binaryData key",
+			ref:      secretsv1alpha1.ConfigMapKeyReference{Name: "db-config", Key: "ca.der"},
+			expected: []byte{0x30, 0x82},
+		},
+		{
+			name:          "missing ConfigMap",
+			ref:           secretsv1alpha1.ConfigMapKeyReference{Name: "absent", Key: "host"},
+			expectMissing: true,
+		},
+		{
+			name:          "missing key",
+			ref:           secretsv1alpha1.ConfigMapKeyReference{Name: "db-config", Key: "port"},
+			expectMissing: true,
+		},
+		{
+			name:          "ConfigMap of another namespace",
+			ref:           secretsv1alpha1.ConfigMapKeyReference{Name: "shared-config", Key: "host"},
+			expectMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, dbConfig, otherNamespace)
+			secretData := tt.secretData
+			if secretData == nil {
+				secretData = map[string][]byte{}
+			}
+
+			err := r.processASecretData(context.Background(), newConfigMapASecret(tt.ref), secretData, logr.Discard())
+			if tt.expectMissing {
+				require.Error(t, err)
+				assert.Equal(t, ReasonConfigMapMissing, dataErrorReason(err))
+				assert.NotContains(t, secretData, "DB_HOST")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, secretData["DB_HOST"])
+		})
+	}
+}
+
+func TestReconcileConfigMapRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		configMap *corev1.ConfigMap
+	}{
+		{
+			name: "present ConfigMap is written to the Secret and AWS",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-config", Namespace: "default"},
+				Data:       map[string]string{"host": "db.internal"},
+			},
+		},
+		{
+			name: "absent ConfigMap fails with ConfigMapMissing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newConfigMapASecret(secretsv1alpha1.ConfigMapKeyReference{Name: "db-config", Key: "host"})
+			objs := []client.Object{aSecret}
+			if tt.configMap != nil {
+				objs = append(objs, tt.configMap)
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"DB_HOST":"db.old"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil).Maybe()
+			mockClient.On("PutSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.PutSecretValueInput) bool {
+				return aws.ToString(input.SecretString) == `{"DB_HOST":"db.internal"}`
+			})).Return(&secretsmanager.PutSecretValueOutput{}, nil).Maybe()
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, objs...)
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "composed", Namespace: "default"}}
+			_, err := r.Reconcile(context.Background(), req)
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+			synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+			require.NotNil(t, synced)
+
+			if tt.configMap == nil {
+				require.Error(t, err)
+				assert.Equal(t, metav1.ConditionFalse, synced.Status)
+				assert.Equal(t, ReasonConfigMapMissing, synced.Reason)
+				assert.Contains(t, synced.Message, "ConfigMap default/db-config")
+				mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, metav1.ConditionTrue, synced.Status)
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "composed-secret", Namespace: "default"}, &secret))
+			assert.Equal(t, []byte("db.internal"), secret.Data["DB_HOST"])
+			mockClient.AssertCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+		})
+	}
+}
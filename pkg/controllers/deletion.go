@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// awsSecretDeletingError reports that an AWS secret cannot be read because it is scheduled for deletion
+type awsSecretDeletingError struct {
+	secretID string
+	cause    error
+}
+
+func (e *awsSecretDeletingError) Error() string {
+	return fmt.Sprintf("AWS secret %s is scheduled for deletion, restore it or set restoreOnDeletion", e.secretID)
+}
+
+func (e *awsSecretDeletingError) Unwrap() error {
+	return e.cause
+}
+
+// getOrRestoreAwsSecret reads the AWS secret like getAwsSecret. With restoreOnDeletion, secrets scheduled for
+// deletion are restored and read again; each is restored at most once, so a failing restore cannot loop
+func (r *ASecretReconciler) getOrRestoreAwsSecret(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) (map[string]string, bool, error) {
+	restored := make(map[string]bool)
+	for {
+		data, exists, err := r.getAwsSecret(ctx, smClient, aSecret, log)
+		var deleting *awsSecretDeletingError
+		if !errors.As(err, &deleting) || restored[deleting.secretID] || !r.restoresOnDeletion(aSecret, log) {
+			return data, exists, err
+		}
+
+		if _, err := smClient.RestoreSecret(ctx, &secretsmanager.RestoreSecretInput{
+			SecretId: aws.String(deleting.secretID),
+		}); err != nil {
+			return nil, false, fmt.Errorf("failed to restore AWS secret %s scheduled for deletion: %w", deleting.secretID, err)
+		}
+		log.Info("Restored AWS secret scheduled for deletion", "path", deleting.secretID)
+		restored[deleting.secretID] = true
+	}
+}
+
+// restoresOnDeletion reports whether secrets of the ASecret scheduled for deletion are restored.
+// Restoring is a write, so it is suppressed in dry-run and read-only mode
+func (r *ASecretReconciler) restoresOnDeletion(aSecret *secretsv1alpha1.ASecret, log logr.Logger) bool {
+	if aSecret.Spec.RestoreOnDeletion == nil || !*aSecret.Spec.RestoreOnDeletion {
+		return false
+	}
+	if r.DryRun || r.ReadOnly {
+		log.Info("Dry-run or read-only mode, AWS secret scheduled for deletion not restored")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+var markedForDeletion = &smTypes.InvalidRequestException{
+	Message: aws.String("You can't perform this operation on the secret because it was marked for deletion."),
+}
+
+func deletingASecret(restore *bool) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deleting", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName:  "deleting-secret",
+			AwsSecretPath:     "/deleting",
+			RestoreOnDeletion: restore,
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+}
+
+func TestReconcileAwsSecretScheduledForDeletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		restore  *bool
+		readOnly bool
+	}{
+		{name: "restore not requested"},
+		{name: "restore disabled", restore: boolPtr(false)},
+		{name: "restore suppressed in read-only mode", restore: boolPtr(true), readOnly: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, markedForDeletion)
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, deletingASecret(tt.restore))
+			r.ReadOnly = tt.readOnly
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "deleting", Namespace: "default"}}
+
+			result, err := r.Reconcile(ctx, req)
+			require.NoError(t, err, "a secret scheduled for deletion is reported, not retried")
+			assert.Positive(t, result.RequeueAfter)
+			mockClient.AssertNotCalled(t, "RestoreSecret", mock.Anything, mock.Anything)
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+			deleting := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeAWSSecretDeleting)
+			require.NotNil(t, deleting)
+			assert.Equal(t, metav1.ConditionTrue, deleting.Status)
+			assert.Equal(t, "ScheduledForDeletion", deleting.Reason)
+			assert.Equal(t, "AWS secret /deleting is scheduled for deletion, restore it or set restoreOnDeletion", deleting.Message)
+
+			synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+			require.NotNil(t, synced)
+			assert.Equal(t, metav1.ConditionFalse, synced.Status)
+			assert.Equal(t, ReasonAWSSecretDeleting, synced.Reason)
+
+			// Nothing is written until the secret is restored
+			err = fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "deleting-secret", Namespace: "default"}, &corev1.Secret{})
+			assert.True(t, apierrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcileRestoresAwsSecretScheduledForDeletion(t *testing.T) {
+	aSecret := deletingASecret(boolPtr(true))
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:   ConditionTypeAWSSecretDeleting,
+		Status: metav1.ConditionTrue,
+		Reason: "ScheduledForDeletion",
+	})
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, markedForDeletion).Once()
+	mockClient.On("RestoreSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.RestoreSecretInput) bool {
+		return aws.ToString(input.SecretId) == "/deleting"
+	})).Return(&secretsmanager.RestoreSecretOutput{}, nil).Once()
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "deleting", Namespace: "default"}}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "deleting-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, "admin", string(secret.Data["username"]))
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeAWSSecretDeleting))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+}
+
+func TestReconcileRestoreFailure(t *testing.T) {
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, markedForDeletion)
+	mockClient.On("RestoreSecret", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException"))
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, deletingASecret(boolPtr(true)))
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "deleting", Namespace: "default"}}
+
+	_, err := r.Reconcile(ctx, req)
+	require.ErrorContains(t, err, "failed to restore AWS secret /deleting scheduled for deletion")
+	mockClient.AssertNumberOfCalls(t, "RestoreSecret", 1)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, ReasonAWSError, synced.Reason)
+}
+
+func TestGetOrRestoreAwsSecretRestoresOnce(t *testing.T) {
+	// A restore that does not take effect must not loop
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, markedForDeletion)
+	mockClient.On("RestoreSecret", mock.Anything, mock.Anything).Return(&secretsmanager.RestoreSecretOutput{}, nil)
+
+	r, _ := setupASecretReconciler(t, mockClient)
+	_, _, err := r.getOrRestoreAwsSecret(context.Background(), mockClient, deletingASecret(boolPtr(true)), r.Log)
+
+	var deleting *awsSecretDeletingError
+	require.ErrorAs(t, err, &deleting)
+	mockClient.AssertNumberOfCalls(t, "RestoreSecret", 1)
+	mockClient.AssertNumberOfCalls(t, "GetSecretValue", 2)
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// describeOnceSecretsManager serves the DescribeSecret calls of a reconcile for its AWS secret from a single
+// response, so the push, replica, description, KMS key and remote metadata steps do not describe the secret
+// once each. Any write drops the response, and the next step describes the secret again so it never reads
+// a description, version or timestamp from before the write
+type describeOnceSecretsManager struct {
+	awsclient.SecretsManagerAPI
+	secretPath string
+
+	described bool
+	output    *secretsmanager.DescribeSecretOutput
+	err       error
+}
+
+// newDescribeOnceSecretsManager wraps the API of a reconcile writing the AWS secret at secretPath
+func newDescribeOnceSecretsManager(api awsclient.SecretsManagerAPI, secretPath string) *describeOnceSecretsManager {
+	return &describeOnceSecretsManager{SecretsManagerAPI: api, secretPath: secretPath}
+}
+
+// DescribeSecret returns the cached response of the reconciled secret, describing it on first use or after
+// a write. Other secrets are always described
+func (d *describeOnceSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	if aws.ToString(params.SecretId) != d.secretPath {
+		return d.SecretsManagerAPI.DescribeSecret(ctx, params, optFns...)
+	}
+	if !d.described {
+		d.output, d.err = d.SecretsManagerAPI.DescribeSecret(ctx, params, optFns...)
+		d.described = true
+	}
+	return d.output, d.err
+}
+
+// invalidate drops the cached response after a write
+func (d *describeOnceSecretsManager) invalidate() {
+	d.described = false
+	d.output, d.err = nil, nil
+}
+
+func (d *describeOnceSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.CreateSecret(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.PutSecretValue(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.UpdateSecret(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.RestoreSecret(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.TagResource(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.UntagResource(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.ReplicateSecretToRegions(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.RemoveRegionsFromReplication(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.PutResourcePolicy(ctx, params, optFns...)
+}
+
+func (d *describeOnceSecretsManager) DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error) {
+	defer d.invalidate()
+	return d.SecretsManagerAPI.DeleteResourcePolicy(ctx, params, optFns...)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestDescribeOnceSecretsManager(t *testing.T) {
+	ctx := context.Background()
+	describe := func(api *describeOnceSecretsManager, path string) (*secretsmanager.DescribeSecretOutput, error) {
+		return api.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(path)})
+	}
+
+	t.Run("response and failure are shared", func(t *testing.T) {
+		for _, err := range []error{nil, errors.New("throttled")} {
+			output := &secretsmanager.DescribeSecretOutput{Description: aws.String("Rotated monthly")}
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(output, err).Once()
+
+			api := newDescribeOnceSecretsManager(mockClient, "/described")
+			for range 3 {
+				described, describeErr := describe(api, "/described")
+				assert.Same(t, output, described)
+				assert.Equal(t, err, describeErr)
+			}
+			mockClient.AssertNumberOfCalls(t, "DescribeSecret", 1)
+		}
+	})
+
+	t.Run("other secrets are not cached", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+		api := newDescribeOnceSecretsManager(mockClient, "/described")
+		for range 2 {
+			_, err := describe(api, "/other")
+			require.NoError(t, err)
+		}
+		mockClient.AssertNumberOfCalls(t, "DescribeSecret", 2)
+	})
+
+	t.Run("a write drops the response", func(t *testing.T) {
+		before := &secretsmanager.DescribeSecretOutput{Description: aws.String("before")}
+		after := &secretsmanager.DescribeSecretOutput{Description: aws.String("after")}
+		mockClient := &MockSecretsManagerClient{}
+		mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(before, nil).Once()
+		mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(after, nil).Once()
+		mockClient.On("UpdateSecret", mock.Anything, mock.Anything).Return(&secretsmanager.UpdateSecretOutput{}, nil)
+
+		api := newDescribeOnceSecretsManager(mockClient, "/described")
+		described, err := describe(api, "/described")
+		require.NoError(t, err)
+		assert.Same(t, before, described)
+
+		_, err = api.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{SecretId: aws.String("/described"), Description: aws.String("after")})
+		require.NoError(t, err)
+		described, err = describe(api, "/described")
+		require.NoError(t, err)
+		assert.Same(t, after, described)
+		mockClient.AssertNumberOfCalls(t, "DescribeSecret", 2)
+	})
+}
+
+func TestReconcileSharesDescribeSecret(t *testing.T) {
+	changed := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := changed.Add(time.Hour)
+	tests := []struct {
+		name              string
+		current           string
+		expectedDescribes int
+		expectedChanged   time.Time
+	}{
+		{
+			name:              "secret in sync is described once",
+			current:           "Rotated monthly",
+			expectedDescribes: 1,
+			expectedChanged:   changed,
+		},
+		{
+			name:              "metadata is described again after a description update",
+			current:           "Written by hand in the console",
+			expectedDescribes: 2,
+			expectedChanged:   updated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "described", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "described-secret",
+					AwsSecretPath:    "/described",
+					Description:      "Rotated monthly",
+					KmsKeyId:         "alias/payments",
+					ReplicaRegions:   []string{"eu-central-1"},
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+			replicated := []smTypes.ReplicationStatusType{{Region: aws.String("eu-central-1"), Status: smTypes.StatusTypeInSync}}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{
+				Description:       aws.String(tt.current),
+				KmsKeyId:          aws.String(testAliasARN),
+				LastChangedDate:   &changed,
+				ReplicationStatus: replicated,
+			}, nil).Once()
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{
+				Description:       aws.String("Rotated monthly"),
+				KmsKeyId:          aws.String(testAliasARN),
+				LastChangedDate:   &updated,
+				ReplicationStatus: replicated,
+			}, nil)
+			mockClient.On("UpdateSecret", mock.Anything, mock.Anything).Return(&secretsmanager.UpdateSecretOutput{}, nil)
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "described", Namespace: "default"}})
+			require.NoError(t, err)
+			mockClient.AssertNumberOfCalls(t, "DescribeSecret", tt.expectedDescribes)
+
+			var result secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "described", Namespace: "default"}, &result))
+			require.NotNil(t, result.Status.RemoteMetadata)
+			assert.True(t, result.Status.RemoteMetadata.LastChangedDate.Time.Equal(tt.expectedChanged))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// awsSecretDescription returns the description of the AWS secret created for the ASecret,
+// naming the owning ASecret unless the spec sets one
+func awsSecretDescription(aSecret *secretsv1alpha1.ASecret) string {
+	if aSecret.Spec.Description != "" {
+		return aSecret.Spec.Description
+	}
+	return fmt.Sprintf("Managed by yet-another-secrets-operator for ASecret %s/%s", aSecret.Namespace, aSecret.Name)
+}
+
+// reconcileAwsDescription updates the description of the existing AWS secret when it differs from
+// spec.description. Without a spec description, the description set at creation or by someone else is kept
+func (r *ASecretReconciler) reconcileAwsDescription(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) error {
+	if aSecret.Spec.Description == "" {
+		return nil
+	}
+
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	described, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe AWS secret %s: %w", secretPath, err)
+	}
+	if aws.ToString(described.Description) == aSecret.Spec.Description {
+		return nil
+	}
+
+	if _, err := smClient.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
+		SecretId:    aws.String(secretPath),
+		Description: aws.String(aSecret.Spec.Description),
+	}); err != nil {
+		return fmt.Errorf("failed to update description of AWS secret %s: %w", secretPath, err)
+	}
+	log.Info("Updated AWS secret description", "path", secretPath)
+	return nil
+}
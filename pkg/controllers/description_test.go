@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestCreateAwsSecretDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		binary      bool
+		expected    string
+	}{
+		{
+			name:     "defaults to the owning ASecret",
+			expected: "Managed by yet-another-secrets-operator for ASecret payments/db-credentials",
+		},
+		{
+			name:        "spec description is used",
+			description: "Database credentials of the payments service",
+			expected:    "Database credentials of the payments service",
+		},
+		{
+			name:        "binary secrets are described too",
+			description: "TLS certificate",
+			binary:      true,
+			expected:    "TLS certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "payments"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					AwsSecretPath: "/payments/db",
+					Description:   tt.description,
+				},
+			}
+			if tt.binary {
+				aSecret.Spec.ValueType = "binary"
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{})
+			mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+				return aws.ToString(input.Description) == tt.expected
+			})).Return(&secretsmanager.CreateSecretOutput{}, nil)
+
+			r := newVerifyWriteReconciler(1)
+			err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, map[string][]byte{"tls.crt": []byte("cert")}, logr.Discard())
+			require.NoError(t, err)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcileAwsDescription(t *testing.T) {
+	tests := []struct {
+		name         string
+		description  string
+		current      string
+		expectUpdate bool
+	}{
+		{
+			name:         "changed description is updated",
+			description:  "Rotated monthly",
+			current:      "Managed by yet-another-secrets-operator for ASecret default/described",
+			expectUpdate: true,
+		},
+		{
+			name:        "matching description is left alone",
+			description: "Rotated monthly",
+			current:     "Rotated monthly",
+		},
+		{
+			name:    "without a spec description the current one is kept",
+			current: "Written by hand in the console",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "described", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "described-secret",
+					AwsSecretPath:    "/described",
+					Description:      tt.description,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{
+				Description: aws.String(tt.current),
+			}, nil)
+			if tt.expectUpdate {
+				mockClient.On("UpdateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.UpdateSecretInput) bool {
+					return aws.ToString(input.SecretId) == "/described" && aws.ToString(input.Description) == tt.description &&
+						input.SecretString == nil && input.SecretBinary == nil && input.KmsKeyId == nil
+				})).Return(&secretsmanager.UpdateSecretOutput{}, nil).Once()
+			}
+
+			r, _ := setupASecretReconciler(t, mockClient, aSecret)
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "described", Namespace: "default"}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			mockClient.AssertExpectations(t)
+			if !tt.expectUpdate {
+				mockClient.AssertNotCalled(t, "UpdateSecret", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReconcileAwsDescriptionFailure(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "described", Namespace: "default"},
+		Spec:       secretsv1alpha1.ASecretSpec{AwsSecretPath: "/described", Description: "Rotated monthly"},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("UpdateSecret", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException"))
+
+	r := newVerifyWriteReconciler(1)
+	err := r.reconcileAwsDescription(context.Background(), mockClient, aSecret, logr.Discard())
+	require.ErrorContains(t, err, "failed to update description of AWS secret /described")
+	assert.ErrorContains(t, err, "AccessDeniedException")
+}
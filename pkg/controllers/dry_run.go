@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// dryRunPlan describes the changes a reconcile would apply
+type dryRunPlan struct {
+	kubeSecretAction string
+	addedKeys        []string
+	changedKeys      []string
+	removedKeys      []string
+	awsSecretAction  string
+}
+
+// hasChanges reports whether anything would be written
+func (p dryRunPlan) hasChanges() bool {
+	return p.kubeSecretAction != "" || p.awsSecretAction != ""
+}
+
+// message summarizes the plan without revealing any secret value
+func (p dryRunPlan) message(kubeSecretName, awsSecretPath string) string {
+	if !p.hasChanges() {
+		return "Dry run: no changes"
+	}
+
+	var parts []string
+	if p.kubeSecretAction != "" {
+		part := fmt.Sprintf("would %s Kubernetes Secret %s", p.kubeSecretAction, kubeSecretName)
+		if keys := describeKeyChanges(p.addedKeys, p.changedKeys, p.removedKeys); keys != "" {
+			part += " (" + keys + ")"
+		}
+		parts = append(parts, part)
+	}
+	if p.awsSecretAction != "" {
+		parts = append(parts, fmt.Sprintf("would %s AWS secret %s", p.awsSecretAction, awsSecretPath))
+	}
+	return "Dry run: " + strings.Join(parts, "; ")
+}
+
+// planDryRun computes the Kubernetes Secret and AWS changes a reconcile would apply
+func (r *ASecretReconciler) planDryRun(aSecret *secretsv1alpha1.ASecret, existingSecret *corev1.Secret, kubeSecretExists bool, kubeSecretData, secretData map[string][]byte, awsSecretData map[string]string, awsSecretExists, rotated bool) dryRunPlan {
+	var plan dryRunPlan
+
+	var currentData map[string][]byte
+	if kubeSecretExists {
+		currentData = existingSecret.Data
+	}
+	plan.addedKeys, plan.changedKeys, plan.removedKeys = diffSecretKeys(currentData, kubeSecretData)
+	switch {
+	case !kubeSecretExists:
+		plan.kubeSecretAction = "create"
+	case len(plan.addedKeys)+len(plan.changedKeys)+len(plan.removedKeys) > 0:
+		plan.kubeSecretAction = "update"
+	}
+
+	onlyImportRemote := isImportOnly(aSecret)
+	if !isLocalOnly(aSecret) && !onlyImportRemote && !r.ReadOnly && (rotated || r.shouldUpdateAwsSecret(aSecret, secretData, awsSecretData, awsSecretExists)) {
+		if awsSecretExists {
+			plan.awsSecretAction = "update"
+		} else {
+			plan.awsSecretAction = "create"
+		}
+	}
+
+	return plan
+}
+
+// reportDryRun logs the planned changes and records them in the DryRun condition instead of applying them
+func (r *ASecretReconciler) reportDryRun(ctx context.Context, aSecret *secretsv1alpha1.ASecret, plan dryRunPlan, log logr.Logger) (ctrl.Result, error) {
+	log.Info("Dry run, skipping changes",
+		"kubernetesSecret", aSecret.Spec.TargetSecretName,
+		"kubernetesSecretAction", plan.kubeSecretAction,
+		"addedKeys", plan.addedKeys,
+		"changedKeys", plan.changedKeys,
+		"removedKeys", plan.removedKeys,
+		"awsSecretPath", r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath),
+		"awsSecretAction", plan.awsSecretAction)
+
+	reason := "NoChanges"
+	if plan.hasChanges() {
+		reason = "ChangesPending"
+	}
+
+	aSecret.Status.LastSyncTime = metav1.Now()
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeDryRun,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             reason,
+		Message:            plan.message(aSecret.Spec.TargetSecretName, r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)),
+	})
+
+	if err := r.Status().Update(ctx, aSecret); err != nil {
+		log.Error(err, "Failed to update ASecret status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.refreshInterval(aSecret)}, nil
+}
+
+// diffSecretKeys returns the sorted keys added, changed and removed between two Secret data maps
+func diffSecretKeys(current, desired map[string][]byte) (added, changed, removed []string) {
+	for k, v := range desired {
+		currentValue, exists := current[k]
+		if !exists {
+			added = append(added, k)
+		} else if !bytes.Equal(currentValue, v) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range current {
+		if _, exists := desired[k]; !exists {
+			removed = append(removed, k)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return added, changed, removed
+}
+
+// describeKeyChanges formats the key changes as e.g. "added: a, b; removed: c"
+func describeKeyChanges(added, changed, removed []string) string {
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, "added: "+strings.Join(added, ", "))
+	}
+	if len(changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(changed, ", "))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "removed: "+strings.Join(removed, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
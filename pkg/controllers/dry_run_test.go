@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	"github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// setupDryRunReconciler builds a dry-run reconciler whose fake client counts every mutating call
+// outside of the status subresource
+func setupDryRunReconciler(t *testing.T, smClient awsclient.SecretsManagerAPI, objs ...client.Object) (*ASecretReconciler, client.Client, *int) {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	mutations := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithStatusSubresource(&secretsv1alpha1.ASecret{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				mutations++
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				mutations++
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				mutations++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				mutations++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return &ASecretReconciler{
+		Client:         fakeClient,
+		Scheme:         s,
+		Log:            logr.Discard(),
+		AwsClient:      &awsclient.AwsClient{Config: config.AWSConfig{RemoveRemoteKeys: true}},
+		SecretsManager: smClient,
+		DryRun:         true,
+	}, fakeClient, &mutations
+}
+
+func TestReconcileDryRunMakesNoMutatingCalls(t *testing.T) {
+	tests := []struct {
+		name            string
+		existingSecret  *corev1.Secret
+		awsSecret       *secretsmanager.GetSecretValueOutput
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "new Kubernetes and AWS secrets",
+			awsSecret:       nil,
+			expectedReason:  "ChangesPending",
+			expectedMessage: "Dry run: would create Kubernetes Secret app-secret (added: username); would create AWS secret /app/secret",
+		},
+		{
+			name: "changed Kubernetes secret and extra AWS key",
+			existingSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "default"},
+				Data:       map[string][]byte{"username": []byte("root"), "legacy": []byte("x")},
+			},
+			awsSecret: &secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username": "admin", "legacy": "x"}`),
+			},
+			expectedReason:  "ChangesPending",
+			expectedMessage: "Dry run: would update Kubernetes Secret app-secret (changed: username; removed: legacy); would update AWS secret /app/secret",
+		},
+		{
+			name: "everything in sync",
+			existingSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "default"},
+				Data:       map[string][]byte{"username": []byte("admin")},
+			},
+			awsSecret: &secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username": "admin"}`),
+			},
+			expectedReason:  "NoChanges",
+			expectedMessage: "Dry run: no changes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "app-secret",
+					AwsSecretPath:    "/app/secret",
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+			objs := []client.Object{aSecret}
+			if tt.existingSecret != nil {
+				objs = append(objs, tt.existingSecret)
+			}
+
+			// Only GetSecretValue is expected, any mutating AWS call fails the mock
+			mockClient := &MockSecretsManagerClient{}
+			if tt.awsSecret != nil {
+				mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(tt.awsSecret, nil)
+			} else {
+				mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+			}
+
+			r, fakeClient, mutations := setupDryRunReconciler(t, mockClient, objs...)
+			ctx := context.Background()
+			before := time.Now().Add(-time.Second)
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "app", Namespace: "default"}})
+			require.NoError(t, err)
+			assert.Equal(t, time.Hour, result.RequeueAfter)
+
+			assert.Zero(t, *mutations, "dry run must not create, update, patch or delete objects")
+			mockClient.AssertExpectations(t)
+			mockClient.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "TagResource", mock.Anything, mock.Anything)
+
+			var secret corev1.Secret
+			err = fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "app-secret", Namespace: "default"}, &secret)
+			if tt.existingSecret == nil {
+				assert.True(t, apierrors.IsNotFound(err))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.existingSecret.Data, secret.Data)
+			}
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "app", Namespace: "default"}, &updated))
+			assert.True(t, updated.Status.LastSyncTime.After(before))
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeDryRun)
+			require.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+			assert.Equal(t, tt.expectedMessage, condition.Message)
+			assert.NotContains(t, condition.Message, "admin")
+			assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced))
+		})
+	}
+}
+
+func TestReconcileDryRunAwsErrorStillFails(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "app-secret",
+			AwsSecretPath:    "/app/secret",
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+	r, _, mutations := setupDryRunReconciler(t, mockClient, aSecret)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "app", Namespace: "default"}})
+	assert.Error(t, err)
+	assert.Zero(t, *mutations)
+}
+
+func TestDiffSecretKeys(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         map[string][]byte
+		desired         map[string][]byte
+		expectedAdded   []string
+		expectedChanged []string
+		expectedRemoved []string
+	}{
+		{
+			name:          "nothing exists yet",
+			current:       nil,
+			desired:       map[string][]byte{"b": []byte("2"), "a": []byte("1")},
+			expectedAdded: []string{"a", "b"},
+		},
+		{
+			name:    "identical data",
+			current: map[string][]byte{"a": []byte("1")},
+			desired: map[string][]byte{"a": []byte("1")},
+		},
+		{
+			name:            "added, changed and removed keys",
+			current:         map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")},
+			desired:         map[string][]byte{"a": []byte("1"), "b": []byte("changed"), "d": []byte("4")},
+			expectedAdded:   []string{"d"},
+			expectedChanged: []string{"b"},
+			expectedRemoved: []string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, changed, removed := diffSecretKeys(tt.current, tt.desired)
+
+			assert.Equal(t, tt.expectedAdded, added)
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestMarkSyncedClearsDryRunCondition(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Status: secretsv1alpha1.ASecretStatus{
+			Conditions: []metav1.Condition{
+				{Type: ConditionTypeDryRun, Status: metav1.ConditionTrue, Reason: "ChangesPending"},
+			},
+		},
+	}
+
+	markSynced(aSecret, false)
+
+	assert.Nil(t, meta.FindStatusCondition(aSecret.Status.Conditions, ConditionTypeDryRun))
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// ForceSyncAnnotation triggers an immediate reconcile reading AWS past the secret cache whenever its value
+// changes, e.g. to the current time. The operator never modifies it, the handled value goes to the status
+const ForceSyncAnnotation = "yet-another-secrets.io/force-sync"
+
+// forceSyncPending reports whether the force-sync annotation holds a value no successful reconcile handled yet
+func forceSyncPending(aSecret *secretsv1alpha1.ASecret) bool {
+	value := aSecret.Annotations[ForceSyncAnnotation]
+	return value != "" && value != aSecret.Status.LastForceSync
+}
+
+// recordForceSync records the force-sync annotation value handled by a successful reconcile
+func recordForceSync(aSecret *secretsv1alpha1.ASecret) {
+	aSecret.Status.LastForceSync = aSecret.Annotations[ForceSyncAnnotation]
+}
+
+// forceSyncChangedPredicate triggers a reconcile when the force-sync annotation is set to a new value,
+// which does not bump the generation. Removing the annotation does not trigger one
+func forceSyncChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			value := e.ObjectNew.GetAnnotations()[ForceSyncAnnotation]
+			return value != "" && value != e.ObjectOld.GetAnnotations()[ForceSyncAnnotation]
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+func TestASecretForceSyncPredicate(t *testing.T) {
+	old := &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "app", Generation: 1}}
+	forced := old.DeepCopy()
+	forced.Annotations = map[string]string{ForceSyncAnnotation: "2026-10-14T10:00:00Z"}
+	forcedAgain := old.DeepCopy()
+	forcedAgain.Annotations = map[string]string{ForceSyncAnnotation: "2026-10-14T11:00:00Z"}
+	otherAnnotation := forced.DeepCopy()
+	otherAnnotation.Annotations["team"] = "platform"
+
+	p := predicate.Or(predicate.GenerationChangedPredicate{}, forceSyncChangedPredicate())
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: forced}), "setting the annotation must trigger a reconcile")
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: forced, ObjectNew: forcedAgain}), "a new value must trigger a reconcile")
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: forced, ObjectNew: otherAnnotation}), "an unchanged value must not trigger a reconcile")
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: forced, ObjectNew: old}), "removing the annotation must not trigger a reconcile")
+}
+
+func TestForceSyncPending(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotation    string
+		lastForceSync string
+		expected      bool
+	}{
+		{name: "no annotation"},
+		{name: "new value", annotation: "1", expected: true},
+		{name: "changed value", annotation: "2", lastForceSync: "1", expected: true},
+		{name: "handled value", annotation: "1", lastForceSync: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Status: secretsv1alpha1.ASecretStatus{LastForceSync: tt.lastForceSync}}
+			if tt.annotation != "" {
+				aSecret.Annotations = map[string]string{ForceSyncAnnotation: tt.annotation}
+			}
+			assert.Equal(t, tt.expected, forceSyncPending(aSecret))
+		})
+	}
+}
+
+func TestReconcileForceSyncBypassesCache(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "forced", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "forced-secret",
+			AwsSecretPath:    "/forced",
+			OnlyImportRemote: boolPtr(true),
+		},
+	}
+
+	// The token is rotated in AWS after the first read was cached
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"token":"old"}`),
+	}, nil).Once()
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"token":"rotated"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, awsclient.NewCachingSecretsManager(mockClient, time.Hour), aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "forced", Namespace: "default"}}
+	secretName := k8sTypes.NamespacedName{Name: "forced-secret", Namespace: "default"}
+
+	assertToken := func(expected string) {
+		t.Helper()
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+		assert.Equal(t, expected, string(secret.Data["token"]))
+	}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertToken("old")
+
+	// Without a force-sync the cached value is served
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertToken("old")
+	mockClient.AssertNumberOfCalls(t, "GetSecretValue", 1)
+
+	var current secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+	current.Annotations = map[string]string{ForceSyncAnnotation: "1"}
+	require.NoError(t, fakeClient.Update(ctx, &current))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertToken("rotated")
+	mockClient.AssertNumberOfCalls(t, "GetSecretValue", 2)
+
+	// The handled value is recorded and the annotation left alone, so the next reconcile uses the cache again
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+	assert.Equal(t, "1", current.Status.LastForceSync)
+	assert.Equal(t, "1", current.Annotations[ForceSyncAnnotation])
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetSecretValue", 2)
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// errGeneratorsDisabled is the cause of a generatorError when the operator runs without the AGenerator controller
+var errGeneratorsDisabled = errors.New("the AGenerator controller is disabled by --enable-generator-controller=false")
+
+// generatorError reports that the AGenerator referenced by a data key is missing or cannot generate a value
+type generatorError struct {
+	key       string
+	generator string
+	cause     error
+}
+
+func (e *generatorError) Error() string {
+	if e.missing() {
+		return fmt.Sprintf("AGenerator %s referenced by key %s does not exist", e.generator, e.key)
+	}
+	if e.disabled() {
+		return fmt.Sprintf("AGenerator %s referenced by key %s cannot be used, %v", e.generator, e.key, e.cause)
+	}
+	return fmt.Sprintf("AGenerator %s referenced by key %s is invalid: %v", e.generator, e.key, e.cause)
+}
+
+func (e *generatorError) Unwrap() error {
+	return e.cause
+}
+
+// missing reports whether the AGenerator does not exist, rather than having an invalid spec
+func (e *generatorError) missing() bool {
+	return apierrors.IsNotFound(e.cause)
+}
+
+// disabled reports whether the value could not be generated because generators are disabled
+func (e *generatorError) disabled() bool {
+	return errors.Is(e.cause, errGeneratorsDisabled)
+}
+
+// setGeneratorErrorCondition records which AGenerator failed the reconcile, next to the Synced failure
+func (r *ASecretReconciler) setGeneratorErrorCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, genErr *generatorError, log logr.Logger) {
+	reason := "GeneratorInvalid"
+	if genErr.missing() {
+		reason = "GeneratorNotFound"
+	} else if genErr.disabled() {
+		reason = "GeneratorsDisabled"
+	}
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeGeneratorError,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             reason,
+		Message:            genErr.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, dataErrorReason(genErr), genErr, log)
+}
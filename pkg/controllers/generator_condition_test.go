@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestReconcileGeneratorErrorCondition(t *testing.T) {
+	tests := []struct {
+		name            string
+		generator       *secretsv1alpha1.AGenerator
+		disabled        bool
+		expectedReason  string
+		expectedSynced  string
+		expectedMessage string
+	}{
+		{
+			name:            "missing generator",
+			expectedReason:  "GeneratorNotFound",
+			expectedSynced:  ReasonGeneratorMissing,
+			expectedMessage: "AGenerator password-generator referenced by key password does not exist",
+		},
+		{
+			name: "invalid generator spec",
+			generator: &secretsv1alpha1.AGenerator{
+				ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+				Spec:       secretsv1alpha1.AGeneratorSpec{Length: 16},
+			},
+			expectedReason:  "GeneratorInvalid",
+			expectedSynced:  ReasonInvalidData,
+			expectedMessage: "AGenerator password-generator referenced by key password is invalid: ",
+		},
+		{
+			name: "generators disabled",
+			generator: &secretsv1alpha1.AGenerator{
+				ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+				Spec:       secretsv1alpha1.AGeneratorSpec{Length: 16, IncludeLowercase: true},
+			},
+			disabled:        true,
+			expectedReason:  "GeneratorsDisabled",
+			expectedSynced:  ReasonGeneratorsDisabled,
+			expectedMessage: "AGenerator password-generator referenced by key password cannot be used, the AGenerator controller is disabled by --enable-generator-controller=false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "generated-secret",
+					AwsSecretPath:    "/generated",
+					Data: map[string]secretsv1alpha1.DataSource{
+						"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"}},
+					},
+				},
+			}
+			objs := []client.Object{aSecret}
+			if tt.generator != nil {
+				objs = append(objs, tt.generator)
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")})
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, objs...)
+			r.GeneratorsDisabled = tt.disabled
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "generated", Namespace: "default"}}
+			_, err := r.Reconcile(ctx, req)
+			require.Error(t, err)
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+			generatorCondition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeGeneratorError)
+			require.NotNil(t, generatorCondition)
+			assert.Equal(t, metav1.ConditionTrue, generatorCondition.Status)
+			assert.Equal(t, tt.expectedReason, generatorCondition.Reason)
+			assert.Contains(t, generatorCondition.Message, tt.expectedMessage)
+
+			synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+			require.NotNil(t, synced)
+			assert.Equal(t, metav1.ConditionFalse, synced.Status)
+			assert.Equal(t, tt.expectedSynced, synced.Reason)
+		})
+	}
+}
+
+func TestReconcileClearsGeneratorErrorCondition(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "generated-secret",
+			Provider:         "none",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"}},
+			},
+		},
+	}
+
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "generated", Namespace: "default"}}
+	_, err := r.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	// Creating the generator resolves the failure
+	require.NoError(t, fakeClient.Create(ctx, &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 16, IncludeLowercase: true},
+	}))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeGeneratorError))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+}
+
+func TestGenerateValuesWrapsOnlyGeneratorFailures(t *testing.T) {
+	r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{})
+	_, err := r.generateValues(context.Background(), &secretsv1alpha1.ASecret{}, "password", &secretsv1alpha1.GeneratorReference{Name: "absent"}, r.Log)
+
+	var genErr *generatorError
+	require.ErrorAs(t, err, &genErr)
+	assert.True(t, genErr.missing())
+	assert.Equal(t, ReasonGeneratorMissing, dataErrorReason(err))
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// hasGeneratorOverrides reports whether the generator reference overrides any parameter of its AGenerator
+func hasGeneratorOverrides(ref *secretsv1alpha1.GeneratorReference) bool {
+	return ref.Length != nil || ref.IncludeUppercase != nil || ref.IncludeLowercase != nil ||
+		ref.IncludeNumbers != nil || ref.IncludeSpecialChars != nil || ref.SpecialChars != nil
+}
+
+// applyGeneratorOverrides returns the AGenerator spec with the parameters set on the reference merged over it
+func applyGeneratorOverrides(spec secretsv1alpha1.AGeneratorSpec, ref *secretsv1alpha1.GeneratorReference) secretsv1alpha1.AGeneratorSpec {
+	if ref.Length != nil {
+		spec.Length = *ref.Length
+	}
+	if ref.IncludeUppercase != nil {
+		spec.IncludeUppercase = *ref.IncludeUppercase
+	}
+	if ref.IncludeLowercase != nil {
+		spec.IncludeLowercase = *ref.IncludeLowercase
+	}
+	if ref.IncludeNumbers != nil {
+		spec.IncludeNumbers = *ref.IncludeNumbers
+	}
+	if ref.IncludeSpecialChars != nil {
+		spec.IncludeSpecialChars = *ref.IncludeSpecialChars
+	}
+	if ref.SpecialChars != nil {
+		spec.SpecialChars = *ref.SpecialChars
+	}
+	return spec
+}
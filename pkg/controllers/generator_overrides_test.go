@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestApplyGeneratorOverrides(t *testing.T) {
+	base := secretsv1alpha1.AGeneratorSpec{
+		Length:              16,
+		IncludeUppercase:    true,
+		IncludeLowercase:    true,
+		IncludeNumbers:      true,
+		IncludeSpecialChars: true,
+		SpecialChars:        "!@#",
+	}
+	ref := &secretsv1alpha1.GeneratorReference{
+		Name:                "password-generator",
+		Length:              intPtr(40),
+		IncludeSpecialChars: boolPtr(false),
+	}
+
+	merged := applyGeneratorOverrides(base, ref)
+	assert.Equal(t, 40, merged.Length)
+	assert.False(t, merged.IncludeSpecialChars)
+	assert.True(t, merged.IncludeUppercase, "parameters without an override keep the generator value")
+	assert.Equal(t, "!@#", merged.SpecialChars)
+	assert.Equal(t, 16, base.Length, "the generator spec is not modified")
+
+	assert.True(t, hasGeneratorOverrides(ref))
+	assert.False(t, hasGeneratorOverrides(&secretsv1alpha1.GeneratorReference{Name: "password-generator"}))
+}
+
+func TestReconcileGeneratorOverrides(t *testing.T) {
+	generator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+		Spec: secretsv1alpha1.AGeneratorSpec{
+			Length:           16,
+			IncludeLowercase: true,
+		},
+	}
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "overrides", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "overrides-secret",
+			AwsSecretPath:    "/overrides",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"}},
+				"pin": {GeneratorRef: &secretsv1alpha1.GeneratorReference{
+					Name:             "password-generator",
+					Length:           intPtr(6),
+					IncludeLowercase: boolPtr(false),
+					IncludeNumbers:   boolPtr(true),
+				}},
+			},
+		},
+	}
+	notFound := &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, notFound)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, notFound).Once()
+	mockClient.On("CreateSecret", mock.Anything, mock.Anything).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, generator)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "overrides", Namespace: "default"}}
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "overrides-secret", Namespace: "default"}, &secret))
+	assert.Len(t, secret.Data["password"], 16)
+	assert.Len(t, secret.Data["pin"], 6)
+	assert.Empty(t, strings.Trim(string(secret.Data["pin"]), "0123456789"), "pin only holds numbers")
+
+	var unchanged secretsv1alpha1.AGenerator
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "password-generator"}, &unchanged))
+	assert.Equal(t, generator.Spec, unchanged.Spec)
+}
+
+func TestGenerateValuesInvalidOverrides(t *testing.T) {
+	generator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 16, IncludeLowercase: true},
+	}
+	r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, generator)
+
+	// Disabling the only character type of the generator leaves nothing to generate from
+	ref := &secretsv1alpha1.GeneratorReference{Name: "password-generator", IncludeLowercase: boolPtr(false)}
+	_, err := r.generateValues(context.Background(), &secretsv1alpha1.ASecret{}, "password", ref, r.Log)
+
+	var genErr *generatorError
+	require.ErrorAs(t, err, &genErr)
+	assert.False(t, genErr.missing())
+	assert.ErrorContains(t, err, "invalid overrides: at least one character type")
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
+)
+
+// generateDeterministicValue derives the value of the ASecret key from the seed of a deterministic generator.
+// The ASecret namespace and name are part of the HKDF info, so ASecrets sharing a generator and a key name
+// still get different values
+func (r *ASecretReconciler) generateDeterministicValue(ctx context.Context, aSecret *secretsv1alpha1.ASecret, key string, generator *secretsv1alpha1.AGenerator) ([]byte, error) {
+	ref := generator.Spec.SeedSecretRef
+	if ref == nil {
+		return nil, &generatorError{key: key, generator: generator.Name, cause: errors.New("seedSecretRef is required for a deterministic generator")}
+	}
+
+	var seedSecret corev1.Secret
+	if err := r.Get(ctx, k8sTypes.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &seedSecret); err != nil {
+		// A missing seed is an invalid generator, not a missing one
+		if apierrors.IsNotFound(err) {
+			return nil, &generatorError{key: key, generator: generator.Name, cause: fmt.Errorf("seed Secret %s/%s not found", ref.Namespace, ref.Name)}
+		}
+		return nil, err
+	}
+	seed, ok := seedSecret.Data[ref.Key]
+	if !ok {
+		return nil, &generatorError{key: key, generator: generator.Name, cause: fmt.Errorf("seed Secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)}
+	}
+
+	info := fmt.Sprintf("%s/%s/%s", aSecret.Namespace, aSecret.Name, key)
+	value, err := utils.GenerateDeterministicString(generator.Spec, seed, info)
+	if err != nil {
+		return nil, &generatorError{key: key, generator: generator.Name, cause: err}
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
+)
+
+func deterministicGenerator() *secretsv1alpha1.AGenerator {
+	return &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "seeded-generator"},
+		Spec: secretsv1alpha1.AGeneratorSpec{
+			Length:           24,
+			IncludeLowercase: true,
+			IncludeNumbers:   true,
+			Deterministic:    true,
+			SeedSecretRef:    &secretsv1alpha1.SecretKeyReference{Name: "generator-seed", Namespace: "yaso-system", Key: "seed"},
+		},
+	}
+}
+
+func seededASecret(name string) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: name + "-secret",
+			Provider:         "none",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "seeded-generator"}},
+				"apiKey":   {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "seeded-generator"}},
+			},
+		},
+	}
+}
+
+func TestReconcileDeterministicGenerator(t *testing.T) {
+	seed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "generator-seed", Namespace: "yaso-system"},
+		Data:       map[string][]byte{"seed": []byte("cluster-independent-seed")},
+	}
+	generator := deterministicGenerator()
+
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, seededASecret("first"), seededASecret("second"), generator, seed)
+	ctx := context.Background()
+
+	generated := make(map[string]map[string][]byte)
+	for _, name := range []string{"first", "second"} {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: name, Namespace: "default"}})
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: name + "-secret", Namespace: "default"}, &secret))
+		generated[name] = secret.Data
+	}
+
+	expected, err := utils.GenerateDeterministicString(generator.Spec, []byte("cluster-independent-seed"), "default/first/password")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(generated["first"]["password"]))
+	assert.Len(t, generated["first"]["password"], 24)
+
+	assert.NotEqual(t, generated["first"]["password"], generated["first"]["apiKey"], "keys of one ASecret must differ")
+	assert.NotEqual(t, generated["first"]["password"], generated["second"]["password"], "ASecrets sharing the generator must differ")
+}
+
+func TestReconcileDeterministicGeneratorSeedErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		seed            *corev1.Secret
+		expectedMessage string
+	}{
+		{
+			name:            "missing seed Secret",
+			expectedMessage: "seed Secret yaso-system/generator-seed not found",
+		},
+		{
+			name: "missing seed key",
+			seed: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "generator-seed", Namespace: "yaso-system"},
+				Data:       map[string][]byte{"other": []byte("value")},
+			},
+			expectedMessage: "seed Secret yaso-system/generator-seed has no key seed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{seededASecret("first"), deterministicGenerator()}
+			if tt.seed != nil {
+				objs = append(objs, tt.seed)
+			}
+
+			r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, objs...)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "first", Namespace: "default"}}
+			_, err := r.Reconcile(ctx, req)
+			require.Error(t, err)
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+			generatorCondition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeGeneratorError)
+			require.NotNil(t, generatorCondition)
+			assert.Equal(t, "GeneratorInvalid", generatorCondition.Reason)
+			assert.Contains(t, generatorCondition.Message, tt.expectedMessage)
+		})
+	}
+}
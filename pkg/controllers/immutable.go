@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// isImmutable reports whether the target Secret of the ASecret is immutable
+func isImmutable(aSecret *secretsv1alpha1.ASecret) bool {
+	template := aSecret.Spec.TargetSecretTemplate
+	return template != nil && template.Immutable != nil && *template.Immutable
+}
+
+// applyImmutability marks the Secret immutable when the ASecret asks for it. A mutable Secret can be made
+// immutable by an update, but an immutable one never becomes mutable again, so a Secret already immutable
+// keeps its flag until it is recreated
+func applyImmutability(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret) {
+	if isImmutable(aSecret) {
+		immutable := true
+		secret.Immutable = &immutable
+	}
+}
+
+// requiresRecreate reports whether the update of a Secret that was immutable before is refused by the
+// API server: its data or type changed, or the ASecret no longer wants it immutable
+func requiresRecreate(aSecret *secretsv1alpha1.ASecret, previous, desired *corev1.Secret) bool {
+	if previous.Immutable == nil || !*previous.Immutable {
+		return false
+	}
+	return !isImmutable(aSecret) || previous.Type != desired.Type || !diffSecretData(previous.Data, desired.Data).empty()
+}
+
+// recreateSecret replaces the immutable Secret previous by desired. The delete is conditioned on the
+// version the reconcile read, so a Secret changed or recreated by someone else in the meantime is left alone
+// and the reconcile retried. Pods keep the values of the Secret they mounted until they restart, and pods
+// starting between the delete and the create wait in ContainerCreating until the new Secret exists
+func (r *ASecretReconciler) recreateSecret(ctx context.Context, aSecret *secretsv1alpha1.ASecret, previous, desired *corev1.Secret, log logr.Logger) error {
+	if err := r.Delete(ctx, previous, client.Preconditions{
+		UID:             &previous.UID,
+		ResourceVersion: &previous.ResourceVersion,
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete immutable Secret %s for recreation: %w", previous.Name, err)
+	}
+
+	recreated := desired.DeepCopy()
+	recreated.ResourceVersion = ""
+	recreated.UID = ""
+	recreated.CreationTimestamp = metav1.Time{}
+	recreated.ManagedFields = nil
+	recreated.Immutable = nil
+	applyImmutability(aSecret, recreated)
+	if err := r.Create(ctx, recreated); err != nil {
+		// A finalizer on the old Secret keeps its name taken until it is released
+		return fmt.Errorf("failed to recreate immutable Secret %s: %w", previous.Name, err)
+	}
+
+	log.Info("Recreated immutable Kubernetes Secret, pods mounting it keep the previous values until restarted", "name", recreated.Name)
+	return nil
+}
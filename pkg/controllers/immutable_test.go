@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// setupImmutableReconciler builds a reconciler whose fake client refuses updates of immutable Secrets like the
+// API server does, and counts the Secrets deleted
+func setupImmutableReconciler(t *testing.T, objs ...client.Object) (*ASecretReconciler, client.Client, *int) {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	deletes := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithStatusSubresource(&secretsv1alpha1.ASecret{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if secret, ok := obj.(*corev1.Secret); ok {
+					var stored corev1.Secret
+					if err := c.Get(ctx, client.ObjectKeyFromObject(secret), &stored); err == nil && stored.Immutable != nil && *stored.Immutable {
+						if secret.Immutable == nil || !*secret.Immutable || !diffSecretData(stored.Data, secret.Data).empty() || stored.Type != secret.Type {
+							return apierrors.NewInvalid(schema.GroupKind{Kind: "Secret"}, secret.Name, field.ErrorList{
+								field.Forbidden(field.NewPath("data"), "field is immutable when `immutable` is set"),
+							})
+						}
+					}
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return &ASecretReconciler{
+		Client:    fakeClient,
+		Scheme:    s,
+		Log:       logr.Discard(),
+		AwsClient: &awsclient.AwsClient{},
+	}, fakeClient, &deletes
+}
+
+// immutableASecret defines the keys of the ASecret, each with its name as value
+func immutableASecret(immutable *bool, keys ...string) *secretsv1alpha1.ASecret {
+	data := make(map[string]secretsv1alpha1.DataSource)
+	for _, key := range keys {
+		data[key] = secretsv1alpha1.DataSource{Value: key}
+	}
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "frozen", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName:     "frozen-secret",
+			Provider:             "none",
+			TargetSecretTemplate: &secretsv1alpha1.TargetSecretTemplate{Immutable: immutable},
+			Data:                 data,
+		},
+	}
+}
+
+func existingFrozenSecret(immutable bool) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "frozen-secret",
+			Namespace:   "default",
+			UID:         "old-uid",
+			Annotations: map[string]string{ManagedByAnnotation: "frozen"},
+		},
+		Type:      corev1.SecretTypeOpaque,
+		Immutable: &immutable,
+		Data:      map[string][]byte{"password": []byte("password")},
+	}
+}
+
+func reconcileFrozen(t *testing.T, r *ASecretReconciler, fakeClient client.Client) *corev1.Secret {
+	t.Helper()
+	ctx := context.Background()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "frozen", Namespace: "default"}})
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "frozen-secret", Namespace: "default"}, &secret))
+	return &secret
+}
+
+func TestReconcileCreatesImmutableSecret(t *testing.T) {
+	r, fakeClient, deletes := setupImmutableReconciler(t, immutableASecret(boolPtr(true), "password"))
+
+	secret := reconcileFrozen(t, r, fakeClient)
+	require.NotNil(t, secret.Immutable)
+	assert.True(t, *secret.Immutable)
+	assert.Equal(t, "password", string(secret.Data["password"]))
+	assert.Zero(t, *deletes)
+}
+
+func TestReconcileImmutableSecret(t *testing.T) {
+	tests := []struct {
+		name              string
+		specImmutable     *bool
+		existingImmutable bool
+		keys              []string
+		expectRecreate    bool
+		expectImmutable   bool
+	}{
+		{
+			name:              "changed data recreates the Secret",
+			specImmutable:     boolPtr(true),
+			existingImmutable: true,
+			keys:              []string{"password", "username"},
+			expectRecreate:    true,
+			expectImmutable:   true,
+		},
+		{
+			name:              "unchanged data keeps the Secret",
+			specImmutable:     boolPtr(true),
+			existingImmutable: true,
+			keys:              []string{"password"},
+			expectImmutable:   true,
+		},
+		{
+			name:            "a mutable Secret is made immutable in place",
+			specImmutable:   boolPtr(true),
+			keys:            []string{"password", "username"},
+			expectImmutable: true,
+		},
+		{
+			name:              "dropping immutable recreates a mutable Secret",
+			specImmutable:     boolPtr(false),
+			existingImmutable: true,
+			keys:              []string{"password"},
+			expectRecreate:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, fakeClient, deletes := setupImmutableReconciler(t,
+				immutableASecret(tt.specImmutable, tt.keys...), existingFrozenSecret(tt.existingImmutable))
+
+			secret := reconcileFrozen(t, r, fakeClient)
+			assert.Len(t, secret.Data, len(tt.keys))
+			assert.Equal(t, tt.expectImmutable, secret.Immutable != nil && *secret.Immutable)
+			owner := metav1.GetControllerOf(secret)
+			require.NotNil(t, owner, "a recreated Secret keeps its owner")
+			assert.Equal(t, "frozen", owner.Name)
+			if tt.expectRecreate {
+				assert.Equal(t, 1, *deletes)
+				assert.NotEqual(t, k8sTypes.UID("old-uid"), secret.UID)
+			} else {
+				assert.Zero(t, *deletes)
+				assert.Equal(t, k8sTypes.UID("old-uid"), secret.UID)
+			}
+		})
+	}
+}
+
+func TestRequiresRecreate(t *testing.T) {
+	immutable := immutableASecret(boolPtr(true), "password")
+	changed := existingFrozenSecret(true)
+	changed.Data["password"] = []byte("rotated")
+
+	tests := []struct {
+		name     string
+		aSecret  *secretsv1alpha1.ASecret
+		previous *corev1.Secret
+		desired  *corev1.Secret
+		expected bool
+	}{
+		{
+			name:     "mutable Secrets are updated",
+			aSecret:  immutable,
+			previous: existingFrozenSecret(false),
+			desired:  changed,
+		},
+		{
+			name:     "unchanged immutable Secrets are updated",
+			aSecret:  immutable,
+			previous: existingFrozenSecret(true),
+			desired:  existingFrozenSecret(true),
+		},
+		{
+			name:     "changed data is recreated",
+			aSecret:  immutable,
+			previous: existingFrozenSecret(true),
+			desired:  changed,
+			expected: true,
+		},
+		{
+			name:     "changed type is recreated",
+			aSecret:  immutable,
+			previous: existingFrozenSecret(true),
+			desired: func() *corev1.Secret {
+				secret := existingFrozenSecret(true)
+				secret.Type = corev1.SecretTypeBasicAuth
+				return secret
+			}(),
+			expected: true,
+		},
+		{
+			name:     "no longer immutable is recreated",
+			aSecret:  immutableASecret(nil, "password"),
+			previous: existingFrozenSecret(true),
+			desired:  existingFrozenSecret(true),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, requiresRecreate(tt.aSecret, tt.previous, tt.desired))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// kubeKeySources returns the AWS key each mapped Kubernetes Secret key is read from, the reverse of keyMappings
+func kubeKeySources(aSecret *secretsv1alpha1.ASecret) map[string]string {
+	sources := make(map[string]string, len(aSecret.Spec.KeyMappings))
+	for awsKey, kubeKey := range aSecret.Spec.KeyMappings {
+		sources[kubeKey] = awsKey
+	}
+	return sources
+}
+
+// validateKeyMappings rejects keyMappings writing two AWS keys to the same Kubernetes Secret key,
+// whose values would overwrite each other on import and could not be told apart on push
+func validateKeyMappings(aSecret *secretsv1alpha1.ASecret) error {
+	var errs []error
+	seen := make(map[string]string, len(aSecret.Spec.KeyMappings))
+	for _, awsKey := range sortedKeys(aSecret.Spec.KeyMappings) {
+		kubeKey := aSecret.Spec.KeyMappings[awsKey]
+		if other, exists := seen[kubeKey]; exists {
+			errs = append(errs, fmt.Errorf("keyMappings map both %s and %s to key %s", other, awsKey, kubeKey))
+			continue
+		}
+		seen[kubeKey] = awsKey
+	}
+	return errors.Join(errs...)
+}
+
+// mapAwsKeys renames the keys of an imported AWS secret to their Kubernetes Secret key, unmapped keys pass through.
+// An unmapped AWS key named like the target of a mapping would take the place of the mapped value, so it fails the import
+func mapAwsKeys(aSecret *secretsv1alpha1.ASecret, awsSecretData map[string]string) (map[string]string, error) {
+	if len(aSecret.Spec.KeyMappings) == 0 {
+		return awsSecretData, nil
+	}
+
+	sources := kubeKeySources(aSecret)
+	mapped := make(map[string]string, len(awsSecretData))
+	for _, awsKey := range sortedKeys(awsSecretData) {
+		kubeKey, isMapped := aSecret.Spec.KeyMappings[awsKey]
+		if !isMapped {
+			if source, isTarget := sources[awsKey]; isTarget {
+				return nil, fmt.Errorf("AWS key %s collides with the key mapping of %s to %s", awsKey, source, awsKey)
+			}
+			kubeKey = awsKey
+		}
+		mapped[kubeKey] = awsSecretData[awsKey]
+	}
+	return mapped, nil
+}
+
+// unmapKubeKeys renames the keys of the Kubernetes Secret back to their AWS key before a push, unmapped keys pass through.
+// An unmapped key named like the source of a mapping would be written to the same AWS key as the mapped one, so it fails the push
+func unmapKubeKeys(aSecret *secretsv1alpha1.ASecret, data map[string][]byte) (map[string][]byte, error) {
+	if len(aSecret.Spec.KeyMappings) == 0 {
+		return data, nil
+	}
+
+	sources := kubeKeySources(aSecret)
+	unmapped := make(map[string][]byte, len(data))
+	for _, kubeKey := range sortedKeys(data) {
+		awsKey, isMapped := sources[kubeKey]
+		if !isMapped {
+			if target, isSource := aSecret.Spec.KeyMappings[kubeKey]; isSource {
+				return nil, fmt.Errorf("key %s collides with the key mapping of %s to %s", kubeKey, kubeKey, target)
+			}
+			awsKey = kubeKey
+		}
+		unmapped[awsKey] = data[kubeKey]
+	}
+	return unmapped, nil
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestMapAwsKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		mappings      map[string]string
+		awsData       map[string]string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name:     "mapped key is renamed and others pass through",
+			mappings: map[string]string{"db_password": "DATABASE_PASSWORD"},
+			awsData:  map[string]string{"db_password": "s3cr3t", "region": "eu-west-1"},
+			expected: map[string]string{"DATABASE_PASSWORD": "s3cr3t", "region": "eu-west-1"},
+		},
+		{
+			name:     "chained mappings rename each key once",
+			mappings: map[string]string{"a": "b", "b": "c"},
+			awsData:  map[string]string{"a": "1", "b": "2"},
+			expected: map[string]string{"b": "1", "c": "2"},
+		},
+		{
+			name:     "mapped key absent from AWS",
+			mappings: map[string]string{"db_password": "DATABASE_PASSWORD"},
+			awsData:  map[string]string{"region": "eu-west-1"},
+			expected: map[string]string{"region": "eu-west-1"},
+		},
+		{
+			name:          "unmapped AWS key named like a mapping target",
+			mappings:      map[string]string{"db_password": "DATABASE_PASSWORD"},
+			awsData:       map[string]string{"db_password": "s3cr3t", "DATABASE_PASSWORD": "other"},
+			expectedError: "AWS key DATABASE_PASSWORD collides with the key mapping of db_password to DATABASE_PASSWORD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{KeyMappings: tt.mappings}}
+			mapped, err := mapAwsKeys(aSecret, tt.awsData)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mapped)
+		})
+	}
+}
+
+func TestUnmapKubeKeys(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		KeyMappings: map[string]string{"db_password": "DATABASE_PASSWORD"},
+	}}
+
+	unmapped, err := unmapKubeKeys(aSecret, map[string][]byte{"DATABASE_PASSWORD": []byte("s3cr3t"), "region": []byte("eu-west-1")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"db_password": []byte("s3cr3t"), "region": []byte("eu-west-1")}, unmapped)
+
+	// A data key named like the AWS key would be pushed on top of the mapped value
+	_, err = unmapKubeKeys(aSecret, map[string][]byte{"DATABASE_PASSWORD": []byte("s3cr3t"), "db_password": []byte("other")})
+	assert.EqualError(t, err, "key db_password collides with the key mapping of db_password to DATABASE_PASSWORD")
+}
+
+func TestKeyMappingsReverseRoundTrip(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		KeyMappings: map[string]string{"db_password": "DATABASE_PASSWORD", "a": "b", "b": "c"},
+	}}
+	awsData := map[string]string{"db_password": "s3cr3t", "a": "1", "b": "2", "region": "eu-west-1"}
+
+	mapped, err := mapAwsKeys(aSecret, awsData)
+	require.NoError(t, err)
+	kubeData := make(map[string][]byte, len(mapped))
+	for k, v := range mapped {
+		kubeData[k] = []byte(v)
+	}
+
+	unmapped, err := unmapKubeKeys(aSecret, kubeData)
+	require.NoError(t, err)
+	for k, v := range awsData {
+		assert.Equal(t, []byte(v), unmapped[k], "key %s", k)
+	}
+	assert.Len(t, unmapped, len(awsData))
+}
+
+func TestValidateKeyMappings(t *testing.T) {
+	valid := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		KeyMappings: map[string]string{"db_password": "DATABASE_PASSWORD", "db_user": "DATABASE_USER"},
+	}}
+	assert.NoError(t, validateKeyMappings(valid))
+
+	duplicate := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		KeyMappings: map[string]string{"db_password": "PASSWORD", "password": "PASSWORD"},
+	}}
+	assert.EqualError(t, validateKeyMappings(duplicate), "keyMappings map both db_password and password to key PASSWORD")
+}
+
+func TestReconcileKeyMappings(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mapped", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "mapped-secret",
+			AwsSecretPath:    "/mapped",
+			KeyMappings:      map[string]string{"db_password": "DATABASE_PASSWORD"},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"API_KEY": {Value: "abc123"},
+			},
+		},
+	}
+
+	// The new key is pushed next to the AWS names of the existing ones
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"db_password":"s3cr3t","region":"eu-west-1"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.PutSecretValueInput) bool {
+		return aws.ToString(input.SecretString) == `{"API_KEY":"abc123","db_password":"s3cr3t","region":"eu-west-1"}`
+	})).Return(&secretsmanager.PutSecretValueOutput{}, nil).Once()
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	r.AwsClient.Config.RemoveRemoteKeys = false
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "mapped", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "mapped-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, map[string][]byte{
+		"API_KEY":           []byte("abc123"),
+		"DATABASE_PASSWORD": []byte("s3cr3t"),
+		"region":            []byte("eu-west-1"),
+	}, secret.Data)
+	mockClient.AssertExpectations(t)
+}
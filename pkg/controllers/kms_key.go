@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// awsManagedKmsAlias is the alias of the AWS managed key, which DescribeSecret omits from its output
+const awsManagedKmsAlias = "alias/aws/secretsmanager"
+
+// kmsKeyMatches reports whether the KMS key reported by DescribeSecret is the desired one. DescribeSecret returns
+// the ARN of the key or alias, while the spec can also name a bare key id or alias. An alias cannot be matched
+// against the ARN of the key it points to without KMS access, such a secret is updated once to the alias
+func kmsKeyMatches(desired, current string) bool {
+	if desired == current {
+		return true
+	}
+	if current == "" {
+		return desired == awsManagedKmsAlias
+	}
+	if strings.HasPrefix(desired, "arn:") {
+		return false
+	}
+	if strings.HasPrefix(desired, "alias/") {
+		return strings.HasSuffix(current, ":"+desired)
+	}
+	return strings.HasSuffix(current, ":key/"+desired)
+}
+
+// reconcileAwsKmsKey re-encrypts the existing AWS secret with spec.kmsKeyId when it uses another key.
+// Without a spec key, the key set at creation, including the operator default, or by someone else is kept
+func (r *ASecretReconciler) reconcileAwsKmsKey(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) error {
+	if aSecret.Spec.KmsKeyId == "" {
+		return nil
+	}
+
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	described, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe AWS secret %s: %w", secretPath, err)
+	}
+	current := aws.ToString(described.KmsKeyId)
+	if kmsKeyMatches(aSecret.Spec.KmsKeyId, current) {
+		return nil
+	}
+
+	if _, err := smClient.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
+		SecretId: aws.String(secretPath),
+		KmsKeyId: aws.String(aSecret.Spec.KmsKeyId),
+	}); err != nil {
+		return fmt.Errorf("failed to update KMS key of AWS secret %s: %w", secretPath, err)
+	}
+	log.Info("Updated AWS secret KMS key", "path", secretPath, "previousKmsKeyId", current, "kmsKeyId", aSecret.Spec.KmsKeyId)
+	return nil
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const (
+	testKeyID    = "1234abcd-12ab-34cd-56ef-1234567890ab"
+	testKeyARN   = "arn:aws:kms:eu-west-1:111122223333:key/" + testKeyID
+	testAliasARN = "arn:aws:kms:eu-west-1:111122223333:alias/payments"
+)
+
+func TestKmsKeyMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		current string
+		matches bool
+	}{
+		{name: "same ARN", desired: testKeyARN, current: testKeyARN, matches: true},
+		{name: "key id against key ARN", desired: testKeyID, current: testKeyARN, matches: true},
+		{name: "alias name against alias ARN", desired: "alias/payments", current: testAliasARN, matches: true},
+		{name: "AWS managed key is omitted", desired: "alias/aws/secretsmanager", current: "", matches: true},
+		{name: "other key id", desired: "9999abcd-12ab-34cd-56ef-1234567890ab", current: testKeyARN},
+		{name: "other alias", desired: "alias/billing", current: testAliasARN},
+		{name: "alias against key ARN", desired: "alias/payments", current: testKeyARN},
+		{name: "ARN of another account", desired: "arn:aws:kms:eu-west-1:444455556666:key/" + testKeyID, current: testKeyARN},
+		{name: "key moving off the AWS managed key", desired: testKeyID, current: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, kmsKeyMatches(tt.desired, tt.current))
+		})
+	}
+}
+
+func TestReconcileAwsKmsKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		kmsKeyID     string
+		current      string
+		expectUpdate bool
+	}{
+		{
+			name:         "changed key is updated",
+			kmsKeyID:     "alias/payments-v2",
+			current:      testAliasARN,
+			expectUpdate: true,
+		},
+		{
+			name:     "key id matching the current key ARN is left alone",
+			kmsKeyID: testKeyID,
+			current:  testKeyARN,
+		},
+		{
+			name:    "without a spec key the current one is kept",
+			current: testKeyARN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "encrypted", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "encrypted-secret",
+					AwsSecretPath:    "/encrypted",
+					KmsKeyId:         tt.kmsKeyID,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{
+				KmsKeyId: aws.String(tt.current),
+			}, nil)
+			if tt.expectUpdate {
+				mockClient.On("UpdateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.UpdateSecretInput) bool {
+					return aws.ToString(input.SecretId) == "/encrypted" && aws.ToString(input.KmsKeyId) == tt.kmsKeyID &&
+						input.SecretString == nil && input.SecretBinary == nil && input.Description == nil
+				})).Return(&secretsmanager.UpdateSecretOutput{}, nil).Once()
+			}
+
+			r, _ := setupASecretReconciler(t, mockClient, aSecret)
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "encrypted", Namespace: "default"}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			mockClient.AssertExpectations(t)
+			if !tt.expectUpdate {
+				mockClient.AssertNotCalled(t, "UpdateSecret", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReconcileAwsKmsKeyFailure(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "encrypted", Namespace: "default"},
+		Spec:       secretsv1alpha1.ASecretSpec{AwsSecretPath: "/encrypted", KmsKeyId: "alias/payments-v2"},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{KmsKeyId: aws.String(testAliasARN)}, nil)
+	mockClient.On("UpdateSecret", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException: kms:GenerateDataKey"))
+
+	r := newVerifyWriteReconciler(1)
+	err := r.reconcileAwsKmsKey(context.Background(), mockClient, aSecret, logr.Discard())
+	require.ErrorContains(t, err, "failed to update KMS key of AWS secret /encrypted")
+	assert.ErrorContains(t, err, "AccessDeniedException")
+}
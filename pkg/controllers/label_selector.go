@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ParseObjectLabelSelector parses --object-label-selector, an empty selector matches every ASecret and returns nil
+func ParseObjectLabelSelector(selector string) (labels.Selector, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object label selector %q: %w", selector, err)
+	}
+	return parsed, nil
+}
+
+// objectLabelSelectorPredicate ignores events of ASecrets whose labels do not match the selector, so operators
+// started with distinct selectors can share a cluster. The cache is scoped by the same selector, this guards
+// events that do not come from it
+func objectLabelSelectorPredicate(selector labels.Selector) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return selector.Matches(labels.Set(obj.GetLabels()))
+	})
+}
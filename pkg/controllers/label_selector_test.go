@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestParseObjectLabelSelector(t *testing.T) {
+	selector, err := ParseObjectLabelSelector("")
+	require.NoError(t, err)
+	assert.Nil(t, selector, "an empty selector must not restrict the cache")
+
+	selector, err = ParseObjectLabelSelector("tier=prod,team in (a,b)")
+	require.NoError(t, err)
+	assert.Equal(t, "team in (a,b),tier=prod", selector.String())
+
+	_, err = ParseObjectLabelSelector("tier==prod=")
+	assert.Error(t, err)
+}
+
+func TestObjectLabelSelectorPredicate(t *testing.T) {
+	selector, err := ParseObjectLabelSelector("tier=prod")
+	require.NoError(t, err)
+	p := objectLabelSelectorPredicate(selector)
+
+	withLabels := func(labels map[string]string) *secretsv1alpha1.ASecret {
+		return &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: labels}}
+	}
+	prod := withLabels(map[string]string{"tier": "prod", "team": "a"})
+	staging := withLabels(map[string]string{"tier": "staging"})
+	unlabeled := withLabels(nil)
+
+	assert.True(t, p.Create(event.CreateEvent{Object: prod}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: prod, ObjectNew: prod}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: prod}))
+	assert.True(t, p.Generic(event.GenericEvent{Object: prod}))
+
+	assert.False(t, p.Create(event.CreateEvent{Object: staging}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: staging, ObjectNew: staging}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: staging}))
+	assert.False(t, p.Create(event.CreateEvent{Object: unlabeled}))
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LastChangeAnnotation describes the keys added, changed and removed by the last update of the managed Secret,
+// e.g. "added: a, b; removed: c". It is only written with --record-last-change and never holds a value
+const LastChangeAnnotation = "yet-another-secrets.io/last-change"
+
+// secretChange lists the key names an update of the Secret adds, changes and removes
+type secretChange struct {
+	added   []string
+	changed []string
+	removed []string
+}
+
+// diffSecretData computes the change from the current to the desired Secret data
+func diffSecretData(current, desired map[string][]byte) secretChange {
+	var change secretChange
+	change.added, change.changed, change.removed = diffSecretKeys(current, desired)
+	return change
+}
+
+// empty reports whether the update leaves the data untouched
+func (c secretChange) empty() bool {
+	return len(c.added)+len(c.changed)+len(c.removed) == 0
+}
+
+// String formats the change by key name only, so it is safe to log
+func (c secretChange) String() string {
+	return describeKeyChanges(c.added, c.changed, c.removed)
+}
+
+// recordLastChange sets LastChangeAnnotation when the update changes the data, keeping the previous change otherwise
+func recordLastChange(secret *corev1.Secret, change secretChange) {
+	if change.empty() {
+		return
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[LastChangeAnnotation] = change.String()
+}
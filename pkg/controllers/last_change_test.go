@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestRecordLastChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		change      secretChange
+		expected    map[string]string
+	}{
+		{
+			name:     "change is recorded by key name",
+			change:   diffSecretData(map[string][]byte{"a": []byte("1"), "c": []byte("3")}, map[string][]byte{"a": []byte("2"), "b": []byte("2")}),
+			expected: map[string]string{LastChangeAnnotation: "added: b; changed: a; removed: c"},
+		},
+		{
+			name:        "unchanged data keeps the previous change",
+			annotations: map[string]string{LastChangeAnnotation: "added: b"},
+			change:      diffSecretData(map[string][]byte{"b": []byte("2")}, map[string][]byte{"b": []byte("2")}),
+			expected:    map[string]string{LastChangeAnnotation: "added: b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			recordLastChange(secret, tt.change)
+			assert.Equal(t, tt.expected, secret.Annotations)
+		})
+	}
+}
+
+func TestReconcileLogsSecretChangeWithoutValues(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "app-secret",
+			Provider:         "none",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"password": {ConfigMapRef: &secretsv1alpha1.ConfigMapKeyReference{Name: "app-config", Key: "password"}},
+				"token":    {Value: "token-value"},
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"password": "new-password"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app-secret",
+			Namespace:   "default",
+			Annotations: map[string]string{ManagedByAnnotation: "app"},
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("old-password"),
+			"legacy":   []byte("legacy-value"),
+		},
+	}
+
+	var logs strings.Builder
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret, configMap, secret)
+	r.Log = funcr.New(func(prefix, args string) {
+		logs.WriteString(args + "\n")
+	}, funcr.Options{Verbosity: 1})
+	r.RecordLastChange = true
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "app", Namespace: "default"}})
+	require.NoError(t, err)
+
+	output := logs.String()
+	assert.Contains(t, output, "Updated Kubernetes Secret")
+	assert.Contains(t, output, `"name"="app-secret" "addedKeys"=["token"] "changedKeys"=["password"] "removedKeys"=["legacy"]`)
+	for _, value := range []string{"token-value", "new-password", "old-password", "legacy-value"} {
+		assert.NotContains(t, output, value)
+	}
+
+	var updated corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "app-secret", Namespace: "default"}, &updated))
+	assert.Equal(t, "added: token; changed: password; removed: legacy", updated.Annotations[LastChangeAnnotation])
+}
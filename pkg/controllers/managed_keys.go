@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// ManagedKeysAnnotation lists, sorted and comma-separated, the keys the operator wrote to the managed Secret
+const ManagedKeysAnnotation = "yet-another-secrets.io/managed-keys"
+
+const (
+	// ExternalKeysPreserve leaves keys written by others untouched
+	ExternalKeysPreserve = "Preserve"
+	// ExternalKeysAdopt takes keys written by others over as keys of the ASecret
+	ExternalKeysAdopt = "Adopt"
+)
+
+// externalKeys returns the keys of the Secret that the operator does not own, with their values.
+// Ownership is read from ManagedKeysAnnotation; a Secret without it predates ownership tracking
+// and all its keys are owned. Nothing is external when the ASecret adopts external keys
+func externalKeys(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret) map[string][]byte {
+	if aSecret.Spec.ExternalKeys == ExternalKeysAdopt {
+		return nil
+	}
+	owned, tracked := annotatedKeys(secret)
+	if !tracked {
+		return nil
+	}
+
+	external := make(map[string][]byte)
+	for k, v := range secret.Data {
+		if !owned[k] {
+			external[k] = v
+		}
+	}
+	return external
+}
+
+// preservedKeys returns the keys of the Secret written back as they are: the external keys and,
+// with preserveUnmanagedKeys, the keys neither the spec nor AWS define that the operator never wrote
+func preservedKeys(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret, awsSecretData map[string]string) map[string][]byte {
+	preserved := externalKeys(aSecret, secret)
+	if aSecret.Spec.PreserveUnmanagedKeys == nil || !*aSecret.Spec.PreserveUnmanagedKeys {
+		return preserved
+	}
+
+	owned, _ := annotatedKeys(secret)
+	managed := specKeys(aSecret)
+	derivedKey := dotenvKey(aSecret)
+	for k, v := range secret.Data {
+		if _, inAws := awsSecretData[k]; inAws || managed[k] || owned[k] || k == derivedKey || k == aSecret.Spec.RenderAsSingleKey {
+			continue
+		}
+		if preserved == nil {
+			preserved = make(map[string][]byte)
+		}
+		preserved[k] = v
+	}
+	return preserved
+}
+
+// annotatedKeys returns the keys listed in ManagedKeysAnnotation, and whether the Secret has the annotation
+func annotatedKeys(secret *corev1.Secret) (map[string]bool, bool) {
+	annotation, tracked := secret.Annotations[ManagedKeysAnnotation]
+	if !tracked {
+		return nil, false
+	}
+
+	owned := make(map[string]bool)
+	for _, key := range strings.Split(annotation, ",") {
+		owned[key] = true
+	}
+	return owned, true
+}
+
+// mergeExternalKeys returns the Secret data with the external keys kept next to the rendered keys,
+// and the part of it the operator owns. An external key always keeps its value, even when the ASecret renders it too
+func mergeExternalKeys(rendered, external map[string][]byte, log logr.Logger) (map[string][]byte, map[string][]byte) {
+	if len(external) == 0 {
+		return rendered, rendered
+	}
+
+	data := make(map[string][]byte, len(rendered)+len(external))
+	owned := make(map[string][]byte, len(rendered))
+	for k, v := range rendered {
+		if _, isExternal := external[k]; isExternal {
+			log.V(1).Info("Key of the Secret is managed externally, leaving it untouched", "key", k)
+			continue
+		}
+		data[k] = v
+		owned[k] = v
+	}
+	for k, v := range external {
+		data[k] = v
+	}
+	return data, owned
+}
+
+// setManagedKeysAnnotation records the keys of the data written to the Secret, removing the annotation when there are none
+func setManagedKeysAnnotation(secret *corev1.Secret, data map[string][]byte) {
+	if len(data) == 0 {
+		delete(secret.Annotations, ManagedKeysAnnotation)
+		return
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[ManagedKeysAnnotation] = strings.Join(sortedKeys(data), ",")
+}
+
+// sortedKeys returns the keys of the map in ascending order
+func sortedKeys[V any](data map[string]V) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
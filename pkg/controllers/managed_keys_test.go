@@ -0,0 +1,414 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestSetManagedKeysAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		data        map[string][]byte
+		expected    map[string]string
+	}{
+		{
+			name:     "keys are sorted",
+			data:     map[string][]byte{"password": nil, "api-key": nil, "username": nil},
+			expected: map[string]string{ManagedKeysAnnotation: "api-key,password,username"},
+		},
+		{
+			name:        "other annotations are kept",
+			annotations: map[string]string{"team": "platform", ManagedKeysAnnotation: "old"},
+			data:        map[string][]byte{"token": nil},
+			expected:    map[string]string{"team": "platform", ManagedKeysAnnotation: "token"},
+		},
+		{
+			name:        "empty data removes the annotation",
+			annotations: map[string]string{"team": "platform", ManagedKeysAnnotation: "token"},
+			data:        map[string][]byte{},
+			expected:    map[string]string{"team": "platform"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			setManagedKeysAnnotation(secret, tt.data)
+			assert.Equal(t, tt.expected, secret.Annotations)
+		})
+	}
+}
+
+func TestReconcileKeepsManagedKeysAnnotationInSync(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "managed-secret",
+			Provider:         "none",
+			TargetSecretTemplate: &secretsv1alpha1.TargetSecretTemplate{
+				Annotations: map[string]string{"team": "platform"},
+			},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"host":     {Value: "db.local"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "managed", Namespace: "default"}}
+
+	assertManagedKeys := func(expected string) {
+		t.Helper()
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "managed-secret", Namespace: "default"}, &secret))
+		assert.Equal(t, expected, secret.Annotations[ManagedKeysAnnotation])
+		assert.Equal(t, "platform", secret.Annotations["team"])
+	}
+
+	updateSpec := func(mutate func(data map[string]secretsv1alpha1.DataSource)) {
+		t.Helper()
+		var current secretsv1alpha1.ASecret
+		require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+		mutate(current.Spec.Data)
+		require.NoError(t, fakeClient.Update(ctx, &current))
+	}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertManagedKeys("host,username")
+
+	// Adding a key lists it
+	updateSpec(func(data map[string]secretsv1alpha1.DataSource) {
+		data["port"] = secretsv1alpha1.DataSource{Value: "5432"}
+	})
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertManagedKeys("host,port,username")
+
+	// Removing a key prunes it from the data and the annotation
+	updateSpec(func(data map[string]secretsv1alpha1.DataSource) {
+		delete(data, "host")
+	})
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertManagedKeys("port,username")
+
+	assert.Empty(t, mockClient.Calls)
+}
+
+func TestExternalKeys(t *testing.T) {
+	data := map[string][]byte{"username": []byte("admin"), "ca.crt": []byte("ca")}
+
+	tests := []struct {
+		name         string
+		externalKeys string
+		annotations  map[string]string
+		expected     map[string][]byte
+	}{
+		{
+			name:     "untracked Secret has no external keys",
+			expected: nil,
+		},
+		{
+			name:        "keys missing from the annotation are external",
+			annotations: map[string]string{ManagedKeysAnnotation: "username"},
+			expected:    map[string][]byte{"ca.crt": []byte("ca")},
+		},
+		{
+			name:        "all keys owned",
+			annotations: map[string]string{ManagedKeysAnnotation: "ca.crt,username"},
+			expected:    map[string][]byte{},
+		},
+		{
+			name:         "adopting ASecret owns every key",
+			externalKeys: ExternalKeysAdopt,
+			annotations:  map[string]string{ManagedKeysAnnotation: "username"},
+			expected:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{ExternalKeys: tt.externalKeys}}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}, Data: data}
+			assert.Equal(t, tt.expected, externalKeys(aSecret, secret))
+		})
+	}
+}
+
+func TestMergeExternalKeys(t *testing.T) {
+	rendered := map[string][]byte{"username": []byte("admin"), "token": []byte("new")}
+	external := map[string][]byte{"ca.crt": []byte("ca"), "token": []byte("theirs")}
+
+	data, owned := mergeExternalKeys(rendered, external, logr.Discard())
+	assert.Equal(t, map[string][]byte{"username": []byte("admin"), "token": []byte("theirs"), "ca.crt": []byte("ca")}, data)
+	assert.Equal(t, map[string][]byte{"username": []byte("admin")}, owned)
+
+	data, owned = mergeExternalKeys(rendered, nil, logr.Discard())
+	assert.Equal(t, rendered, data)
+	assert.Equal(t, rendered, owned)
+}
+
+func TestReconcileLeavesExternalKeysIntact(t *testing.T) {
+	tests := []struct {
+		name         string
+		externalKeys string
+		expectedData map[string]string
+		expectedKeys string
+	}{
+		{
+			name:         "external keys are preserved",
+			expectedData: map[string]string{"password": "initial", "port": "5432", "ca.crt": "injected", "token": "from-controller"},
+			expectedKeys: "password,port",
+		},
+		{
+			name:         "adopted keys are merged and pruned like owned keys",
+			externalKeys: ExternalKeysAdopt,
+			expectedData: map[string]string{"password": "initial", "port": "5432", "token": "from-controller"},
+			expectedKeys: "password,port,token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "shared-secret",
+					Provider:         "none",
+					ExternalKeys:     tt.externalKeys,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+						"password": {Value: "initial"},
+					},
+				},
+			}
+
+			r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "shared", Namespace: "default"}}
+			secretName := k8sTypes.NamespacedName{Name: "shared-secret", Namespace: "default"}
+
+			_, err := r.Reconcile(ctx, req)
+			require.NoError(t, err)
+
+			// Another controller adds its own keys to the Secret
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+			secret.Data["ca.crt"] = []byte("injected")
+			secret.Data["token"] = []byte("from-controller")
+			require.NoError(t, fakeClient.Update(ctx, &secret))
+
+			// The ASecret then changes its own keys and starts defining one the other writer owns
+			var current secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+			delete(current.Spec.Data, "username")
+			current.Spec.Data["port"] = secretsv1alpha1.DataSource{Value: "5432"}
+			current.Spec.Data["token"] = secretsv1alpha1.DataSource{Value: "from-spec"}
+			require.NoError(t, fakeClient.Update(ctx, &current))
+
+			for range 2 {
+				_, err = r.Reconcile(ctx, req)
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+			actual := make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				actual[k] = string(v)
+			}
+			assert.Equal(t, tt.expectedData, actual)
+			assert.Equal(t, tt.expectedKeys, secret.Annotations[ManagedKeysAnnotation])
+		})
+	}
+}
+
+func TestReconcileDoesNotPushExternalKeysToAws(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "shared-secret",
+			AwsSecretPath:    "/shared",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared-secret",
+			Namespace:   "default",
+			Annotations: map[string]string{ManagedKeysAnnotation: "username", ManagedByAnnotation: "shared"},
+		},
+		Data: map[string][]byte{"username": []byte("admin"), "ca.crt": []byte("injected")},
+	}
+
+	notFound := &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, notFound)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, notFound).Once()
+	mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+		return aws.ToString(input.SecretString) == `{"username":"admin"}`
+	})).Return(&secretsmanager.CreateSecretOutput{}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, secret)
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "shared", Namespace: "default"}})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var updated corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "shared-secret", Namespace: "default"}, &updated))
+	assert.Equal(t, []byte("injected"), updated.Data["ca.crt"])
+}
+
+func TestPreservedKeys(t *testing.T) {
+	data := map[string][]byte{"username": []byte("admin"), "region": []byte("eu"), "old": []byte("stale"), "note": []byte("hand-written")}
+	awsSecretData := map[string]string{"region": "eu"}
+
+	tests := []struct {
+		name        string
+		preserve    *bool
+		annotations map[string]string
+		expected    map[string][]byte
+	}{
+		{
+			name:     "disabled preserves nothing on an untracked Secret",
+			expected: nil,
+		},
+		{
+			name:     "keys outside the spec and AWS are preserved",
+			preserve: boolPtr(true),
+			expected: map[string][]byte{"old": []byte("stale"), "note": []byte("hand-written")},
+		},
+		{
+			name:        "keys the operator wrote before are not preserved",
+			preserve:    boolPtr(true),
+			annotations: map[string]string{ManagedKeysAnnotation: "note,old,region,username"},
+			expected:    map[string][]byte{},
+		},
+		{
+			name:        "external keys are preserved either way",
+			preserve:    boolPtr(false),
+			annotations: map[string]string{ManagedKeysAnnotation: "region,username"},
+			expected:    map[string][]byte{"old": []byte("stale"), "note": []byte("hand-written")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+				PreserveUnmanagedKeys: tt.preserve,
+				Data:                  map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+			}}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}, Data: data}
+			assert.Equal(t, tt.expected, preservedKeys(aSecret, secret, awsSecretData))
+		})
+	}
+}
+
+func TestReconcilePreservesUnmanagedKeys(t *testing.T) {
+	tests := []struct {
+		name             string
+		preserve         *bool
+		removeRemoteKeys bool
+		expectedPush     string
+		expectedKeys     []string
+	}{
+		{
+			name:         "user-added key stays local",
+			preserve:     boolPtr(true),
+			expectedPush: `{"password":"initial","region":"eu","username":"admin"}`,
+			expectedKeys: []string{"note", "password", "region", "username"},
+		},
+		{
+			name:         "user-added key is pushed to AWS without the option",
+			expectedPush: `{"note":"hand-written","password":"initial","region":"eu","username":"admin"}`,
+			expectedKeys: []string{"note", "password", "region", "username"},
+		},
+		{
+			name:             "pruning drops AWS-sourced keys but keeps the user-added key",
+			preserve:         boolPtr(true),
+			removeRemoteKeys: true,
+			expectedPush:     `{"password":"initial","username":"admin"}`,
+			expectedKeys:     []string{"note", "password", "username"},
+		},
+		{
+			name:             "pruning drops every key outside the spec without the option",
+			removeRemoteKeys: true,
+			expectedPush:     `{"password":"initial","username":"admin"}`,
+			expectedKeys:     []string{"password", "username"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName:      "app-secret",
+					AwsSecretPath:         "/app",
+					PreserveUnmanagedKeys: tt.preserve,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+						"password": {Value: "initial"},
+					},
+				},
+			}
+			// The Secret predates ownership tracking, so no key is external
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app-secret",
+					Namespace:   "default",
+					Annotations: map[string]string{ManagedByAnnotation: "app"},
+				},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("initial"),
+					"region":   []byte("eu"),
+					"note":     []byte("hand-written"),
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"region":"eu","username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			mockClient.On("PutSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.PutSecretValueInput) bool {
+				return aws.ToString(input.SecretString) == tt.expectedPush
+			})).Return(&secretsmanager.PutSecretValueOutput{}, nil)
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, secret)
+			r.AwsClient.Config.RemoveRemoteKeys = tt.removeRemoteKeys
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "app", Namespace: "default"}})
+			require.NoError(t, err)
+			mockClient.AssertExpectations(t)
+
+			var updated corev1.Secret
+			require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "app-secret", Namespace: "default"}, &updated))
+			assert.Equal(t, tt.expectedKeys, sortedKeys(updated.Data))
+			if _, kept := updated.Data["note"]; kept {
+				assert.Equal(t, []byte("hand-written"), updated.Data["note"])
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"sort"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const (
+	// MergePolicyRemoteWins lets AWS override the Kubernetes Secret, spec values only fill missing keys
+	MergePolicyRemoteWins = "RemoteWins"
+	// MergePolicySpecWins lets the value keys of the spec override both AWS and the Kubernetes Secret
+	MergePolicySpecWins = "SpecWins"
+	// MergePolicyKubeWins lets the Kubernetes Secret override AWS
+	MergePolicyKubeWins = "KubeWins"
+)
+
+// mergePolicy returns the merge policy of the ASecret, MergePolicyRemoteWins when unset
+func mergePolicy(aSecret *secretsv1alpha1.ASecret) string {
+	if aSecret.Spec.MergePolicy == "" {
+		return MergePolicyRemoteWins
+	}
+	return aSecret.Spec.MergePolicy
+}
+
+// divergedRemoteKeys returns the keys pushed to AWS whose value differs from the one stored there.
+// Only a policy letting another source win over AWS produces them, and since such keys exist on
+// both sides they have to be pushed explicitly
+func (r *ASecretReconciler) divergedRemoteKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, awsSecretData map[string]string) []string {
+	if mergePolicy(aSecret) == MergePolicyRemoteWins {
+		return nil
+	}
+
+	var diverged []string
+	for key, value := range r.filterAwsUpdateData(aSecret, secretData) {
+		if remote, ok := awsSecretData[key]; ok && !sameRemoteValue(aSecret, value, remote) {
+			diverged = append(diverged, key)
+		}
+	}
+	sort.Strings(diverged)
+	return diverged
+}
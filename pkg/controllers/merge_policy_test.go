@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestReconcileMergePolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		expectedData map[string]string
+		expectedPush string
+	}{
+		{
+			name:         "default lets AWS win",
+			expectedData: map[string]string{"password": "from-aws", "region": "eu", "username": "admin"},
+		},
+		{
+			name:         "RemoteWins lets AWS win",
+			policy:       MergePolicyRemoteWins,
+			expectedData: map[string]string{"password": "from-aws", "region": "eu", "username": "admin"},
+		},
+		{
+			name:         "SpecWins lets the value win and pushes it",
+			policy:       MergePolicySpecWins,
+			expectedData: map[string]string{"password": "from-spec", "region": "eu", "username": "admin"},
+			expectedPush: `{"password":"from-spec","region":"eu","username":"admin"}`,
+		},
+		{
+			name:         "KubeWins lets the Kubernetes Secret win and pushes it",
+			policy:       MergePolicyKubeWins,
+			expectedData: map[string]string{"password": "from-kube", "region": "eu", "username": "admin"},
+			expectedPush: `{"password":"from-kube","region":"eu","username":"admin"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "app-secret",
+					AwsSecretPath:    "/app",
+					MergePolicy:      tt.policy,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+						"password": {Value: "from-spec"},
+						"region":   {},
+					},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app-secret",
+					Namespace:   "default",
+					Annotations: map[string]string{ManagedByAnnotation: "app"},
+				},
+				Data: map[string][]byte{"username": []byte("admin"), "password": []byte("from-kube")},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"password":"from-aws","region":"eu","username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			if tt.expectedPush != "" {
+				mockClient.On("PutSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.PutSecretValueInput) bool {
+					return aws.ToString(input.SecretString) == tt.expectedPush
+				})).Return(&secretsmanager.PutSecretValueOutput{}, nil).Once()
+			}
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, secret)
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "app", Namespace: "default"}})
+			require.NoError(t, err)
+			mockClient.AssertExpectations(t)
+			if tt.expectedPush == "" {
+				mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+			}
+
+			var updated corev1.Secret
+			require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "app-secret", Namespace: "default"}, &updated))
+			actual := make(map[string]string, len(updated.Data))
+			for k, v := range updated.Data {
+				actual[k] = string(v)
+			}
+			assert.Equal(t, tt.expectedData, actual)
+		})
+	}
+}
+
+func TestDivergedRemoteKeys(t *testing.T) {
+	secretData := map[string][]byte{"password": []byte("local"), "region": []byte("eu"), "token": []byte("new")}
+	awsSecretData := map[string]string{"password": "remote", "region": "eu"}
+
+	tests := []struct {
+		policy   string
+		expected []string
+	}{
+		{policy: "", expected: nil},
+		{policy: MergePolicyRemoteWins, expected: nil},
+		{policy: MergePolicySpecWins, expected: []string{"password"}},
+		{policy: MergePolicyKubeWins, expected: []string{"password"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{MergePolicy: tt.policy}}
+			r := &ASecretReconciler{}
+			assert.Equal(t, tt.expected, r.divergedRemoteKeys(aSecret, secretData, awsSecretData))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// namespaceAccess is a permission the operator needs in every watched namespace
+type namespaceAccess struct {
+	group    string
+	resource string
+	verb     string
+}
+
+// String formats the permission like kubectl auth can-i, e.g. "update asecrets.yet-another-secrets.io/status"
+func (a namespaceAccess) String() string {
+	resource, subresource, _ := strings.Cut(a.resource, "/")
+	if a.group != "" {
+		resource += "." + a.group
+	}
+	if subresource != "" {
+		resource += "/" + subresource
+	}
+	return a.verb + " " + resource
+}
+
+// namespaceAccessRules lists the permissions a namespace-scoped Role must grant, the minimum for reconciling ASecrets
+var namespaceAccessRules = func() []namespaceAccess {
+	var rules []namespaceAccess
+	add := func(group, resource string, verbs ...string) {
+		for _, verb := range verbs {
+			rules = append(rules, namespaceAccess{group: group, resource: resource, verb: verb})
+		}
+	}
+	add(secretsv1alpha1.GroupVersion.Group, "asecrets", "get", "list", "watch")
+	add(secretsv1alpha1.GroupVersion.Group, "asecrets/status", "update")
+	add("", "secrets", "get", "list", "watch", "create", "update")
+	add("", "configmaps", "get", "list", "watch")
+	add("", "events", "create")
+	return rules
+}()
+
+// VerifyNamespaceAccess checks through SelfSubjectAccessReviews that the operator's ServiceAccount holds
+// every permission of namespaceAccessRules in each namespace, so a missing RoleBinding fails at startup
+// instead of in every reconcile. All denied permissions are reported at once
+func VerifyNamespaceAccess(ctx context.Context, c client.Client, namespaces []string) error {
+	var errs []error
+	for _, namespace := range namespaces {
+		var denied []string
+		for _, rule := range namespaceAccessRules {
+			resource, subresource, _ := strings.Cut(rule.resource, "/")
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace:   namespace,
+						Group:       rule.group,
+						Resource:    resource,
+						Subresource: subresource,
+						Verb:        rule.verb,
+					},
+				},
+			}
+			if err := c.Create(ctx, review); err != nil {
+				return fmt.Errorf("failed to review access to namespace %s: %w", namespace, err)
+			}
+			if !review.Status.Allowed {
+				denied = append(denied, rule.String())
+			}
+		}
+		if len(denied) > 0 {
+			errs = append(errs, fmt.Errorf("missing permissions in namespace %s: %s", namespace, strings.Join(denied, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
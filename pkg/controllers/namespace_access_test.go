@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// accessReviewClient answers SelfSubjectAccessReviews with allowed, recording each reviewed attribute set
+func accessReviewClient(t *testing.T, allowed func(*authorizationv1.ResourceAttributes) bool, createErr error, reviewed *[]authorizationv1.ResourceAttributes) client.Client {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	return fake.NewClientBuilder().
+		WithScheme(s).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+				if createErr != nil {
+					return createErr
+				}
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				*reviewed = append(*reviewed, *review.Spec.ResourceAttributes)
+				review.Status.Allowed = allowed(review.Spec.ResourceAttributes)
+				return nil
+			},
+		}).
+		Build()
+}
+
+func TestVerifyNamespaceAccess(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowed       func(*authorizationv1.ResourceAttributes) bool
+		createErr     error
+		expectedError string
+	}{
+		{
+			name:    "every permission granted",
+			allowed: func(*authorizationv1.ResourceAttributes) bool { return true },
+		},
+		{
+			name: "missing RoleBinding in one namespace",
+			allowed: func(attrs *authorizationv1.ResourceAttributes) bool {
+				return attrs.Namespace != "team-b"
+			},
+			expectedError: "missing permissions in namespace team-b: get asecrets.yet-another-secrets.io, list asecrets.yet-another-secrets.io, " +
+				"watch asecrets.yet-another-secrets.io, update asecrets.yet-another-secrets.io/status, get secrets, list secrets, watch secrets, " +
+				"create secrets, update secrets, get configmaps, list configmaps, watch configmaps, create events",
+		},
+		{
+			name: "read-only Role on Secrets",
+			allowed: func(attrs *authorizationv1.ResourceAttributes) bool {
+				return attrs.Resource != "secrets" || attrs.Verb == "get" || attrs.Verb == "list" || attrs.Verb == "watch"
+			},
+			expectedError: "missing permissions in namespace team-a: create secrets, update secrets\n" +
+				"missing permissions in namespace team-b: create secrets, update secrets",
+		},
+		{
+			name:          "review request fails",
+			createErr:     errors.New("connection refused"),
+			expectedError: "failed to review access to namespace team-a: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviewed []authorizationv1.ResourceAttributes
+			c := accessReviewClient(t, tt.allowed, tt.createErr, &reviewed)
+
+			err := VerifyNamespaceAccess(context.Background(), c, []string{"team-a", "team-b"})
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, reviewed, 2*len(namespaceAccessRules))
+			assert.Contains(t, reviewed, authorizationv1.ResourceAttributes{
+				Namespace: "team-a", Group: "yet-another-secrets.io", Resource: "asecrets", Subresource: "status", Verb: "update",
+			})
+		})
+	}
+}
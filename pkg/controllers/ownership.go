@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// ManagedByAnnotation names the ASecret managing a Secret created without an owner reference
+const ManagedByAnnotation = "yet-another-secrets.io/managed-by"
+
+// createsOwnerReference reports whether the ASecret is set as controller owner of its Secret, the default
+func createsOwnerReference(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.CreateOwnerReference == nil || *aSecret.Spec.CreateOwnerReference
+}
+
+// applyOwnership links the Secret to the ASecret, with a controller reference or the managed-by annotation.
+// Existing Secrets only get a controller reference if they were previously tracked by the annotation,
+// so Secrets that predate the ASecret are never adopted into cascade deletion
+func (r *ASecretReconciler) applyOwnership(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret, exists bool) error {
+	if createsOwnerReference(aSecret) {
+		if exists && secret.Annotations[ManagedByAnnotation] != aSecret.Name {
+			return nil
+		}
+		if err := controllerutil.SetControllerReference(aSecret, secret, r.Scheme); err != nil {
+			return err
+		}
+		delete(secret.Annotations, ManagedByAnnotation)
+		return nil
+	}
+
+	if metav1.IsControlledBy(secret, aSecret) {
+		if err := controllerutil.RemoveControllerReference(aSecret, secret, r.Scheme); err != nil {
+			return err
+		}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[ManagedByAnnotation] = aSecret.Name
+	return nil
+}
+
+// conflictingOwner returns the name of another ASecret managing the Secret, through its controller reference
+// or the managed-by annotation. It returns "" when the Secret is unmanaged or managed by this ASecret
+func conflictingOwner(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret) string {
+	if owner := metav1.GetControllerOf(secret); owner != nil && owner.Kind == "ASecret" && owner.Name != aSecret.Name {
+		if gv, err := schema.ParseGroupVersion(owner.APIVersion); err == nil && gv.Group == secretsv1alpha1.GroupVersion.Group {
+			return owner.Name
+		}
+	}
+	if name := secret.Annotations[ManagedByAnnotation]; name != "" && name != aSecret.Name {
+		return name
+	}
+	return ""
+}
+
+// managedByRequests maps a Secret carrying the managed-by annotation to a reconcile of its ASecret
+func managedByRequests(_ context.Context, obj client.Object) []ctrl.Request {
+	name := obj.GetAnnotations()[ManagedByAnnotation]
+	if name == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: k8sTypes.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func newOwnershipTestASecret(createOwnerReference *bool) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "default", UID: "asecret-uid"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName:     "owned-secret",
+			Provider:             "none",
+			CreateOwnerReference: createOwnerReference,
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+}
+
+func TestReconcileOwnerReferenceModes(t *testing.T) {
+	tests := []struct {
+		name                 string
+		createOwnerReference *bool
+		expectOwnerReference bool
+	}{
+		{name: "default sets a controller reference", createOwnerReference: nil, expectOwnerReference: true},
+		{name: "enabled sets a controller reference", createOwnerReference: boolPtr(true), expectOwnerReference: true},
+		{name: "disabled sets the managed-by annotation", createOwnerReference: boolPtr(false), expectOwnerReference: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newOwnershipTestASecret(tt.createOwnerReference)
+			r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "owned", Namespace: "default"}})
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "owned-secret", Namespace: "default"}, &secret))
+			assert.Equal(t, []byte("admin"), secret.Data["username"])
+
+			// Secrets without an owner reference are still mapped back to their ASecret by the watch
+			if tt.expectOwnerReference {
+				assert.True(t, metav1.IsControlledBy(&secret, aSecret))
+				assert.NotContains(t, secret.Annotations, ManagedByAnnotation)
+			} else {
+				assert.Empty(t, secret.OwnerReferences)
+				assert.Equal(t, "owned", secret.Annotations[ManagedByAnnotation])
+				assert.Equal(t, []ctrl.Request{{NamespacedName: k8sTypes.NamespacedName{Name: "owned", Namespace: "default"}}}, managedByRequests(ctx, &secret))
+			}
+		})
+	}
+}
+
+func TestApplyOwnership(t *testing.T) {
+	controlled := func(secret *corev1.Secret, aSecret *secretsv1alpha1.ASecret) {
+		secret.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(aSecret, secretsv1alpha1.GroupVersion.WithKind("ASecret"))}
+	}
+
+	tests := []struct {
+		name                 string
+		createOwnerReference *bool
+		prepare              func(*corev1.Secret, *secretsv1alpha1.ASecret)
+		expectOwnerReference bool
+		expectAnnotation     bool
+	}{
+		{
+			name:                 "disabling removes the controller reference",
+			createOwnerReference: boolPtr(false),
+			prepare:              controlled,
+			expectAnnotation:     true,
+		},
+		{
+			name:                 "enabling replaces the managed-by annotation",
+			createOwnerReference: boolPtr(true),
+			prepare: func(secret *corev1.Secret, _ *secretsv1alpha1.ASecret) {
+				secret.Annotations = map[string]string{ManagedByAnnotation: "owned"}
+			},
+			expectOwnerReference: true,
+		},
+		{
+			name:                 "an unmanaged existing Secret is not adopted",
+			createOwnerReference: boolPtr(true),
+			prepare:              func(*corev1.Secret, *secretsv1alpha1.ASecret) {},
+		},
+		{
+			name:                 "an owned Secret stays owned",
+			createOwnerReference: nil,
+			prepare:              controlled,
+			expectOwnerReference: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newOwnershipTestASecret(tt.createOwnerReference)
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "owned-secret", Namespace: "default"}}
+			tt.prepare(secret, aSecret)
+
+			r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{})
+			require.NoError(t, r.applyOwnership(aSecret, secret, true))
+
+			assert.Equal(t, tt.expectOwnerReference, metav1.IsControlledBy(secret, aSecret))
+			if tt.expectAnnotation {
+				assert.Equal(t, "owned", secret.Annotations[ManagedByAnnotation])
+			} else {
+				assert.NotContains(t, secret.Annotations, ManagedByAnnotation)
+			}
+		})
+	}
+}
+
+func TestManagedByRequests(t *testing.T) {
+	annotated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "owned-secret",
+		Namespace:   "team-a",
+		Annotations: map[string]string{ManagedByAnnotation: "owned"},
+	}}
+	assert.Equal(t, []ctrl.Request{{NamespacedName: k8sTypes.NamespacedName{Name: "owned", Namespace: "team-a"}}}, managedByRequests(context.Background(), annotated))
+
+	unmanaged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-a"}}
+	assert.Nil(t, managedByRequests(context.Background(), unmanaged))
+}
+
+func TestReconcileConflictingASecrets(t *testing.T) {
+	tests := []struct {
+		name                 string
+		createOwnerReference *bool
+	}{
+		{name: "Secret owned through a controller reference", createOwnerReference: nil},
+		{name: "Secret tracked by the managed-by annotation", createOwnerReference: boolPtr(false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := newOwnershipTestASecret(tt.createOwnerReference)
+			second := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "intruder", Namespace: "default", UID: "intruder-uid"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "owned-secret",
+					Provider:         "none",
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "root"},
+					},
+				},
+			}
+
+			r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, first, second)
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "owned", Namespace: "default"}})
+			require.NoError(t, err)
+
+			// The second ASecret must leave the Secret alone and report who owns it
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "intruder", Namespace: "default"}})
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "owned-secret", Namespace: "default"}, &secret))
+			assert.Equal(t, []byte("admin"), secret.Data["username"])
+
+			var intruder secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "intruder", Namespace: "default"}, &intruder))
+			conflict := meta.FindStatusCondition(intruder.Status.Conditions, ConditionTypeConflict)
+			require.NotNil(t, conflict)
+			assert.Equal(t, metav1.ConditionTrue, conflict.Status)
+			assert.Contains(t, conflict.Message, "ASecret owned")
+			synced := meta.FindStatusCondition(intruder.Status.Conditions, ConditionTypeSynced)
+			require.NotNil(t, synced)
+			assert.Equal(t, ReasonConflict, synced.Reason)
+
+			// The owning ASecret keeps syncing without a conflict
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "owned", Namespace: "default"}})
+			require.NoError(t, err)
+			var owner secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "owned", Namespace: "default"}, &owner))
+			assert.Nil(t, meta.FindStatusCondition(owner.Status.Conditions, ConditionTypeConflict))
+		})
+	}
+}
+
+func TestConflictingOwner(t *testing.T) {
+	aSecret := newOwnershipTestASecret(nil)
+	other := &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		expected string
+	}{
+		{name: "unmanaged Secret", secret: &corev1.Secret{}, expected: ""},
+		{
+			name: "controlled by this ASecret",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(aSecret, secretsv1alpha1.GroupVersion.WithKind("ASecret"))},
+			}},
+			expected: "",
+		},
+		{
+			name: "controlled by another ASecret",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(other, secretsv1alpha1.GroupVersion.WithKind("ASecret"))},
+			}},
+			expected: "other",
+		},
+		{
+			name: "controlled by another kind",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(other, corev1.SchemeGroupVersion.WithKind("ConfigMap"))},
+			}},
+			expected: "",
+		},
+		{
+			name:     "annotated by another ASecret",
+			secret:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ManagedByAnnotation: "other"}}},
+			expected: "other",
+		},
+		{
+			name:     "annotated by this ASecret",
+			secret:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ManagedByAnnotation: "owned"}}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, conflictingOwner(aSecret, tt.secret))
+		})
+	}
+}
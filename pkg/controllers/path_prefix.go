@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// prefixedSecretKey returns the Kubernetes Secret key of an AWS secret imported through a pathPrefix,
+// the last segment of its name
+func prefixedSecretKey(name string) string {
+	return path.Base(strings.TrimRight(name, "/"))
+}
+
+// listPrefixedSecrets returns the sorted names of the AWS secrets starting with prefix, following pagination.
+// The name filter of ListSecrets ignores case, so the prefix is checked again on each name. Listing fails
+// once more than maxSecrets match, a non-positive maxSecrets disables the cap
+func listPrefixedSecrets(ctx context.Context, smClient awsclient.SecretsManagerAPI, prefix string, maxSecrets int) ([]string, error) {
+	input := &secretsmanager.ListSecretsInput{
+		Filters: []smTypes.Filter{{Key: smTypes.FilterNameStringTypeName, Values: []string{prefix}}},
+	}
+
+	var names []string
+	for {
+		output, err := smClient.ListSecrets(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list AWS secrets under %s: %w", prefix, err)
+		}
+		for _, entry := range output.SecretList {
+			if name := aws.ToString(entry.Name); strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		if maxSecrets > 0 && len(names) > maxSecrets {
+			return nil, fmt.Errorf("AWS path prefix %s matches more than %d secrets, raise --max-prefix-secrets to import them", prefix, maxSecrets)
+		}
+		if aws.ToString(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
+
+// getPrefixedSecrets imports every AWS secret under the pathPrefix as one key per secret. Names that are not
+// valid Secret keys are skipped, and two secrets whose names end with the same segment fail the sync.
+// A secret deleted between listing and reading only leaves its key out
+func (r *ASecretReconciler) getPrefixedSecrets(ctx context.Context, smClient awsclient.SecretsManagerAPI, secret *secretsv1alpha1.ASecret, log logr.Logger) (map[string]string, bool, error) {
+	prefix := r.AwsClient.ResolveSecretPath(secret.Spec.PathPrefix)
+
+	log.V(1).Info("Listing AWS secrets under path prefix", "prefix", prefix)
+	names, err := listPrefixedSecrets(ctx, smClient, prefix, r.AwsClient.Config.MaxPrefixSecrets)
+	if err != nil {
+		return nil, false, err
+	}
+
+	secretData := make(map[string]string, len(names))
+	sources := make(map[string]string, len(names))
+	for _, name := range names {
+		key := prefixedSecretKey(name)
+		if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+			log.Info("Skipping AWS secret whose name does not end with a valid Secret key", "path", name, "key", key)
+			continue
+		}
+		if other, exists := sources[key]; exists {
+			return nil, true, fmt.Errorf("AWS secrets %s and %s under path prefix %s both map to key %q", other, name, prefix, key)
+		}
+
+		result, err := smClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(name),
+		})
+		if err != nil {
+			if _, _, err := r.handleAwsSecretError(err, name, log); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		value := aws.ToString(result.SecretString)
+		if result.SecretString == nil {
+			value = string(result.SecretBinary)
+		}
+		secretData[key] = value
+		sources[key] = name
+	}
+
+	secretData, err = mapAwsKeys(secret, secretData)
+	if err != nil {
+		log.Error(err, "Failed to map AWS secret keys", "prefix", prefix)
+		return nil, true, err
+	}
+
+	log.V(1).Info("Successfully retrieved AWS secrets under path prefix", "prefix", prefix, "keys", len(secretData))
+	return secretData, len(secretData) > 0, nil
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	"github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// mockListPages makes ListSecrets serve the names in pages chained by NextToken
+func mockListPages(mockClient *MockSecretsManagerClient, pages ...[]string) {
+	for i, page := range pages {
+		output := &secretsmanager.ListSecretsOutput{}
+		for _, name := range page {
+			output.SecretList = append(output.SecretList, smTypes.SecretListEntry{Name: aws.String(name)})
+		}
+		if i+1 < len(pages) {
+			output.NextToken = aws.String(string(rune('a' + i + 1)))
+		}
+		token := ""
+		if i > 0 {
+			token = string(rune('a' + i))
+		}
+		mockClient.On("ListSecrets", mock.Anything, mock.MatchedBy(func(input *secretsmanager.ListSecretsInput) bool {
+			return aws.ToString(input.NextToken) == token
+		})).Return(output, nil).Once()
+	}
+}
+
+func TestPrefixedSecretKey(t *testing.T) {
+	assert.Equal(t, "db", prefixedSecretKey("app/prod/db"))
+	assert.Equal(t, "db", prefixedSecretKey("app/prod/team/db"))
+	assert.Equal(t, "db", prefixedSecretKey("db"))
+	assert.Equal(t, "db", prefixedSecretKey("/app/prod/db/"))
+}
+
+func TestListPrefixedSecrets(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         [][]string
+		maxSecrets    int
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:       "pages are followed and names sorted",
+			pages:      [][]string{{"app/prod/b", "app/prod/a"}, {"app/prod/c"}, {"app/prod/d"}},
+			maxSecrets: 10,
+			expected:   []string{"app/prod/a", "app/prod/b", "app/prod/c", "app/prod/d"},
+		},
+		{
+			name:       "names only matching the prefix ignoring case are dropped",
+			pages:      [][]string{{"app/prod/a", "App/Prod/b", "app/production"}},
+			maxSecrets: 10,
+			expected:   []string{"app/prod/a"},
+		},
+		{
+			name:       "at the cap",
+			pages:      [][]string{{"app/prod/a", "app/prod/b"}, {"app/prod/c"}},
+			maxSecrets: 3,
+			expected:   []string{"app/prod/a", "app/prod/b", "app/prod/c"},
+		},
+		{
+			name:          "over the cap",
+			pages:         [][]string{{"app/prod/a", "app/prod/b"}, {"app/prod/c", "app/prod/d"}},
+			maxSecrets:    3,
+			expectedError: "matches more than 3 secrets",
+		},
+		{
+			name:     "no cap",
+			pages:    [][]string{{"app/prod/a", "app/prod/b"}, {"app/prod/c", "app/prod/d"}},
+			expected: []string{"app/prod/a", "app/prod/b", "app/prod/c", "app/prod/d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockSecretsManagerClient{}
+			mockListPages(mockClient, tt.pages...)
+
+			names, err := listPrefixedSecrets(context.Background(), mockClient, "app/prod/", tt.maxSecrets)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, names)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListPrefixedSecretsFiltersByName(t *testing.T) {
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("ListSecrets", mock.Anything, mock.MatchedBy(func(input *secretsmanager.ListSecretsInput) bool {
+		return len(input.Filters) == 1 && input.Filters[0].Key == smTypes.FilterNameStringTypeName && input.Filters[0].Values[0] == "team-a/app/prod/"
+	})).Return(&secretsmanager.ListSecretsOutput{}, nil).Once()
+
+	r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{Config: config.AWSConfig{SecretPathPrefix: "team-a"}}}
+	aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{PathPrefix: "app/prod/"}}
+	data, exists, err := r.getPrefixedSecrets(context.Background(), mockClient, aSecret, logr.Discard())
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Empty(t, data)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetPrefixedSecrets(t *testing.T) {
+	secretValue := func(name string) any {
+		return mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+			return aws.ToString(input.SecretId) == name
+		})
+	}
+
+	t.Run("each secret becomes a key", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		mockListPages(mockClient, []string{"app/prod/db_password", "app/prod/not a key"}, []string{"app/prod/api_key", "app/prod/gone"})
+		mockClient.On("GetSecretValue", mock.Anything, secretValue("app/prod/db_password")).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String("s3cr3t")}, nil)
+		mockClient.On("GetSecretValue", mock.Anything, secretValue("app/prod/api_key")).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"nested":"kept as is"}`)}, nil)
+		mockClient.On("GetSecretValue", mock.Anything, secretValue("app/prod/gone")).Return(nil, &smTypes.ResourceNotFoundException{Message: aws.String("deleted")})
+
+		r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{Config: config.AWSConfig{MaxPrefixSecrets: 10}}}
+		aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+			PathPrefix:  "app/prod/",
+			KeyMappings: map[string]string{"db_password": "DB_PASSWORD"},
+		}}
+		data, exists, err := r.getPrefixedSecrets(context.Background(), mockClient, aSecret, logr.Discard())
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, map[string]string{"DB_PASSWORD": "s3cr3t", "api_key": `{"nested":"kept as is"}`}, data)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("two secrets with the same last segment fail", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		mockListPages(mockClient, []string{"app/prod/db", "app/prod/team/db"})
+		mockClient.On("GetSecretValue", mock.Anything, secretValue("app/prod/db")).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String("one")}, nil)
+
+		r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{}}
+		aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{PathPrefix: "app/prod/"}}
+		_, _, err := r.getPrefixedSecrets(context.Background(), mockClient, aSecret, logr.Discard())
+		assert.ErrorContains(t, err, `app/prod/db and app/prod/team/db under path prefix app/prod/ both map to key "db"`)
+	})
+
+	t.Run("a failed read fails the import", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		mockListPages(mockClient, []string{"app/prod/db"})
+		mockClient.On("GetSecretValue", mock.Anything, secretValue("app/prod/db")).Return(nil, errors.New("access denied"))
+
+		r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{}}
+		aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{PathPrefix: "app/prod/"}}
+		_, _, err := r.getPrefixedSecrets(context.Background(), mockClient, aSecret, logr.Discard())
+		assert.ErrorContains(t, err, "access denied")
+	})
+}
+
+func TestReconcilePathPrefix(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefixed", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "prefixed-secret",
+			PathPrefix:       "app/prod/",
+		},
+	}
+
+	// Only reads are mocked, the import never writes to AWS
+	mockClient := &MockSecretsManagerClient{}
+	mockListPages(mockClient, []string{"app/prod/db_password"}, []string{"app/prod/api_key"})
+	mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+		return aws.ToString(input.SecretId) == "app/prod/db_password"
+	})).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String("s3cr3t")}, nil)
+	mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+		return aws.ToString(input.SecretId) == "app/prod/api_key"
+	})).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String("abc123")}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "prefixed", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "prefixed-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, map[string][]byte{
+		"db_password": []byte("s3cr3t"),
+		"api_key":     []byte("abc123"),
+	}, secret.Data)
+	mockClient.AssertExpectations(t)
+}
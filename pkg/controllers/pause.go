@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// PausedAnnotation freezes an ASecret when set to "true": it is neither synced nor refreshed until removed
+const PausedAnnotation = "yet-another-secrets.io/paused"
+
+// isPaused reports whether reconciles of the ASecret are suspended by the paused annotation
+func isPaused(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Annotations[PausedAnnotation] == "true"
+}
+
+// pausedChangedPredicate triggers a reconcile when the paused annotation is toggled, which does not bump the generation
+func pausedChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetAnnotations()[PausedAnnotation] != e.ObjectNew.GetAnnotations()[PausedAnnotation]
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// setPausedCondition records that the ASecret is paused, only writing the status when the condition is new
+func (r *ASecretReconciler) setPausedCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, log logr.Logger) {
+	changed := meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePaused,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "PausedByAnnotation",
+		Message:            "Reconciliation is paused by the " + PausedAnnotation + " annotation",
+	})
+	if !changed {
+		return
+	}
+
+	if err := r.Status().Update(ctx, aSecret); err != nil {
+		log.Error(err, "Failed to update ASecret status")
+	}
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestASecretPausedPredicate(t *testing.T) {
+	old := &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "app", Generation: 1}}
+	paused := old.DeepCopy()
+	paused.Annotations = map[string]string{PausedAnnotation: "true"}
+	otherAnnotation := old.DeepCopy()
+	otherAnnotation.Annotations = map[string]string{"team": "platform"}
+
+	p := predicate.Or(predicate.GenerationChangedPredicate{}, pausedChangedPredicate())
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: paused}), "pausing must trigger a reconcile")
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: paused, ObjectNew: old}), "unpausing must trigger a reconcile")
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: otherAnnotation}), "other annotations must not trigger a reconcile")
+}
+
+func TestReconcilePausedASecret(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "frozen",
+			Namespace:   "default",
+			Annotations: map[string]string{PausedAnnotation: "true"},
+		},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "frozen-secret",
+			AwsSecretPath:    "/frozen",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	// The mock has no expectations, any AWS call while paused fails the test
+	mockClient := &MockSecretsManagerClient{}
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "frozen", Namespace: "default"}}
+	secretName := k8sTypes.NamespacedName{Name: "frozen-secret", Namespace: "default"}
+
+	for range 2 {
+		result, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result, "a paused ASecret must not requeue on the refresh timer")
+	}
+
+	var secret corev1.Secret
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, secretName, &secret)))
+	assert.Empty(t, mockClient.Calls)
+
+	var current secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+	paused := meta.FindStatusCondition(current.Status.Conditions, ConditionTypePaused)
+	require.NotNil(t, paused)
+	assert.Equal(t, metav1.ConditionTrue, paused.Status)
+	assert.Nil(t, meta.FindStatusCondition(current.Status.Conditions, ConditionTypeSynced))
+
+	// Unpausing resumes syncing and clears the condition
+	delete(current.Annotations, PausedAnnotation)
+	current.Spec.Provider = "none"
+	require.NoError(t, fakeClient.Update(ctx, &current))
+
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+
+	require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+	assert.Equal(t, []byte("admin"), secret.Data["username"])
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+	assert.Nil(t, meta.FindStatusCondition(current.Status.Conditions, ConditionTypePaused))
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, ConditionTypeSynced))
+
+	// Pausing again freezes the Secret even when its spec changes
+	current.Annotations = map[string]string{PausedAnnotation: "true"}
+	current.Spec.Data["username"] = secretsv1alpha1.DataSource{Value: "root"}
+	current.Spec.Data["password"] = secretsv1alpha1.DataSource{Value: "changed"}
+	require.NoError(t, fakeClient.Update(ctx, &current))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+	assert.NotContains(t, secret.Data, "password")
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &current))
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, ConditionTypePaused))
+}
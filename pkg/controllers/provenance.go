@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	"github.com/yaso/yet-another-secrets-operator/pkg/utils"
+)
+
+// SourceAnnotationPrefix prefixes the annotation recording, for each key the operator writes, where its value comes from
+const SourceAnnotationPrefix = "yet-another-secrets.io/source-"
+
+// Values of the source annotations
+const (
+	// SourceAWS marks a key imported from AWS SecretsManager
+	SourceAWS = "aws"
+	// SourceGenerator marks a key produced by an AGenerator
+	SourceGenerator = "generator"
+	// SourceValue marks a key hardcoded in the ASecret
+	SourceValue = "value"
+	// SourceConfigMap marks a key read from a ConfigMap
+	SourceConfigMap = "configmap"
+	// SourceSecret marks a key read from another Secret
+	SourceSecret = "secret"
+	// SourceTemplate marks a key rendered from the other keys, such as the dotenv output or renderAsSingleKey
+	SourceTemplate = "template"
+)
+
+// keySource returns where the value of a key written to the Secret comes from. It is derived from the spec
+// rather than from the path a single reconcile took, so a generated key stays "generator" once it is read back from AWS
+func keySource(aSecret *secretsv1alpha1.ASecret, key string) string {
+	if isImportOnly(aSecret) {
+		return SourceAWS
+	}
+	if key == dotenvKey(aSecret) || key == aSecret.Spec.RenderAsSingleKey {
+		return SourceTemplate
+	}
+
+	dataSource, declared := aSecret.Spec.Data[key]
+	if !declared {
+		// The public half of a generated key pair is stored next to the private key
+		if base, isPublic := strings.CutSuffix(key, utils.PublicKeySuffix); isPublic && aSecret.Spec.Data[base].GeneratorRef != nil {
+			return SourceGenerator
+		}
+		return SourceAWS
+	}
+
+	switch {
+	case dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote, dataSource.RemoteRef != nil:
+		return SourceAWS
+	case hasGenerator(dataSource):
+		return SourceGenerator
+	case dataSource.ConfigMapRef != nil:
+		return SourceConfigMap
+	case dataSource.SecretRef != nil:
+		return SourceSecret
+	case dataSource.Value != "":
+		return SourceValue
+	}
+	return SourceAWS
+}
+
+// setSourceAnnotations records the source of every owned key, dropping the annotations of keys no longer written.
+// Keys that cannot form a valid annotation name are left unannotated
+func setSourceAnnotations(aSecret *secretsv1alpha1.ASecret, secret *corev1.Secret, owned map[string][]byte, log logr.Logger) {
+	for name := range secret.Annotations {
+		if key, isSource := strings.CutPrefix(name, SourceAnnotationPrefix); isSource {
+			if _, exists := owned[key]; !exists {
+				delete(secret.Annotations, name)
+			}
+		}
+	}
+
+	for key := range owned {
+		name := SourceAnnotationPrefix + key
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			log.V(1).Info("Key cannot be recorded in a source annotation", "key", key, "reason", strings.Join(errs, "; "))
+			continue
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[name] = keySource(aSecret, key)
+	}
+}
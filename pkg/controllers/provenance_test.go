@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestKeySource(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username":    {Value: "admin"},
+				"password":    {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "gen"}},
+				"id_rsa":      {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "ssh"}},
+				"apiKey":      {OnlyImportRemote: boolPtr(true)},
+				"DB_PASSWORD": {RemoteRef: &secretsv1alpha1.RemoteReference{Property: ".rds.password"}},
+				"DB_HOST":     {ConfigMapRef: &secretsv1alpha1.ConfigMapKeyReference{Name: "db", Key: "host"}},
+				"TOKEN":       {SecretRef: &secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"}},
+			},
+			TargetSecretTemplate: &secretsv1alpha1.TargetSecretTemplate{
+				Dotenv: &secretsv1alpha1.DotenvTemplate{},
+			},
+		},
+	}
+
+	tests := map[string]string{
+		"username":    SourceValue,
+		"password":    SourceGenerator,
+		"id_rsa.pub":  SourceGenerator,
+		"apiKey":      SourceAWS,
+		"DB_PASSWORD": SourceAWS,
+		"DB_HOST":     SourceConfigMap,
+		"TOKEN":       SourceSecret,
+		".env":        SourceTemplate,
+		"remote-only": SourceAWS,
+		"other.pub":   SourceAWS,
+	}
+	for key, expected := range tests {
+		assert.Equal(t, expected, keySource(aSecret, key), key)
+	}
+
+	importOnly := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		OnlyImportRemote: boolPtr(true),
+		Data:             map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+	}}
+	assert.Equal(t, SourceAWS, keySource(importOnly, "username"), "every key of an import-only ASecret comes from AWS")
+}
+
+func TestSetSourceAnnotations(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{
+		Data: map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+	}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		SourceAnnotationPrefix + "removed": SourceAWS,
+		"team":                             "platform",
+	}}}
+
+	setSourceAnnotations(aSecret, secret, map[string][]byte{
+		"username": []byte("admin"),
+		"token_":   []byte("not a valid annotation name"),
+	}, logr.Discard())
+
+	assert.Equal(t, map[string]string{
+		SourceAnnotationPrefix + "username": SourceValue,
+		"team":                              "platform",
+	}, secret.Annotations)
+}
+
+func TestReconcileRecordsKeySources(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "audited", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "audited-secret",
+			AwsSecretPath:    "/audited",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"}},
+				"apiKey":   {OnlyImportRemote: boolPtr(true)},
+			},
+		},
+	}
+	generator := &secretsv1alpha1.AGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+		Spec:       secretsv1alpha1.AGeneratorSpec{Length: 12, IncludeLowercase: true},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"apiKey":"remote","region":"eu-west-1"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, generator)
+	r.AwsClient.Config.RemoveRemoteKeys = false
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "audited", Namespace: "default"}}
+	expected := map[string]string{
+		SourceAnnotationPrefix + "username": SourceValue,
+		SourceAnnotationPrefix + "password": SourceGenerator,
+		SourceAnnotationPrefix + "apiKey":   SourceAWS,
+		SourceAnnotationPrefix + "region":   SourceAWS,
+	}
+
+	// The generated password is read back from the Secret on the second reconcile, its source must not change
+	for range 2 {
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "audited-secret", Namespace: "default"}, &secret))
+		for name, source := range expected {
+			assert.Equal(t, source, secret.Annotations[name], name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const (
+	// ReconcileModeEvent reconciles on every ASecret spec change and every change to a Secret it manages
+	ReconcileModeEvent = "event"
+	// ReconcileModePoll only reacts to ASecret spec changes, Secret drift is repaired on the next refresh
+	ReconcileModePoll = "poll"
+
+	eventRefreshInterval = time.Hour
+	pollRefreshInterval  = 6 * time.Hour
+)
+
+// ValidateReconcileMode checks that the mode is one of the supported reconcile modes
+func ValidateReconcileMode(mode string) error {
+	switch mode {
+	case "", ReconcileModeEvent, ReconcileModePoll:
+		return nil
+	default:
+		return fmt.Errorf("unsupported reconcile mode %q, must be %s or %s", mode, ReconcileModeEvent, ReconcileModePoll)
+	}
+}
+
+// ValidateRefreshJitter checks that the refresh jitter is a percentage between 0 and 100
+func ValidateRefreshJitter(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("refresh jitter %d%% is out of range, must be between 0 and 100", percent)
+	}
+	return nil
+}
+
+// watchesSecrets reports whether changes to managed Kubernetes Secrets trigger a reconcile
+func (r *ASecretReconciler) watchesSecrets() bool {
+	return r.ReconcileMode != ReconcileModePoll
+}
+
+// refreshInterval returns the per-secret refresh interval, defaulting to 1h in event mode and 6h in poll mode
+func (r *ASecretReconciler) refreshInterval(aSecret *secretsv1alpha1.ASecret) time.Duration {
+	if aSecret.Spec.RefreshInterval != nil && aSecret.Spec.RefreshInterval.Duration > 0 {
+		return aSecret.Spec.RefreshInterval.Duration
+	}
+	if r.ReconcileMode == ReconcileModePoll {
+		return pollRefreshInterval
+	}
+	return eventRefreshInterval
+}
+
+// jitterRefresh spreads the refresh interval uniformly over ±RefreshJitter percent, so ASecrets created
+// together drift apart instead of all hitting AWS at the same instant
+func (r *ASecretReconciler) jitterRefresh(interval time.Duration) time.Duration {
+	if r.RefreshJitter <= 0 || interval <= 0 {
+		return interval
+	}
+	spread := interval * time.Duration(min(r.RefreshJitter, 100)) / 100
+	if spread <= 0 {
+		return interval
+	}
+	return interval - spread + time.Duration(rand.Int64N(int64(2*spread)+1))
+}
+
+// resyncPredicate passes the update events replayed by an informer resync, recognised by an unchanged
+// resource version, which GenerationChangedPredicate would otherwise drop
+func resyncPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion()
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
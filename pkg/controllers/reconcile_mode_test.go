@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestValidateReconcileMode(t *testing.T) {
+	assert.NoError(t, ValidateReconcileMode(""))
+	assert.NoError(t, ValidateReconcileMode(ReconcileModeEvent))
+	assert.NoError(t, ValidateReconcileMode(ReconcileModePoll))
+	assert.EqualError(t, ValidateReconcileMode("push"), `unsupported reconcile mode "push", must be event or poll`)
+}
+
+func TestReconcileModeBehavior(t *testing.T) {
+	tests := []struct {
+		name            string
+		mode            string
+		refreshInterval *metav1.Duration
+		expectWatches   bool
+		expectedRequeue time.Duration
+	}{
+		{name: "unset mode is event-driven", mode: "", expectWatches: true, expectedRequeue: time.Hour},
+		{name: "event mode", mode: ReconcileModeEvent, expectWatches: true, expectedRequeue: time.Hour},
+		{name: "poll mode", mode: ReconcileModePoll, expectWatches: false, expectedRequeue: 6 * time.Hour},
+		{name: "per-secret interval wins in poll mode", mode: ReconcileModePoll, refreshInterval: &metav1.Duration{Duration: 10 * time.Minute}, expectWatches: false, expectedRequeue: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mode", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "mode-secret",
+					Provider:         "none",
+					RefreshInterval:  tt.refreshInterval,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+
+			r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+			r.ReconcileMode = tt.mode
+			assert.Equal(t, tt.expectWatches, r.watchesSecrets())
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "mode", Namespace: "default"}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRequeue, result.RequeueAfter)
+		})
+	}
+}
+
+func TestValidateRefreshJitter(t *testing.T) {
+	assert.NoError(t, ValidateRefreshJitter(0))
+	assert.NoError(t, ValidateRefreshJitter(100))
+	assert.EqualError(t, ValidateRefreshJitter(-1), "refresh jitter -1% is out of range, must be between 0 and 100")
+	assert.EqualError(t, ValidateRefreshJitter(101), "refresh jitter 101% is out of range, must be between 0 and 100")
+}
+
+func TestJitterRefresh(t *testing.T) {
+	assert.Equal(t, time.Hour, (&ASecretReconciler{}).jitterRefresh(time.Hour), "no jitter by default")
+
+	r := &ASecretReconciler{RefreshJitter: 10}
+	spread := false
+	for range 200 {
+		jittered := r.jitterRefresh(time.Hour)
+		assert.GreaterOrEqual(t, jittered, 54*time.Minute)
+		assert.LessOrEqual(t, jittered, 66*time.Minute)
+		spread = spread || jittered != time.Hour
+	}
+	assert.True(t, spread, "requeues should not all land on the base interval")
+}
+
+func TestReconcileJittersRefreshRequeue(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jittered", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "jittered-secret",
+			Provider:         "none",
+			RefreshInterval:  &metav1.Duration{Duration: 2 * time.Hour},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+	r.RefreshJitter = 25
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "jittered", Namespace: "default"}})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.RequeueAfter, 90*time.Minute)
+	assert.LessOrEqual(t, result.RequeueAfter, 150*time.Minute)
+}
+
+func TestResyncPredicate(t *testing.T) {
+	old := &secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "app", Generation: 1, ResourceVersion: "10"}}
+	statusUpdate := old.DeepCopy()
+	statusUpdate.ResourceVersion = "11"
+
+	p := predicate.Or(predicate.GenerationChangedPredicate{}, resyncPredicate())
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: old.DeepCopy()}), "a resync must trigger a reconcile")
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: statusUpdate}), "a status update must not trigger a reconcile")
+	assert.False(t, resyncPredicate().Create(event.CreateEvent{Object: old}))
+}
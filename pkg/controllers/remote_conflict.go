@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// RemotePathRegistry remembers which ASecret writes each AWS secret, so a second ASecret writing the same
+// secret from another namespace is refused instead of overwriting it on alternating reconciles.
+// Claims only live in memory: after a restart, the first ASecret reconciled claims the secret again
+type RemotePathRegistry struct {
+	mu     sync.Mutex
+	owners map[string]k8sTypes.NamespacedName
+	claims map[k8sTypes.NamespacedName]string
+}
+
+// NewRemotePathRegistry returns an empty registry
+func NewRemotePathRegistry() *RemotePathRegistry {
+	return &RemotePathRegistry{
+		owners: make(map[string]k8sTypes.NamespacedName),
+		claims: make(map[k8sTypes.NamespacedName]string),
+	}
+}
+
+// Claim records the ASecret as the writer of the remote secret and returns the ASecret already writing it, if any.
+// Claiming another secret releases the one the ASecret claimed before, e.g. after its awsSecretPath changed
+func (reg *RemotePathRegistry) Claim(remote string, aSecret k8sTypes.NamespacedName) (k8sTypes.NamespacedName, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if owner, claimed := reg.owners[remote]; claimed && owner != aSecret {
+		return owner, false
+	}
+	if previous, claimed := reg.claims[aSecret]; claimed && previous != remote {
+		delete(reg.owners, previous)
+	}
+	reg.owners[remote] = aSecret
+	reg.claims[aSecret] = remote
+	return aSecret, true
+}
+
+// Release forgets the remote secret claimed by the ASecret, once it is deleted or no longer writes to AWS
+func (reg *RemotePathRegistry) Release(aSecret k8sTypes.NamespacedName) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if remote, claimed := reg.claims[aSecret]; claimed {
+		delete(reg.owners, remote)
+		delete(reg.claims, aSecret)
+	}
+}
+
+// remotePathKey identifies the AWS secret the ASecret writes, an ASecret overriding the endpoint writes to another store
+func (r *ASecretReconciler) remotePathKey(aSecret *secretsv1alpha1.ASecret) string {
+	return aSecret.Spec.EndpointURL + "|" + r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+}
+
+// writesRemote reports whether reconciles of the ASecret may push to its AWS secret
+func (r *ASecretReconciler) writesRemote(aSecret *secretsv1alpha1.ASecret) bool {
+	return !r.ReadOnly && !isLocalOnly(aSecret) && !isImportOnly(aSecret) && aSecret.Spec.AwsSecretPath != ""
+}
+
+// claimRemotePath claims the AWS secret of the ASecret when conflict detection is enabled, returning the
+// ASecret that already writes it on a conflict. ASecrets that never push release their claim, reading is harmless
+func (r *ASecretReconciler) claimRemotePath(aSecret *secretsv1alpha1.ASecret) (k8sTypes.NamespacedName, bool) {
+	name := k8sTypes.NamespacedName{Namespace: aSecret.Namespace, Name: aSecret.Name}
+	if r.RemotePaths == nil {
+		return name, true
+	}
+	if !r.writesRemote(aSecret) {
+		r.RemotePaths.Release(name)
+		return name, true
+	}
+	return r.RemotePaths.Claim(r.remotePathKey(aSecret), name)
+}
+
+// setRemotePathConflictCondition records that the AWS secret was left alone because another ASecret writes it
+func (r *ASecretReconciler) setRemotePathConflictCondition(ctx context.Context, aSecret *secretsv1alpha1.ASecret, owner k8sTypes.NamespacedName, log logr.Logger) {
+	err := fmt.Errorf("refusing to write AWS secret %s, it is managed by ASecret %s", r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath), owner)
+	meta.SetStatusCondition(&aSecret.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeRemotePathConflict,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aSecret.Generation,
+		Reason:             "AWSSecretManagedByAnotherASecret",
+		Message:            err.Error(),
+	})
+
+	r.setSyncFailedCondition(ctx, aSecret, ReasonRemotePathConflict, err, log)
+}
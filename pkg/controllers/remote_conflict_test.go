@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestRemotePathRegistry(t *testing.T) {
+	reg := NewRemotePathRegistry()
+	teamA := k8sTypes.NamespacedName{Namespace: "team-a", Name: "db"}
+	teamB := k8sTypes.NamespacedName{Namespace: "team-b", Name: "db"}
+
+	_, claimed := reg.Claim("|/shared/db", teamA)
+	assert.True(t, claimed)
+	_, claimed = reg.Claim("|/shared/db", teamA)
+	assert.True(t, claimed, "reclaiming its own secret must succeed")
+
+	owner, claimed := reg.Claim("|/shared/db", teamB)
+	assert.False(t, claimed)
+	assert.Equal(t, teamA, owner)
+
+	// Moving to another path releases the previous one
+	_, claimed = reg.Claim("|/team-a/db", teamA)
+	assert.True(t, claimed)
+	_, claimed = reg.Claim("|/shared/db", teamB)
+	assert.True(t, claimed)
+
+	reg.Release(teamB)
+	_, claimed = reg.Claim("|/shared/db", teamA)
+	assert.True(t, claimed, "a released secret can be claimed again")
+}
+
+func TestReconcileRemotePathConflict(t *testing.T) {
+	sharedASecret := func(namespace string) *secretsv1alpha1.ASecret {
+		return &secretsv1alpha1.ASecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec: secretsv1alpha1.ASecretSpec{
+				TargetSecretName: "db-secret",
+				AwsSecretPath:    "/shared/db",
+				Data: map[string]secretsv1alpha1.DataSource{
+					"username": {Value: namespace},
+				},
+			},
+		}
+	}
+	first := sharedASecret("team-a")
+	second := sharedASecret("team-b")
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"team-a"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, first, second)
+	r.RemotePaths = NewRemotePathRegistry()
+	ctx := context.Background()
+	firstReq := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "db", Namespace: "team-a"}}
+	secondReq := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "db", Namespace: "team-b"}}
+
+	_, err := r.Reconcile(ctx, firstReq)
+	require.NoError(t, err)
+
+	// The second ASecret is refused before reading or writing AWS, the mock fails on any write
+	calls := len(mockClient.Calls)
+	result, err := r.Reconcile(ctx, secondReq)
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+	assert.Len(t, mockClient.Calls, calls, "a conflicting ASecret must not call AWS")
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, secondReq.NamespacedName, &updated))
+	conflict := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeRemotePathConflict)
+	require.NotNil(t, conflict)
+	assert.Equal(t, metav1.ConditionTrue, conflict.Status)
+	assert.Contains(t, conflict.Message, "managed by ASecret team-a/db")
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, ReasonRemotePathConflict, synced.Reason)
+
+	err = fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "db-secret", Namespace: "team-b"}, &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err), "a conflicting ASecret must not write its Secret")
+
+	// Deleting the first ASecret releases the AWS secret to the second one
+	require.NoError(t, fakeClient.Delete(ctx, first))
+	_, err = r.Reconcile(ctx, firstReq)
+	require.NoError(t, err)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil).Maybe()
+	_, err = r.Reconcile(ctx, secondReq)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, secondReq.NamespacedName, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeRemotePathConflict))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+}
+
+func TestReconcileRemotePathConflictIgnoresImportOnly(t *testing.T) {
+	writer := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "db-secret",
+			AwsSecretPath:    "/shared/db",
+			Data:             map[string]secretsv1alpha1.DataSource{"username": {Value: "admin"}},
+		},
+	}
+	reader := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-b"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "db-secret",
+			AwsSecretPath:    "/shared/db",
+			OnlyImportRemote: boolPtr(true),
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, writer, reader)
+	r.RemotePaths = NewRemotePathRegistry()
+	ctx := context.Background()
+
+	for _, aSecret := range []*secretsv1alpha1.ASecret{writer, reader} {
+		name := k8sTypes.NamespacedName{Name: aSecret.Name, Namespace: aSecret.Namespace}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: name})
+		require.NoError(t, err)
+
+		var updated secretsv1alpha1.ASecret
+		require.NoError(t, fakeClient.Get(ctx, name, &updated))
+		assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced), "%s must sync", name)
+	}
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// refreshRemoteMetadata copies the AWS secret timestamps into the status. It is best effort:
+// a failed DescribeSecret keeps the previous metadata, a missing secret clears it
+func (r *ASecretReconciler) refreshRemoteMetadata(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) {
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	output, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretPath),
+	})
+	if err != nil {
+		if errors.Is(awsclient.ClassifyError(err), yasoerrors.ErrNotFound) {
+			aSecret.Status.RemoteMetadata = nil
+			return
+		}
+		log.V(1).Info("Failed to describe AWS secret, keeping previous remote metadata", "path", secretPath, "error", err.Error())
+		return
+	}
+
+	aSecret.Status.RemoteMetadata = remoteSecretMetadata(output)
+}
+
+// remoteSecretMetadata extracts the timestamps of a DescribeSecret response
+func remoteSecretMetadata(output *secretsmanager.DescribeSecretOutput) *secretsv1alpha1.RemoteSecretMetadata {
+	return &secretsv1alpha1.RemoteSecretMetadata{
+		CreatedDate:      toMetaTime(output.CreatedDate),
+		LastChangedDate:  toMetaTime(output.LastChangedDate),
+		LastAccessedDate: toMetaTime(output.LastAccessedDate),
+	}
+}
+
+// toMetaTime converts an optional AWS timestamp
+func toMetaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	converted := metav1.NewTime(*t)
+	return &converted
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestReconcilePopulatesRemoteMetadata(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	changed := created.Add(48 * time.Hour)
+	accessed := created.Add(72 * time.Hour)
+
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "described", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "described-secret",
+			AwsSecretPath:    "/test/described",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username": "admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.DescribeSecretInput) bool {
+		return *input.SecretId == "/test/described"
+	})).Return(&secretsmanager.DescribeSecretOutput{
+		CreatedDate:      &created,
+		LastChangedDate:  &changed,
+		LastAccessedDate: &accessed,
+	}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "described", Namespace: "default"}})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "described", Namespace: "default"}, &updated))
+	require.NotNil(t, updated.Status.RemoteMetadata)
+	assert.True(t, updated.Status.RemoteMetadata.CreatedDate.Time.Equal(created))
+	assert.True(t, updated.Status.RemoteMetadata.LastChangedDate.Time.Equal(changed))
+	assert.True(t, updated.Status.RemoteMetadata.LastAccessedDate.Time.Equal(accessed))
+}
+
+func TestRefreshRemoteMetadata(t *testing.T) {
+	previous := &secretsv1alpha1.RemoteSecretMetadata{
+		CreatedDate: &metav1.Time{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	changed := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		output   *secretsmanager.DescribeSecretOutput
+		err      error
+		expected *secretsv1alpha1.RemoteSecretMetadata
+	}{
+		{
+			name:   "missing timestamps stay unset",
+			output: &secretsmanager.DescribeSecretOutput{LastChangedDate: &changed},
+			expected: &secretsv1alpha1.RemoteSecretMetadata{
+				LastChangedDate: &metav1.Time{Time: changed},
+			},
+		},
+		{
+			name:     "secret not found clears metadata",
+			err:      &smTypes.ResourceNotFoundException{Message: aws.String("not found")},
+			expected: nil,
+		},
+		{
+			name:     "describe error keeps previous metadata",
+			err:      errors.New("throttled"),
+			expected: previous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				Spec:   secretsv1alpha1.ASecretSpec{AwsSecretPath: "/test/described"},
+				Status: secretsv1alpha1.ASecretStatus{RemoteMetadata: previous.DeepCopy()},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(tt.output, tt.err)
+
+			r := &ASecretReconciler{}
+			r.refreshRemoteMetadata(context.Background(), mockClient, aSecret, logr.Discard())
+
+			assert.Equal(t, tt.expected, aSecret.Status.RemoteMetadata)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// replicaRegionTypes returns the replica regions of the ASecret in the form CreateSecret and ReplicateSecretToRegions take
+func replicaRegionTypes(regions []string) []smTypes.ReplicaRegionType {
+	if len(regions) == 0 {
+		return nil
+	}
+	replicas := make([]smTypes.ReplicaRegionType, 0, len(regions))
+	for _, region := range regions {
+		replicas = append(replicas, smTypes.ReplicaRegionType{Region: aws.String(region)})
+	}
+	return replicas
+}
+
+// isForceOverwriteReplica reports whether secrets already existing in a replica region are overwritten
+func isForceOverwriteReplica(aSecret *secretsv1alpha1.ASecret) bool {
+	return aSecret.Spec.ForceOverwriteReplica != nil && *aSecret.Spec.ForceOverwriteReplica
+}
+
+// replicaChanges compares the desired replica regions with the regions the AWS secret is replicated to.
+// Only regions the operator replicated itself, as recorded in the status, are removed
+func replicaChanges(desired, previous []string, described *secretsmanager.DescribeSecretOutput) (added, removed []string) {
+	current := make(map[string]bool)
+	for _, status := range described.ReplicationStatus {
+		current[aws.ToString(status.Region)] = true
+	}
+
+	for _, region := range desired {
+		if !current[region] {
+			added = append(added, region)
+		}
+	}
+	for _, region := range previous {
+		if current[region] && !slices.Contains(desired, region) {
+			removed = append(removed, region)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// reconcileReplicaRegions replicates the AWS secret to the regions added to spec.replicaRegions and removes
+// it from the regions dropped from it, then records the replicated regions in the status
+func (r *ASecretReconciler) reconcileReplicaRegions(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) error {
+	if len(aSecret.Spec.ReplicaRegions) == 0 && len(aSecret.Status.ReplicaRegions) == 0 {
+		return nil
+	}
+
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	described, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe replication of AWS secret %s: %w", secretPath, err)
+	}
+
+	added, removed := replicaChanges(aSecret.Spec.ReplicaRegions, aSecret.Status.ReplicaRegions, described)
+	if len(added) > 0 {
+		if _, err := smClient.ReplicateSecretToRegions(ctx, &secretsmanager.ReplicateSecretToRegionsInput{
+			SecretId:                    aws.String(secretPath),
+			AddReplicaRegions:           replicaRegionTypes(added),
+			ForceOverwriteReplicaSecret: isForceOverwriteReplica(aSecret),
+		}); err != nil {
+			return fmt.Errorf("failed to replicate AWS secret %s to %v: %w", secretPath, added, err)
+		}
+		log.Info("Replicated AWS secret", "path", secretPath, "regions", added)
+	}
+	if len(removed) > 0 {
+		if _, err := smClient.RemoveRegionsFromReplication(ctx, &secretsmanager.RemoveRegionsFromReplicationInput{
+			SecretId:             aws.String(secretPath),
+			RemoveReplicaRegions: removed,
+		}); err != nil {
+			return fmt.Errorf("failed to remove AWS secret %s replicas in %v: %w", secretPath, removed, err)
+		}
+		log.Info("Removed AWS secret replicas", "path", secretPath, "regions", removed)
+	}
+
+	aSecret.Status.ReplicaRegions = slices.Sorted(slices.Values(aSecret.Spec.ReplicaRegions))
+	return nil
+}
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func describedReplicas(regions ...string) *secretsmanager.DescribeSecretOutput {
+	output := &secretsmanager.DescribeSecretOutput{}
+	for _, region := range regions {
+		output.ReplicationStatus = append(output.ReplicationStatus, smTypes.ReplicationStatusType{
+			Region: aws.String(region),
+			Status: smTypes.StatusTypeInSync,
+		})
+	}
+	return output
+}
+
+func TestReplicaChanges(t *testing.T) {
+	tests := []struct {
+		name            string
+		desired         []string
+		previous        []string
+		current         []string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{
+			name:          "new regions are added",
+			desired:       []string{"us-east-1", "eu-central-1"},
+			expectedAdded: []string{"eu-central-1", "us-east-1"},
+		},
+		{
+			name:     "replicated regions are kept",
+			desired:  []string{"eu-central-1"},
+			previous: []string{"eu-central-1"},
+			current:  []string{"eu-central-1"},
+		},
+		{
+			name:            "dropped regions are removed",
+			desired:         []string{"eu-central-1"},
+			previous:        []string{"eu-central-1", "us-east-1"},
+			current:         []string{"eu-central-1", "us-east-1"},
+			expectedRemoved: []string{"us-east-1"},
+		},
+		{
+			name:    "replicas added outside the operator are left alone",
+			current: []string{"ap-south-1"},
+		},
+		{
+			name:          "a replica removed outside the operator is added back",
+			desired:       []string{"eu-central-1"},
+			previous:      []string{"eu-central-1"},
+			expectedAdded: []string{"eu-central-1"},
+		},
+		{
+			name:     "a dropped region already gone is not removed again",
+			previous: []string{"us-east-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := replicaChanges(tt.desired, tt.previous, describedReplicas(tt.current...))
+			assert.Equal(t, tt.expectedAdded, added)
+			assert.Equal(t, tt.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestCreateAwsSecretWithReplicaRegions(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{
+			AwsSecretPath:  "/test/replicated",
+			ReplicaRegions: []string{"eu-central-1", "us-east-1"},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{})
+	mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+		return len(input.AddReplicaRegions) == 2 &&
+			aws.ToString(input.AddReplicaRegions[0].Region) == "eu-central-1" &&
+			aws.ToString(input.AddReplicaRegions[1].Region) == "us-east-1"
+	})).Return(&secretsmanager.CreateSecretOutput{}, nil)
+
+	r := newVerifyWriteReconciler(1)
+	err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, map[string][]byte{"password": []byte("secret")}, logr.Discard())
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestCreateAwsSecretForceOverwriteReplica(t *testing.T) {
+	tests := []struct {
+		name           string
+		valueType      string
+		force          *bool
+		expectedForced bool
+	}{
+		{name: "string secret with forceOverwriteReplica", force: boolPtr(true), expectedForced: true},
+		{name: "binary secret with forceOverwriteReplica", valueType: "binary", force: boolPtr(true), expectedForced: true},
+		{name: "replicas are not overwritten by default"},
+		{name: "forceOverwriteReplica false", force: boolPtr(false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				Spec: secretsv1alpha1.ASecretSpec{
+					AwsSecretPath:         "/test/replicated",
+					ValueType:             tt.valueType,
+					ReplicaRegions:        []string{"eu-central-1"},
+					ForceOverwriteReplica: tt.force,
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, &smTypes.ResourceNotFoundException{})
+			mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+				return len(input.AddReplicaRegions) == 1 && input.ForceOverwriteReplicaSecret == tt.expectedForced
+			})).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+
+			r := newVerifyWriteReconciler(1)
+			err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, map[string][]byte{"password": []byte("secret")}, logr.Discard())
+			require.NoError(t, err)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcileReplicaRegionsForceOverwrite(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{
+			AwsSecretPath:         "/replicated",
+			ReplicaRegions:        []string{"eu-central-1"},
+			ForceOverwriteReplica: boolPtr(true),
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas(), nil)
+	mockClient.On("ReplicateSecretToRegions", mock.Anything, mock.MatchedBy(func(input *secretsmanager.ReplicateSecretToRegionsInput) bool {
+		return input.ForceOverwriteReplicaSecret
+	})).Return(&secretsmanager.ReplicateSecretToRegionsOutput{}, nil).Once()
+
+	r := newVerifyWriteReconciler(1)
+	require.NoError(t, r.reconcileReplicaRegions(context.Background(), mockClient, aSecret, logr.Discard()))
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileReplicaRegionsChanges(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicated", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "replicated-secret",
+			AwsSecretPath:    "/replicated",
+			ReplicaRegions:   []string{"eu-central-1"},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "replicated", Namespace: "default"}}
+
+	// First reconcile: the existing secret is not replicated yet
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas(), nil).Once()
+	mockClient.On("ReplicateSecretToRegions", mock.Anything, mock.MatchedBy(func(input *secretsmanager.ReplicateSecretToRegionsInput) bool {
+		return aws.ToString(input.SecretId) == "/replicated" && len(input.AddReplicaRegions) == 1 &&
+			aws.ToString(input.AddReplicaRegions[0].Region) == "eu-central-1"
+	})).Return(&secretsmanager.ReplicateSecretToRegionsOutput{}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas("eu-central-1"), nil).Once()
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Equal(t, []string{"eu-central-1"}, updated.Status.ReplicaRegions)
+
+	// Second reconcile: eu-central-1 is swapped for us-east-1
+	updated.Spec.ReplicaRegions = []string{"us-east-1"}
+	require.NoError(t, fakeClient.Update(ctx, &updated))
+
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas("eu-central-1"), nil).Once()
+	mockClient.On("ReplicateSecretToRegions", mock.Anything, mock.MatchedBy(func(input *secretsmanager.ReplicateSecretToRegionsInput) bool {
+		return len(input.AddReplicaRegions) == 1 && aws.ToString(input.AddReplicaRegions[0].Region) == "us-east-1"
+	})).Return(&secretsmanager.ReplicateSecretToRegionsOutput{}, nil).Once()
+	mockClient.On("RemoveRegionsFromReplication", mock.Anything, mock.MatchedBy(func(input *secretsmanager.RemoveRegionsFromReplicationInput) bool {
+		return aws.ToString(input.SecretId) == "/replicated" && assert.ObjectsAreEqual([]string{"eu-central-1"}, input.RemoveReplicaRegions)
+	})).Return(&secretsmanager.RemoveRegionsFromReplicationOutput{}, nil).Once()
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas("us-east-1"), nil).Once()
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	assert.Equal(t, []string{"us-east-1"}, updated.Status.ReplicaRegions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileReplicaRegionsFailure(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicated", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "replicated-secret",
+			AwsSecretPath:    "/replicated",
+			ReplicaRegions:   []string{"eu-central-1"},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(describedReplicas(), nil)
+	mockClient.On("ReplicateSecretToRegions", mock.Anything, mock.Anything).Return(nil, errors.New("AccessDeniedException"))
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "replicated", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.Error(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, ReasonAWSError, synced.Reason)
+	assert.Empty(t, updated.Status.ReplicaRegions)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// validateResourcePolicy rejects a resourcePolicy that is not a JSON object, so it is never sent to AWS
+func validateResourcePolicy(aSecret *secretsv1alpha1.ASecret) error {
+	if aSecret.Spec.ResourcePolicy == "" {
+		return nil
+	}
+	var policy map[string]interface{}
+	if err := unmarshalJSON([]byte(aSecret.Spec.ResourcePolicy), &policy); err != nil {
+		return fmt.Errorf("resourcePolicy is not a JSON object: %w", err)
+	}
+	return nil
+}
+
+// resourcePolicyMatches reports whether the policy returned by GetResourcePolicy is the desired one.
+// AWS does not keep the formatting of the policy, so both are compared in canonical form
+func resourcePolicyMatches(desired, current string) bool {
+	canonicalDesired, ok := canonicalJSON([]byte(desired))
+	if !ok {
+		return false
+	}
+	canonicalCurrent, ok := canonicalJSON([]byte(current))
+	return ok && canonicalDesired == canonicalCurrent
+}
+
+// reconcileAwsResourcePolicy attaches spec.resourcePolicy to the AWS secret when it differs from the attached
+// policy, and deletes the policy once it is cleared from the spec. A policy attached by someone else is only
+// deleted when the operator attached one before, as recorded in the status
+func (r *ASecretReconciler) reconcileAwsResourcePolicy(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, log logr.Logger) error {
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+	if aSecret.Spec.ResourcePolicy == "" {
+		if !aSecret.Status.ResourcePolicyApplied {
+			return nil
+		}
+		if _, err := smClient.DeleteResourcePolicy(ctx, &secretsmanager.DeleteResourcePolicyInput{
+			SecretId: aws.String(secretPath),
+		}); err != nil {
+			return fmt.Errorf("failed to delete resource policy of AWS secret %s: %w", secretPath, err)
+		}
+		log.Info("Deleted AWS secret resource policy", "path", secretPath)
+		aSecret.Status.ResourcePolicyApplied = false
+		return nil
+	}
+
+	current, err := smClient.GetResourcePolicy(ctx, &secretsmanager.GetResourcePolicyInput{
+		SecretId: aws.String(secretPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get resource policy of AWS secret %s: %w", secretPath, err)
+	}
+	if !resourcePolicyMatches(aSecret.Spec.ResourcePolicy, aws.ToString(current.ResourcePolicy)) {
+		// Policies granting public access are refused by AWS, sharing is meant for named accounts
+		if _, err := smClient.PutResourcePolicy(ctx, &secretsmanager.PutResourcePolicyInput{
+			SecretId:          aws.String(secretPath),
+			ResourcePolicy:    aws.String(aSecret.Spec.ResourcePolicy),
+			BlockPublicPolicy: aws.Bool(true),
+		}); err != nil {
+			return fmt.Errorf("failed to put resource policy of AWS secret %s: %w", secretPath, err)
+		}
+		log.Info("Updated AWS secret resource policy", "path", secretPath)
+	}
+	aSecret.Status.ResourcePolicyApplied = true
+	return nil
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const testResourcePolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::444455556666:root"},"Action":"secretsmanager:GetSecretValue","Resource":"*"}]}`
+
+func TestResourcePolicyMatches(t *testing.T) {
+	assert.True(t, resourcePolicyMatches(testResourcePolicy, testResourcePolicy))
+	assert.True(t, resourcePolicyMatches(`{"Version":"2012-10-17","Statement":[]}`, "{\n  \"Statement\" : [ ],\n  \"Version\" : \"2012-10-17\"\n}"), "formatting must not matter")
+	assert.False(t, resourcePolicyMatches(testResourcePolicy, `{"Version":"2012-10-17","Statement":[]}`))
+	assert.False(t, resourcePolicyMatches(testResourcePolicy, ""), "a secret without a policy must get one")
+}
+
+func TestReconcileAwsResourcePolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		resourcePolicy string
+		applied        bool
+		current        *string
+		expectPut      bool
+		expectDelete   bool
+		expectApplied  bool
+	}{
+		{
+			name:           "policy is set on a secret without one",
+			resourcePolicy: testResourcePolicy,
+			expectPut:      true,
+			expectApplied:  true,
+		},
+		{
+			name:           "changed policy is replaced",
+			resourcePolicy: testResourcePolicy,
+			applied:        true,
+			current:        aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+			expectPut:      true,
+			expectApplied:  true,
+		},
+		{
+			name:           "reformatted policy is left alone",
+			resourcePolicy: testResourcePolicy,
+			applied:        true,
+			current:        aws.String("{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [{\"Resource\": \"*\", \"Action\": \"secretsmanager:GetSecretValue\", \"Principal\": {\"AWS\": \"arn:aws:iam::444455556666:root\"}, \"Effect\": \"Allow\"}]\n}"),
+			expectApplied:  true,
+		},
+		{
+			name:         "cleared policy is deleted",
+			applied:      true,
+			expectDelete: true,
+		},
+		{
+			name: "policy the operator did not attach is kept",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "shared-secret",
+					AwsSecretPath:    "/shared",
+					ResourcePolicy:   tt.resourcePolicy,
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+				Status: secretsv1alpha1.ASecretStatus{ResourcePolicyApplied: tt.applied},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			if tt.resourcePolicy != "" {
+				mockClient.On("GetResourcePolicy", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetResourcePolicyInput) bool {
+					return aws.ToString(input.SecretId) == "/shared"
+				})).Return(&secretsmanager.GetResourcePolicyOutput{ResourcePolicy: tt.current}, nil).Once()
+			}
+			if tt.expectPut {
+				mockClient.On("PutResourcePolicy", mock.Anything, mock.MatchedBy(func(input *secretsmanager.PutResourcePolicyInput) bool {
+					return aws.ToString(input.SecretId) == "/shared" && aws.ToString(input.ResourcePolicy) == tt.resourcePolicy &&
+						aws.ToBool(input.BlockPublicPolicy)
+				})).Return(&secretsmanager.PutResourcePolicyOutput{}, nil).Once()
+			}
+			if tt.expectDelete {
+				mockClient.On("DeleteResourcePolicy", mock.Anything, mock.MatchedBy(func(input *secretsmanager.DeleteResourcePolicyInput) bool {
+					return aws.ToString(input.SecretId) == "/shared"
+				})).Return(&secretsmanager.DeleteResourcePolicyOutput{}, nil).Once()
+			}
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "shared", Namespace: "default"}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			mockClient.AssertExpectations(t)
+			if !tt.expectPut {
+				mockClient.AssertNotCalled(t, "PutResourcePolicy", mock.Anything, mock.Anything)
+			}
+			if !tt.expectDelete {
+				mockClient.AssertNotCalled(t, "DeleteResourcePolicy", mock.Anything, mock.Anything)
+			}
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+			assert.Equal(t, tt.expectApplied, updated.Status.ResourcePolicyApplied)
+		})
+	}
+}
+
+func TestReconcileInvalidResourcePolicy(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "shared-secret",
+			AwsSecretPath:    "/shared",
+			ResourcePolicy:   `["not", "an", "object"]`,
+		},
+	}
+
+	// The mock has no expectations, an invalid policy must not reach AWS
+	mockClient := &MockSecretsManagerClient{}
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "shared", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, mockClient.Calls)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, ReasonInvalidSpec, synced.Reason)
+	assert.Contains(t, synced.Message, "resourcePolicy is not a JSON object")
+}
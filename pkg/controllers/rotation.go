@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// isGeneratedKey reports whether the data source is generated, and therefore rotatable
+func isGeneratedKey(dataSource secretsv1alpha1.DataSource) bool {
+	if !hasGenerator(dataSource) || dataSource.Value != "" || dataSource.RemoteRef != nil {
+		return false
+	}
+	return dataSource.OnlyImportRemote == nil || !*dataSource.OnlyImportRemote
+}
+
+// rotationDue reports whether the generated key was last generated longer than the rotation interval ago.
+// Keys without a recorded generation time are not due: their clock starts with this reconcile
+func rotationDue(aSecret *secretsv1alpha1.ASecret, key string, now time.Time) bool {
+	if aSecret.Spec.RotationInterval == nil || aSecret.Spec.RotationInterval.Duration <= 0 {
+		return false
+	}
+	lastRotation, ok := aSecret.Status.LastRotationTimes[key]
+	if !ok {
+		return false
+	}
+	return now.Sub(lastRotation.Time) >= aSecret.Spec.RotationInterval.Duration
+}
+
+// rotateGeneratedKeys removes the generated values due for rotation from the secret data so they are
+// generated again, and returns the rotated keys sorted
+func (r *ASecretReconciler) rotateGeneratedKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte, now time.Time, log logr.Logger) []string {
+	var rotated []string
+	for key, dataSource := range aSecret.Spec.Data {
+		if !isGeneratedKey(dataSource) || !rotationDue(aSecret, key, now) {
+			continue
+		}
+		if _, exists := secretData[key]; !exists {
+			continue
+		}
+		delete(secretData, key)
+		rotated = append(rotated, key)
+	}
+
+	sort.Strings(rotated)
+	if len(rotated) > 0 {
+		log.Info("Rotating generated keys", "keys", rotated, "rotationInterval", aSecret.Spec.RotationInterval.Duration)
+	}
+	return rotated
+}
+
+// missingGeneratedKeys returns the generated keys absent from the secret data, which are about to be generated
+func missingGeneratedKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte) []string {
+	var missing []string
+	for key, dataSource := range aSecret.Spec.Data {
+		if !isGeneratedKey(dataSource) {
+			continue
+		}
+		if _, exists := secretData[key]; !exists {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// recordRotationTimes stores the generation time of the generated keys, starts the clock of generated keys
+// without one and forgets keys that are no longer generated
+func recordRotationTimes(aSecret *secretsv1alpha1.ASecret, generatedKeys []string, now time.Time) {
+	times := make(map[string]metav1.Time)
+	for key, dataSource := range aSecret.Spec.Data {
+		if !isGeneratedKey(dataSource) {
+			continue
+		}
+		if lastRotation, ok := aSecret.Status.LastRotationTimes[key]; ok {
+			times[key] = lastRotation
+		} else {
+			times[key] = metav1.NewTime(now)
+		}
+	}
+	for _, key := range generatedKeys {
+		times[key] = metav1.NewTime(now)
+	}
+
+	if len(times) == 0 {
+		times = nil
+	}
+	aSecret.Status.LastRotationTimes = times
+}
+
+// timeUntilNextRotation returns how long until the next generated key is due for rotation
+func timeUntilNextRotation(aSecret *secretsv1alpha1.ASecret, now time.Time) (time.Duration, bool) {
+	if aSecret.Spec.RotationInterval == nil || aSecret.Spec.RotationInterval.Duration <= 0 {
+		return 0, false
+	}
+
+	var next time.Duration
+	found := false
+	for key, lastRotation := range aSecret.Status.LastRotationTimes {
+		if !isGeneratedKey(aSecret.Spec.Data[key]) {
+			continue
+		}
+		remaining := max(lastRotation.Add(aSecret.Spec.RotationInterval.Duration).Sub(now), time.Second)
+		if !found || remaining < next {
+			next = remaining
+			found = true
+		}
+	}
+	return next, found
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const rotationTestInterval = 90 * 24 * time.Hour
+
+func newRotationTestASecret(lastRotation time.Time) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rotated", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "rotated-secret",
+			AwsSecretPath:    "/test/rotated",
+			RotationInterval: &metav1.Duration{Duration: rotationTestInterval},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"}},
+				"apiKey":   {OnlyImportRemote: boolPtr(true)},
+			},
+		},
+		Status: secretsv1alpha1.ASecretStatus{
+			LastRotationTimes: map[string]metav1.Time{
+				"password": metav1.NewTime(lastRotation),
+			},
+		},
+	}
+}
+
+func TestReconcileRotatesGeneratedKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		expectRotated bool
+	}{
+		{
+			name:          "interval elapsed",
+			elapsed:       rotationTestInterval + time.Hour,
+			expectRotated: true,
+		},
+		{
+			name:          "interval not elapsed",
+			elapsed:       rotationTestInterval - time.Hour,
+			expectRotated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastRotation := time.Now().Add(-tt.elapsed).Truncate(time.Second)
+			aSecret := newRotationTestASecret(lastRotation)
+			generator := &secretsv1alpha1.AGenerator{
+				ObjectMeta: metav1.ObjectMeta{Name: "password-generator"},
+				Spec:       secretsv1alpha1.AGeneratorSpec{Length: 24, IncludeLowercase: true},
+			}
+			existingSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "rotated-secret", Namespace: "default"},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("old-password"),
+					"apiKey":   []byte("remote-api-key"),
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username": "admin", "password": "old-password"}`),
+			}, nil)
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			var pushed map[string]string
+			if tt.expectRotated {
+				mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					input := args.Get(1).(*secretsmanager.PutSecretValueInput)
+					require.NoError(t, json.Unmarshal([]byte(*input.SecretString), &pushed))
+				}).Return(&secretsmanager.PutSecretValueOutput{}, nil)
+			}
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, generator, existingSecret)
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "rotated", Namespace: "default"}})
+			require.NoError(t, err)
+			mockClient.AssertExpectations(t)
+
+			var secret corev1.Secret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "rotated-secret", Namespace: "default"}, &secret))
+			assert.Equal(t, []byte("admin"), secret.Data["username"], "non-generator keys must not be rotated")
+			assert.Equal(t, []byte("remote-api-key"), secret.Data["apiKey"], "imported keys must not be rotated")
+
+			var updated secretsv1alpha1.ASecret
+			require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "rotated", Namespace: "default"}, &updated))
+			rotationTime := updated.Status.LastRotationTimes["password"]
+
+			if tt.expectRotated {
+				assert.NotEqual(t, []byte("old-password"), secret.Data["password"])
+				assert.Len(t, secret.Data["password"], 24)
+				assert.Equal(t, string(secret.Data["password"]), pushed["password"], "rotated value must be pushed to AWS")
+				assert.Equal(t, "admin", pushed["username"])
+				assert.True(t, rotationTime.After(lastRotation))
+			} else {
+				assert.Equal(t, []byte("old-password"), secret.Data["password"])
+				mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+				assert.True(t, rotationTime.Equal(&metav1.Time{Time: lastRotation}))
+			}
+		})
+	}
+}
+
+func TestReconcileWithoutRotationIntervalKeepsGeneratedKeys(t *testing.T) {
+	aSecret := newRotationTestASecret(time.Now().Add(-10 * rotationTestInterval))
+	aSecret.Spec.RotationInterval = nil
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rotated-secret", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("old-password")},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username": "admin", "password": "old-password"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret, existingSecret)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "rotated", Namespace: "default"}})
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "rotated-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, []byte("old-password"), secret.Data["password"])
+	mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+}
+
+func TestRecordRotationTimes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	tests := []struct {
+		name          string
+		previous      map[string]metav1.Time
+		generatedKeys []string
+		expected      map[string]metav1.Time
+	}{
+		{
+			name:          "newly generated key is recorded",
+			generatedKeys: []string{"password"},
+			expected:      map[string]metav1.Time{"password": metav1.NewTime(now)},
+		},
+		{
+			name:     "existing generated key without a time starts its clock",
+			expected: map[string]metav1.Time{"password": metav1.NewTime(now)},
+		},
+		{
+			name:     "previous time is kept",
+			previous: map[string]metav1.Time{"password": earlier},
+			expected: map[string]metav1.Time{"password": earlier},
+		},
+		{
+			name:          "regenerated key is updated",
+			previous:      map[string]metav1.Time{"password": earlier},
+			generatedKeys: []string{"password"},
+			expected:      map[string]metav1.Time{"password": metav1.NewTime(now)},
+		},
+		{
+			name:     "keys no longer generated are forgotten",
+			previous: map[string]metav1.Time{"password": earlier, "username": earlier},
+			expected: map[string]metav1.Time{"password": earlier},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newRotationTestASecret(now)
+			aSecret.Status.LastRotationTimes = tt.previous
+
+			recordRotationTimes(aSecret, tt.generatedKeys, now)
+
+			assert.Equal(t, tt.expected, aSecret.Status.LastRotationTimes)
+		})
+	}
+}
+
+func TestTimeUntilNextRotation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		interval     *metav1.Duration
+		lastRotation time.Time
+		expected     time.Duration
+		expectFound  bool
+	}{
+		{
+			name:         "rotation disabled",
+			interval:     nil,
+			lastRotation: now,
+			expectFound:  false,
+		},
+		{
+			name:         "remaining time of the interval",
+			interval:     &metav1.Duration{Duration: 24 * time.Hour},
+			lastRotation: now.Add(-20 * time.Hour),
+			expected:     4 * time.Hour,
+			expectFound:  true,
+		},
+		{
+			name:         "overdue rotation is retried shortly",
+			interval:     &metav1.Duration{Duration: 24 * time.Hour},
+			lastRotation: now.Add(-48 * time.Hour),
+			expected:     time.Second,
+			expectFound:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := newRotationTestASecret(tt.lastRotation)
+			aSecret.Spec.RotationInterval = tt.interval
+
+			untilRotation, found := timeUntilNextRotation(aSecret, now)
+
+			assert.Equal(t, tt.expectFound, found)
+			assert.Equal(t, tt.expected, untilRotation)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// secretTooLargeError reports a value larger than AWS would accept, caught before it is written
+type secretTooLargeError struct {
+	size  int
+	limit int
+}
+
+func (e *secretTooLargeError) Error() string {
+	return fmt.Sprintf("AWS secret value is %d bytes, larger than the limit of %d bytes set by --max-secret-bytes", e.size, e.limit)
+}
+
+// isSecretTooLarge reports whether err was caused by a value over --max-secret-bytes
+func isSecretTooLarge(err error) bool {
+	var tooLarge *secretTooLargeError
+	return errors.As(err, &tooLarge)
+}
+
+// checkSecretSize fails with a secretTooLargeError when a value of size bytes exceeds --max-secret-bytes.
+// AWS only reports an oversized value as an opaque validation error, so it is refused before the write
+func (r *ASecretReconciler) checkSecretSize(size int) error {
+	if r.AwsClient == nil || r.AwsClient.Config.MaxSecretBytes <= 0 || size <= r.AwsClient.Config.MaxSecretBytes {
+		return nil
+	}
+	return &secretTooLargeError{size: size, limit: r.AwsClient.Config.MaxSecretBytes}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	"github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func TestPrepareAwsSecretStringSizeLimit(t *testing.T) {
+	// {"k":"<value>"} is 8 bytes around the value
+	tests := []struct {
+		name          string
+		limit         int
+		valueLen      int
+		valueType     string
+		expectedError bool
+	}{
+		{name: "at the limit", limit: 64, valueLen: 56},
+		{name: "one byte over the limit", limit: 64, valueLen: 57, expectedError: true},
+		{name: "json at the limit", limit: 64, valueLen: 56, valueType: "json"},
+		{name: "json one byte over the limit", limit: 64, valueLen: 57, valueType: "json", expectedError: true},
+		{name: "disabled", limit: 0, valueLen: 100000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{Config: config.AWSConfig{MaxSecretBytes: tt.limit}}}
+			data := map[string][]byte{"k": []byte(strings.Repeat("a", tt.valueLen))}
+
+			secretString, err := r.prepareAwsSecretString(data, tt.valueType, false, false)
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.True(t, isSecretTooLarge(err))
+				assert.Contains(t, err.Error(), "65 bytes, larger than the limit of 64 bytes")
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, secretString, tt.valueLen+8)
+		})
+	}
+}
+
+func TestCreateOrUpdateAwsSecretSizeLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		valueType string
+		data      map[string][]byte
+	}{
+		{name: "raw", valueType: "raw", data: map[string][]byte{"value": []byte(strings.Repeat("a", 17))}},
+		{name: "binary", valueType: "binary", data: map[string][]byte{"cert": []byte(strings.Repeat("a", 17))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{AwsSecretPath: "/big", ValueType: tt.valueType}}
+
+			// Nothing is written, the mock fails on any write
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil).Maybe()
+
+			r := &ASecretReconciler{AwsClient: &awsclient.AwsClient{Config: config.AWSConfig{MaxSecretBytes: 16}}}
+			err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, tt.data, logr.Discard())
+			assert.True(t, isSecretTooLarge(err))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcileSecretTooLarge(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "big", Namespace: "default", Generation: 1},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "big-secret",
+			AwsSecretPath:    "/big",
+			MergePolicy:      MergePolicySpecWins,
+			Data: map[string]secretsv1alpha1.DataSource{
+				"blob": {Value: strings.Repeat("a", 100)},
+			},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"blob":"small"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	r.AwsClient.Config.MaxSecretBytes = 64
+	r.AwsClient.Config.RemoveRemoteKeys = false
+
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "big", Namespace: "default"}}
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, metav1.ConditionFalse, synced.Status)
+	assert.Equal(t, ReasonSecretTooLarge, synced.Reason)
+	assert.Contains(t, synced.Message, "larger than the limit of 64 bytes")
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSecretTooLarge))
+	mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+
+	// The size is checked before any write, so the Kubernetes Secret does not run ahead of AWS
+	var secret corev1.Secret
+	err = fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "big-secret", Namespace: "default"}, &secret)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Shrinking the value clears the condition
+	updated.Spec.Data["blob"] = secretsv1alpha1.DataSource{Value: "small"}
+	require.NoError(t, fakeClient.Update(context.Background(), &updated))
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSecretTooLarge))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeSynced))
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "big-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, []byte("small"), secret.Data["blob"])
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// sourceSecretMissingError reports that the Secret or the key a secretRef refers to does not exist
+type sourceSecretMissingError struct {
+	namespace string
+	ref       secretsv1alpha1.LocalSecretKeyReference
+	cause     error
+}
+
+func (e *sourceSecretMissingError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("Secret %s/%s referenced by secretRef is missing: %v", e.namespace, e.ref.Name, e.cause)
+	}
+	return fmt.Sprintf("Secret %s/%s has no key %q", e.namespace, e.ref.Name, e.ref.Key)
+}
+
+func (e *sourceSecretMissingError) Unwrap() error {
+	return e.cause
+}
+
+// isSourceSecretMissing reports whether err comes from a secretRef that could not be resolved
+func isSourceSecretMissing(err error) bool {
+	var missing *sourceSecretMissingError
+	return errors.As(err, &missing)
+}
+
+// validateSecretRefs rejects secretRefs reading the target Secret. Its keys are written from the data of
+// the ASecret, so each reconcile would feed the previous value back into itself
+func validateSecretRefs(aSecret *secretsv1alpha1.ASecret) error {
+	var errs []error
+	for _, key := range sortedKeys(aSecret.Spec.Data) {
+		ref := aSecret.Spec.Data[key].SecretRef
+		if ref != nil && ref.Name == aSecret.Spec.TargetSecretName {
+			errs = append(errs, fmt.Errorf("data key %s reads the target Secret %s with secretRef, only other Secrets can be referenced", key, ref.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// secretRefValue reads the value a secretRef points to, from the Secret in the ASecret namespace
+func (r *ASecretReconciler) secretRefValue(ctx context.Context, aSecret *secretsv1alpha1.ASecret, ref *secretsv1alpha1.LocalSecretKeyReference, log logr.Logger) ([]byte, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, k8sTypes.NamespacedName{Namespace: aSecret.Namespace, Name: ref.Name}, &secret); err != nil {
+		log.Error(err, "Failed to get source Secret", "name", ref.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, &sourceSecretMissingError{namespace: aSecret.Namespace, ref: *ref, cause: err}
+		}
+		return nil, err
+	}
+
+	if value, ok := secret.Data[ref.Key]; ok {
+		return value, nil
+	}
+	return nil, &sourceSecretMissingError{namespace: aSecret.Namespace, ref: *ref}
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func newSecretRefASecret(ref secretsv1alpha1.LocalSecretKeyReference) *secretsv1alpha1.ASecret {
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "composed", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "composed-secret",
+			AwsSecretPath:    "/composed",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"TOKEN": {SecretRef: &ref},
+			},
+		},
+	}
+}
+
+func TestProcessASecretDataSecretRef(t *testing.T) {
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-token", Namespace: "default"},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data:       map[string][]byte{"token": []byte("eyJhbGciOi"), "ca.crt": {0x30, 0x82}},
+	}
+	otherNamespace := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-token", Namespace: "other"},
+		Data:       map[string][]byte{"token": []byte("other")},
+	}
+
+	tests := []struct {
+		name          string
+		ref           secretsv1alpha1.LocalSecretKeyReference
+		secretData    map[string][]byte
+		expected      []byte
+		expectMissing bool
+	}{
+		{
+			name:     "value read from the Secret",
+			ref:      secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"},
+			expected: []byte("eyJhbGciOi"),
+		},
+		{
+			name:       "Secret value replaces the previous one",
+			ref:        secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"},
+			secretData: map[string][]byte{"TOKEN": []byte("expired")},
+			expected:   []byte("eyJhbGciOi"),
+		},
+		{
+			name:     "binary value",
+			ref:      secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "ca.crt"},
+			expected: []byte{0x30, 0x82},
+		},
+		{
+			name:          "missing Secret",
+			ref:           secretsv1alpha1.LocalSecretKeyReference{Name: "absent", Key: "token"},
+			expectMissing: true,
+		},
+		{
+			name:          "missing key",
+			ref:           secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "namespace"},
+			expectMissing: true,
+		},
+		{
+			name:          "Secret of another namespace",
+			ref:           secretsv1alpha1.LocalSecretKeyReference{Name: "shared-token", Key: "token"},
+			expectMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupASecretReconciler(t, &MockSecretsManagerClient{}, tokenSecret, otherNamespace)
+			secretData := tt.secretData
+			if secretData == nil {
+				secretData = map[string][]byte{}
+			}
+
+			err := r.processASecretData(context.Background(), newSecretRefASecret(tt.ref), secretData, logr.Discard())
+			if tt.expectMissing {
+				require.Error(t, err)
+				assert.Equal(t, ReasonSecretMissing, dataErrorReason(err))
+				assert.Contains(t, err.Error(), "Secret default/"+tt.ref.Name)
+				assert.NotContains(t, secretData, "TOKEN")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, secretData["TOKEN"])
+		})
+	}
+}
+
+func TestValidateSecretRefs(t *testing.T) {
+	assert.NoError(t, validateSecretRefs(newSecretRefASecret(secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"})))
+
+	err := validateSecretRefs(newSecretRefASecret(secretsv1alpha1.LocalSecretKeyReference{Name: "composed-secret", Key: "TOKEN"}))
+	assert.EqualError(t, err, "data key TOKEN reads the target Secret composed-secret with secretRef, only other Secrets can be referenced")
+}
+
+func TestReconcileSecretRefToTargetSecret(t *testing.T) {
+	aSecret := newSecretRefASecret(secretsv1alpha1.LocalSecretKeyReference{Name: "composed-secret", Key: "TOKEN"})
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "composed-secret", Namespace: "default"},
+		Data:       map[string][]byte{"TOKEN": []byte("previous")},
+	}
+
+	// The mock panics on any AWS call, the loop is refused before AWS is read
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret, target)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "composed", Namespace: "default"}}
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err, "an invalid spec is not retried")
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, metav1.ConditionFalse, synced.Status)
+	assert.Equal(t, ReasonInvalidSpec, synced.Reason)
+	assert.Contains(t, synced.Message, "reads the target Secret composed-secret")
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+	"time"
+)
+
+// withShutdownGrace returns a context that outlives the cancellation of ctx by grace, so a reconcile interrupted by
+// the manager stopping can finish its writes instead of leaving the Secret and the AWS secret out of step.
+// A deadline of ctx still applies, and without a grace period ctx is cancelled as usual
+func withShutdownGrace(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	if grace <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	detached, cancelDetached := context.WithCancelCause(context.WithoutCancel(ctx))
+	graceCtx, cancelGrace := detached, context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		graceCtx, cancelGrace = context.WithDeadline(detached, deadline)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancelDetached(context.Cause(ctx))
+		case <-graceCtx.Done():
+		}
+	})
+	return graceCtx, func() {
+		stop()
+		cancelGrace()
+		cancelDetached(context.Canceled)
+	}
+}
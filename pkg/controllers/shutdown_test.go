@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestWithShutdownGrace(t *testing.T) {
+	t.Run("without a grace period the context is cancelled with its parent", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := withShutdownGrace(parent, 0)
+		defer cancel()
+
+		cancelParent()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("the context outlives its parent for the grace period", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := withShutdownGrace(parent, 50*time.Millisecond)
+		defer cancel()
+
+		cancelParent()
+		assert.NoError(t, ctx.Err())
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context not cancelled after the grace period")
+		}
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("the deadline of the parent still applies", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancelParent()
+		ctx, cancel := withShutdownGrace(parent, time.Hour)
+		defer cancel()
+
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestReconcileInterruptedByShutdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		grace       time.Duration
+		expectWrite bool
+	}{
+		{name: "without a grace period nothing is written", grace: 0},
+		{name: "within the grace period the reconcile finishes", grace: time.Minute, expectWrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "draining", Namespace: "default"},
+				Spec: secretsv1alpha1.ASecretSpec{
+					TargetSecretName: "draining-secret",
+					AwsSecretPath:    "/draining",
+					Data: map[string]secretsv1alpha1.DataSource{
+						"username": {Value: "admin"},
+					},
+				},
+			}
+			notFound := &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+			ctx, stopManager := context.WithCancel(context.Background())
+			defer stopManager()
+
+			// The manager stops while the reconcile is reading AWS, before any write
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				stopManager()
+			}).Return(nil, notFound)
+			if tt.expectWrite {
+				mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(nil, notFound).Once()
+				mockClient.On("CreateSecret", mock.Anything, mock.MatchedBy(func(input *secretsmanager.CreateSecretInput) bool {
+					return aws.ToString(input.SecretString) == `{"username":"admin"}`
+				})).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+				mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			}
+
+			r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+			r.ShutdownGracePeriod = tt.grace
+			req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "draining", Namespace: "default"}}
+			_, err := r.Reconcile(ctx, req)
+
+			var secret corev1.Secret
+			getErr := fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "draining-secret", Namespace: "default"}, &secret)
+			mockClient.AssertExpectations(t)
+			if tt.expectWrite {
+				require.NoError(t, err)
+				require.NoError(t, getErr)
+				assert.Equal(t, []byte("admin"), secret.Data["username"])
+				return
+			}
+
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.True(t, apierrors.IsNotFound(getErr), "no Secret should be written after the cancellation")
+			mockClient.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "PutSecretValue", mock.Anything, mock.Anything)
+		})
+	}
+}
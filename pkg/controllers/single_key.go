@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// renderSingleKey serializes the data like the SecretString written to AWS and stores it under the single key.
+// An empty secret stays empty rather than holding "{}"
+func (r *ASecretReconciler) renderSingleKey(aSecret *secretsv1alpha1.ASecret, singleKey string, secretData map[string][]byte) (map[string][]byte, error) {
+	if len(secretData) == 0 {
+		return map[string][]byte{}, nil
+	}
+	rendered, err := r.prepareAwsSecretString(secretData, aSecret.Spec.ValueType, isFlattenNested(aSecret), isPreserveStringTypes(aSecret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render single key %s: %w", singleKey, err)
+	}
+	return map[string][]byte{singleKey: []byte(rendered)}, nil
+}
+
+// existingKubeSecretData returns the keys of the existing Secret, expanding the JSON of renderAsSingleKey back
+// into the keys it was rendered from. A single key that no longer parses contributes nothing
+func (r *ASecretReconciler) existingKubeSecretData(aSecret *secretsv1alpha1.ASecret, existingSecret *corev1.Secret) map[string][]byte {
+	singleKey := aSecret.Spec.RenderAsSingleKey
+	if singleKey == "" {
+		return existingSecret.Data
+	}
+
+	blob, exists := existingSecret.Data[singleKey]
+	if !exists {
+		return nil
+	}
+	parsed, err := r.parseAwsSecretValue(string(blob), aSecret.Spec.ValueType, isFlattenNested(aSecret))
+	if err != nil {
+		return nil
+	}
+	data := make(map[string][]byte, len(parsed))
+	for k, v := range parsed {
+		data[k] = []byte(v)
+	}
+	return data
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func singleKeyASecret(spec secretsv1alpha1.ASecretSpec) *secretsv1alpha1.ASecret {
+	spec.TargetSecretName = "config-secret"
+	spec.AwsSecretPath = "/app/config"
+	spec.RenderAsSingleKey = "config.json"
+	return &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+func TestReconcileRenderAsSingleKeyImport(t *testing.T) {
+	aSecret := singleKeyASecret(secretsv1alpha1.ASecretSpec{
+		ValueType:        "json",
+		OnlyImportRemote: boolPtr(true),
+	})
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"user": "admin", "db": {"port": 5432, "host": "db.internal"}}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "config", Namespace: "default"}})
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8sTypes.NamespacedName{Name: "config-secret", Namespace: "default"}, &secret))
+	assert.Equal(t, map[string][]byte{
+		"config.json": []byte(`{"db":{"host":"db.internal","port":5432},"user":"admin"}`),
+	}, secret.Data, "the AWS JSON must be stored re-serialized under the single key")
+	assert.Equal(t, "config.json", secret.Annotations[ManagedKeysAnnotation])
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileRenderAsSingleKeyMerge(t *testing.T) {
+	aSecret := singleKeyASecret(secretsv1alpha1.ASecretSpec{
+		Data: map[string]secretsv1alpha1.DataSource{
+			"username": {Value: "admin"},
+			"password": {Value: "initial"},
+		},
+	})
+
+	// AWS already holds both keys, so nothing is pushed and the mock fails on any write
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"password":"rotated","username":"admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "config", Namespace: "default"}}
+	secretName := k8sTypes.NamespacedName{Name: "config-secret", Namespace: "default"}
+
+	// The second reconcile reads the keys back from the single key of the existing Secret
+	for range 2 {
+		_, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(ctx, secretName, &secret))
+		assert.Equal(t, map[string][]byte{
+			"config.json": []byte(`{"password":"rotated","username":"admin"}`),
+		}, secret.Data)
+		assert.Equal(t, SourceTemplate, secret.Annotations[SourceAnnotationPrefix+"config.json"])
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func TestExistingKubeSecretData(t *testing.T) {
+	r := newVerifyWriteReconciler(1)
+	aSecret := singleKeyASecret(secretsv1alpha1.ASecretSpec{})
+
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		expected map[string][]byte
+	}{
+		{
+			name:     "single key is expanded",
+			data:     map[string][]byte{"config.json": []byte(`{"username":"admin"}`)},
+			expected: map[string][]byte{"username": []byte("admin")},
+		},
+		{
+			name: "single key that does not parse contributes nothing",
+			data: map[string][]byte{"config.json": []byte("not json")},
+		},
+		{
+			name: "missing single key contributes nothing",
+			data: map[string][]byte{"username": []byte("admin")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := r.existingKubeSecretData(aSecret, &corev1.Secret{Data: tt.data})
+			if tt.expected == nil {
+				assert.Empty(t, data)
+			} else {
+				assert.Equal(t, tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestRenderSingleKeyOfEmptySecret(t *testing.T) {
+	r := newVerifyWriteReconciler(1)
+	data, err := r.renderKubeSecretData(singleKeyASecret(secretsv1alpha1.ASecretSpec{}), map[string][]byte{})
+	require.NoError(t, err)
+	assert.Empty(t, data, "an empty secret must not be rendered as {}")
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// dataSourceKinds returns the names of the value sources set on a data source, in declaration order
+func dataSourceKinds(dataSource secretsv1alpha1.DataSource) []string {
+	var kinds []string
+	if dataSource.Value != "" {
+		kinds = append(kinds, "value")
+	}
+	if dataSource.GeneratorRef != nil {
+		kinds = append(kinds, "generatorRef")
+	}
+	if len(dataSource.GeneratorRefs) > 0 {
+		kinds = append(kinds, "generatorRefs")
+	}
+	if dataSource.ConfigMapRef != nil {
+		kinds = append(kinds, "configMapRef")
+	}
+	if dataSource.SecretRef != nil {
+		kinds = append(kinds, "secretRef")
+	}
+	if dataSource.RemoteRef != nil {
+		kinds = append(kinds, "remoteRef")
+	}
+	return kinds
+}
+
+// validateDataSources rejects data keys setting more than one value source. The admission webhook refuses them
+// too, but it is optional, and processASecretData would otherwise silently pick one of the sources
+func validateDataSources(aSecret *secretsv1alpha1.ASecret) error {
+	var errs []error
+	for _, key := range sortedKeys(aSecret.Spec.Data) {
+		if kinds := dataSourceKinds(aSecret.Spec.Data[key]); len(kinds) > 1 {
+			errs = append(errs, fmt.Errorf("data key %s sets %s, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed", key, strings.Join(kinds, " and ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateTargetSecretName rejects a targetSecretName the API server would refuse as a Secret name,
+// so the reconcile fails with a clear message instead of an opaque error from the create call
+func validateTargetSecretName(aSecret *secretsv1alpha1.ASecret) error {
+	name := aSecret.Spec.TargetSecretName
+	if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+		return fmt.Errorf("targetSecretName %q is not a valid Secret name: %s", name, strings.Join(msgs, "; "))
+	}
+	return nil
+}
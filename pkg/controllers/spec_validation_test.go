@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestValidateDataSources(t *testing.T) {
+	generatorRef := &secretsv1alpha1.GeneratorReference{Name: "gen"}
+	configMapRef := &secretsv1alpha1.ConfigMapKeyReference{Name: "config", Key: "host"}
+	secretRef := &secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"}
+	remoteRef := &secretsv1alpha1.RemoteReference{Property: ".rds.password"}
+
+	tests := []struct {
+		name          string
+		data          map[string]secretsv1alpha1.DataSource
+		expectedError string
+	}{
+		{
+			name: "one source per key",
+			data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"password": {GeneratorRef: generatorRef},
+				"host":     {ConfigMapRef: configMapRef},
+				"token":    {SecretRef: secretRef},
+				"rds":      {RemoteRef: remoteRef},
+				"apiKey":   {OnlyImportRemote: boolPtr(true)},
+			},
+		},
+		{
+			name:          "value and generatorRef",
+			data:          map[string]secretsv1alpha1.DataSource{"password": {Value: "admin", GeneratorRef: generatorRef}},
+			expectedError: "data key password sets value and generatorRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "value and configMapRef",
+			data:          map[string]secretsv1alpha1.DataSource{"host": {Value: "db.local", ConfigMapRef: configMapRef}},
+			expectedError: "data key host sets value and configMapRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "value and remoteRef",
+			data:          map[string]secretsv1alpha1.DataSource{"rds": {Value: "secret", RemoteRef: remoteRef}},
+			expectedError: "data key rds sets value and remoteRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "generatorRef and configMapRef",
+			data:          map[string]secretsv1alpha1.DataSource{"password": {GeneratorRef: generatorRef, ConfigMapRef: configMapRef}},
+			expectedError: "data key password sets generatorRef and configMapRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "generatorRef and remoteRef",
+			data:          map[string]secretsv1alpha1.DataSource{"password": {GeneratorRef: generatorRef, RemoteRef: remoteRef}},
+			expectedError: "data key password sets generatorRef and remoteRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "configMapRef and remoteRef",
+			data:          map[string]secretsv1alpha1.DataSource{"host": {ConfigMapRef: configMapRef, RemoteRef: remoteRef}},
+			expectedError: "data key host sets configMapRef and remoteRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "configMapRef and secretRef",
+			data:          map[string]secretsv1alpha1.DataSource{"token": {ConfigMapRef: configMapRef, SecretRef: secretRef}},
+			expectedError: "data key token sets configMapRef and secretRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name:          "every source",
+			data:          map[string]secretsv1alpha1.DataSource{"all": {Value: "v", GeneratorRef: generatorRef, ConfigMapRef: configMapRef, RemoteRef: remoteRef}},
+			expectedError: "data key all sets value and generatorRef and configMapRef and remoteRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+		{
+			name: "every invalid key is reported",
+			data: map[string]secretsv1alpha1.DataSource{
+				"b":        {Value: "v", RemoteRef: remoteRef},
+				"a":        {Value: "v", GeneratorRef: generatorRef},
+				"username": {Value: "admin"},
+			},
+			expectedError: "data key a sets value and generatorRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed\n" +
+				"data key b sets value and remoteRef, only one of value, generatorRef, generatorRefs, configMapRef, secretRef and remoteRef is allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDataSources(&secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{Data: tt.data}})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestReconcileRejectsAmbiguousDataSource(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ambiguous", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "ambiguous-secret",
+			AwsSecretPath:    "/ambiguous",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"password": {
+					Value:        "hardcoded",
+					GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "password-generator"},
+				},
+			},
+		},
+	}
+
+	// Any AWS call would panic on the mock without expectations
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "ambiguous", Namespace: "default"}}
+
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, metav1.ConditionFalse, synced.Status)
+	assert.Equal(t, ReasonInvalidSpec, synced.Reason)
+	assert.Contains(t, synced.Message, "data key password sets value and generatorRef")
+
+	err = fakeClient.Get(ctx, k8sTypes.NamespacedName{Name: "ambiguous-secret", Namespace: "default"}, &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err), "no Secret must be written")
+}
+
+func TestValidateTargetSecretName(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetName string
+		valid      bool
+	}{
+		{name: "lowercase name", targetName: "app-credentials", valid: true},
+		{name: "dotted name", targetName: "app.credentials.v2", valid: true},
+		{name: "empty name", targetName: ""},
+		{name: "uppercase letters", targetName: "App-Credentials"},
+		{name: "underscore", targetName: "app_credentials"},
+		{name: "leading dash", targetName: "-app"},
+		{name: "longer than 253 characters", targetName: strings.Repeat("a", 254)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{Spec: secretsv1alpha1.ASecretSpec{TargetSecretName: tt.targetName}}
+			err := validateTargetSecretName(aSecret)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, "is not a valid Secret name")
+		})
+	}
+}
+
+func TestReconcileRejectsInvalidTargetName(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "badname", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "App_Credentials",
+			AwsSecretPath:    "/badname",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+
+	// Any AWS call would panic on the mock without expectations
+	r, fakeClient := setupASecretReconciler(t, &MockSecretsManagerClient{}, aSecret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "badname", Namespace: "default"}}
+
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, &updated))
+	synced := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, metav1.ConditionFalse, synced.Status)
+	assert.Equal(t, ReasonInvalidTargetName, synced.Reason)
+	assert.Contains(t, synced.Message, `targetSecretName "App_Credentials" is not a valid Secret name`)
+
+	var secrets corev1.SecretList
+	require.NoError(t, fakeClient.List(ctx, &secrets))
+	assert.Empty(t, secrets.Items, "no Secret must be created")
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"time"
+
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// startupDelay returns how long the first reconcile of an ASecret since the operator started is deferred, drawn
+// at random within StartupSpread so the ASecrets listed at startup do not all call AWS at once. ASecrets never
+// synced, whose spec changed since their last sync or with a pending force-sync, are reconciled right away,
+// as is every later reconcile
+func (r *ASecretReconciler) startupDelay(aSecret *secretsv1alpha1.ASecret) time.Duration {
+	if r.StartupSpread <= 0 {
+		return 0
+	}
+
+	key := k8sTypes.NamespacedName{Namespace: aSecret.Namespace, Name: aSecret.Name}
+	if _, seen := r.startupSeen.LoadOrStore(key, struct{}{}); seen {
+		return 0
+	}
+	if aSecret.Status.LastSyncTime.IsZero() || aSecret.Status.ObservedGeneration != aSecret.Generation || forceSyncPending(aSecret) {
+		return 0
+	}
+
+	// A zero RequeueAfter would drop the reconcile instead of deferring it
+	return max(randomJitter(r.StartupSpread), time.Millisecond)
+}
+
+// forgetStartupDelay drops a deleted ASecret, so an ASecret recreated under its name is deferred like a new one
+func (r *ASecretReconciler) forgetStartupDelay(name k8sTypes.NamespacedName) {
+	r.startupSeen.Delete(name)
+}
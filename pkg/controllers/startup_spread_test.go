@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func newStartupSpreadASecret(synced bool) *secretsv1alpha1.ASecret {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spread", Namespace: "default", Generation: 2},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "spread-secret",
+			AwsSecretPath:    "/spread",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+			},
+		},
+	}
+	if synced {
+		aSecret.Status.LastSyncTime = metav1.NewTime(time.Now().Add(-time.Hour))
+		aSecret.Status.ObservedGeneration = 2
+	}
+	return aSecret
+}
+
+func expectStartupSpreadSync(mockClient *MockSecretsManagerClient) {
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+}
+
+func TestReconcileStartupSpread(t *testing.T) {
+	spread := 10 * time.Minute
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "spread", Namespace: "default"}}
+
+	t.Run("first reconcile of an ASecret in sync is deferred", func(t *testing.T) {
+		// Any AWS call would panic on the mock without expectations
+		mockClient := &MockSecretsManagerClient{}
+		r, _ := setupASecretReconciler(t, mockClient, newStartupSpreadASecret(true))
+		r.StartupSpread = spread
+
+		result, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Greater(t, result.RequeueAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RequeueAfter, spread)
+
+		// The deferred reconcile syncs and requeues at the normal interval
+		expectStartupSpreadSync(mockClient)
+		result, err = r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, result.RequeueAfter)
+	})
+
+	t.Run("never synced ASecret is reconciled right away", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		expectStartupSpreadSync(mockClient)
+		r, _ := setupASecretReconciler(t, mockClient, newStartupSpreadASecret(false))
+		r.StartupSpread = spread
+
+		result, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, result.RequeueAfter)
+		mockClient.AssertCalled(t, "GetSecretValue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("without a spread nothing is deferred", func(t *testing.T) {
+		mockClient := &MockSecretsManagerClient{}
+		expectStartupSpreadSync(mockClient)
+		r, _ := setupASecretReconciler(t, mockClient, newStartupSpreadASecret(true))
+
+		result, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, result.RequeueAfter)
+	})
+}
+
+func TestStartupDelaySpecChanged(t *testing.T) {
+	aSecret := newStartupSpreadASecret(true)
+	aSecret.Generation = 3
+
+	r := &ASecretReconciler{StartupSpread: time.Minute}
+	assert.Zero(t, r.startupDelay(aSecret), "a changed spec is synced right away")
+	assert.Zero(t, r.startupDelay(newStartupSpreadASecret(true)), "only the first reconcile is deferred")
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// startupSweepSource returns a source that enqueues every ASecret once after startup,
+// staggered over StartupSweepSpread, to repair drift accumulated while the operator was down.
+// ASecrets already deferred by StartupSpread are skipped, their deferred reconcile repairs the drift
+func (r *ASecretReconciler) startupSweepSource() source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) error {
+		go r.runStartupSweep(ctx, queue)
+		return nil
+	})
+}
+
+// runStartupSweep lists all ASecrets and schedules a reconcile for each of them
+func (r *ASecretReconciler) runStartupSweep(ctx context.Context, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	log := r.Log.WithName("startup-sweep")
+
+	var aSecrets secretsv1alpha1.ASecretList
+	if err := r.List(ctx, &aSecrets); err != nil {
+		log.Error(err, "Failed to list ASecrets for startup sweep")
+		return
+	}
+
+	var requests []ctrl.Request
+	for _, aSecret := range aSecrets.Items {
+		key := k8sTypes.NamespacedName{Namespace: aSecret.Namespace, Name: aSecret.Name}
+		if _, seen := r.startupSeen.Load(key); seen {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: key})
+	}
+
+	delays := startupSweepDelays(len(requests), r.StartupSweepSpread, randomJitter)
+	for i, req := range requests {
+		queue.AddAfter(req, delays[i])
+	}
+
+	log.Info("Scheduled startup sweep", "asecrets", len(requests), "skipped", len(aSecrets.Items)-len(requests), "spread", r.StartupSweepSpread)
+}
+
+// startupSweepDelays spreads count reconciles evenly over spread, each shifted by a jitter within its own slot
+func startupSweepDelays(count int, spread time.Duration, jitter func(time.Duration) time.Duration) []time.Duration {
+	delays := make([]time.Duration, count)
+	if count == 0 || spread <= 0 {
+		return delays
+	}
+
+	slot := spread / time.Duration(count)
+	for i := range delays {
+		delays[i] = time.Duration(i) * slot
+		if slot > 0 {
+			delays[i] += jitter(slot)
+		}
+	}
+	return delays
+}
+
+// randomJitter returns a random duration in [0, limit)
+func randomJitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(limit)))
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// recordingQueue records delayed additions made by the startup sweep
+type recordingQueue struct {
+	workqueue.TypedRateLimitingInterface[ctrl.Request]
+	added map[ctrl.Request]time.Duration
+}
+
+func (q *recordingQueue) AddAfter(item ctrl.Request, duration time.Duration) {
+	q.added[item] = duration
+}
+
+func TestStartupSweepDelays(t *testing.T) {
+	noJitter := func(time.Duration) time.Duration { return 0 }
+	halfSlot := func(slot time.Duration) time.Duration { return slot / 2 }
+
+	tests := []struct {
+		name     string
+		count    int
+		spread   time.Duration
+		jitter   func(time.Duration) time.Duration
+		expected []time.Duration
+	}{
+		{
+			name:     "no items",
+			count:    0,
+			spread:   time.Minute,
+			jitter:   noJitter,
+			expected: []time.Duration{},
+		},
+		{
+			name:     "zero spread enqueues everything immediately",
+			count:    3,
+			spread:   0,
+			jitter:   halfSlot,
+			expected: []time.Duration{0, 0, 0},
+		},
+		{
+			name:     "evenly staggered without jitter",
+			count:    4,
+			spread:   time.Minute,
+			jitter:   noJitter,
+			expected: []time.Duration{0, 15 * time.Second, 30 * time.Second, 45 * time.Second},
+		},
+		{
+			name:     "jitter stays within each slot",
+			count:    2,
+			spread:   time.Minute,
+			jitter:   halfSlot,
+			expected: []time.Duration{15 * time.Second, 45 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := startupSweepDelays(tt.count, tt.spread, tt.jitter)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestStartupSweepDelaysRandomJitterWithinSpread(t *testing.T) {
+	spread := 10 * time.Second
+	delays := startupSweepDelays(100, spread, randomJitter)
+
+	slot := spread / 100
+	for i, d := range delays {
+		assert.GreaterOrEqual(t, d, time.Duration(i)*slot)
+		assert.Less(t, d, time.Duration(i+1)*slot)
+	}
+}
+
+func TestRandomJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), randomJitter(0))
+	assert.Equal(t, time.Duration(0), randomJitter(-time.Second))
+
+	for i := 0; i < 100; i++ {
+		j := randomJitter(time.Second)
+		assert.GreaterOrEqual(t, j, time.Duration(0))
+		assert.Less(t, j, time.Second)
+	}
+}
+
+func TestRunStartupSweep(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(
+			&secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns-a"}},
+			&secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns-b"}},
+			&secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "third", Namespace: "ns-a"}},
+		).
+		Build()
+
+	r := &ASecretReconciler{
+		Client:             fakeClient,
+		Log:                logr.Discard(),
+		StartupSweepSpread: 30 * time.Second,
+	}
+	queue := &recordingQueue{added: make(map[ctrl.Request]time.Duration)}
+
+	r.runStartupSweep(context.Background(), queue)
+
+	require.Len(t, queue.added, 3)
+	for _, name := range []string{"ns-a/first", "ns-b/second", "ns-a/third"} {
+		found := false
+		for req, delay := range queue.added {
+			if req.String() == name {
+				found = true
+				assert.Less(t, delay, r.StartupSweepSpread)
+			}
+		}
+		assert.True(t, found, "expected %s to be enqueued", name)
+	}
+}
+
+func TestRunStartupSweepSkipsDeferredASecrets(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(
+			&secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "deferred", Namespace: "default"}},
+			&secretsv1alpha1.ASecret{ObjectMeta: metav1.ObjectMeta{Name: "unseen", Namespace: "default"}},
+		).
+		Build()
+
+	r := &ASecretReconciler{
+		Client:             fakeClient,
+		Log:                logr.Discard(),
+		StartupSweepSpread: 30 * time.Second,
+		StartupSpread:      time.Minute,
+	}
+	// The first reconcile of "deferred" already scheduled it within StartupSpread
+	r.startupSeen.Store(k8sTypes.NamespacedName{Namespace: "default", Name: "deferred"}, struct{}{})
+	queue := &recordingQueue{added: make(map[ctrl.Request]time.Duration)}
+
+	r.runStartupSweep(context.Background(), queue)
+
+	require.Len(t, queue.added, 1)
+	_, enqueued := queue.added[ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Namespace: "default", Name: "unseen"}}]
+	assert.True(t, enqueued)
+}
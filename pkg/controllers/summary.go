@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// keySummary counts the outcome of each key of a sync
+type keySummary struct {
+	// synced keys are written by the operator and kept in sync with AWS
+	synced int
+	// imported keys are only read from AWS
+	imported int
+	// failed keys are defined in the spec but missing from the Secret, such as an import-only key absent in AWS
+	failed int
+}
+
+// String renders the summary for status.summary, e.g. "5 keys synced, 1 imported, 0 failed"
+func (s keySummary) String() string {
+	unit := "keys"
+	if s.synced == 1 {
+		unit = "key"
+	}
+	return fmt.Sprintf("%d %s synced, %d imported, %d failed", s.synced, unit, s.imported, s.failed)
+}
+
+// isImportedKey reports whether the key is only read from AWS, never written by the operator
+func isImportedKey(aSecret *secretsv1alpha1.ASecret, key string) bool {
+	if isImportOnly(aSecret) {
+		return true
+	}
+	dataSource, inSpec := aSecret.Spec.Data[key]
+	if !inSpec {
+		return false
+	}
+	return (dataSource.OnlyImportRemote != nil && *dataSource.OnlyImportRemote) || dataSource.RemoteRef != nil
+}
+
+// isRenderedKey reports whether the key is rendered from the other keys, such as the dotenv key or renderAsSingleKey
+func isRenderedKey(aSecret *secretsv1alpha1.ASecret, key string) bool {
+	if dotenv := dotenvKey(aSecret); dotenv != "" && key == dotenv {
+		return true
+	}
+	return aSecret.Spec.RenderAsSingleKey != "" && key == aSecret.Spec.RenderAsSingleKey
+}
+
+// summarizeKeys classifies the keys of the synced data, before it is rendered into the Secret, and the spec keys
+// absent from it. Rendered keys are not counted, they only repeat the other keys
+func summarizeKeys(aSecret *secretsv1alpha1.ASecret, secretData map[string][]byte) keySummary {
+	var summary keySummary
+	for key := range secretData {
+		if isRenderedKey(aSecret, key) {
+			continue
+		}
+		if isImportedKey(aSecret, key) {
+			summary.imported++
+		} else {
+			summary.synced++
+		}
+	}
+	for key := range aSecret.Spec.Data {
+		if _, exists := secretData[key]; !exists {
+			summary.failed++
+		}
+	}
+	return summary
+}
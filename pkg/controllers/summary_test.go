@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func TestSummarizeKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     secretsv1alpha1.ASecretSpec
+		keys     []string
+		expected string
+	}{
+		{
+			name: "written keys are synced",
+			spec: secretsv1alpha1.ASecretSpec{Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"password": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "gen"}},
+			}},
+			keys:     []string{"username", "password"},
+			expected: "2 keys synced, 0 imported, 0 failed",
+		},
+		{
+			name: "import-only and remoteRef keys are imported",
+			spec: secretsv1alpha1.ASecretSpec{Data: map[string]secretsv1alpha1.DataSource{
+				"username":    {Value: "admin"},
+				"apiKey":      {OnlyImportRemote: boolPtr(true)},
+				"DB_PASSWORD": {RemoteRef: &secretsv1alpha1.RemoteReference{Property: ".rds.password"}},
+			}},
+			keys:     []string{"username", "apiKey", "DB_PASSWORD"},
+			expected: "1 key synced, 2 imported, 0 failed",
+		},
+		{
+			name: "spec keys missing from the Secret are failed",
+			spec: secretsv1alpha1.ASecretSpec{Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"apiKey":   {OnlyImportRemote: boolPtr(true)},
+			}},
+			keys:     []string{"username"},
+			expected: "1 key synced, 0 imported, 1 failed",
+		},
+		{
+			name:     "every key of an import-only ASecret is imported",
+			spec:     secretsv1alpha1.ASecretSpec{OnlyImportRemote: boolPtr(true)},
+			keys:     []string{"username", "password", "host"},
+			expected: "0 keys synced, 3 imported, 0 failed",
+		},
+		{
+			name: "derived keys outside the spec are synced",
+			spec: secretsv1alpha1.ASecretSpec{Data: map[string]secretsv1alpha1.DataSource{
+				"id_rsa": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "ssh"}},
+			}},
+			keys:     []string{"id_rsa", "id_rsa.pub"},
+			expected: "2 keys synced, 0 imported, 0 failed",
+		},
+		{
+			name: "rendered keys are not counted",
+			spec: secretsv1alpha1.ASecretSpec{
+				Data: map[string]secretsv1alpha1.DataSource{
+					"USERNAME": {Value: "admin"},
+					"PASSWORD": {Value: "secret"},
+				},
+				TargetSecretTemplate: &secretsv1alpha1.TargetSecretTemplate{Dotenv: &secretsv1alpha1.DotenvTemplate{}},
+				RenderAsSingleKey:    "config.json",
+			},
+			keys:     []string{"USERNAME", "PASSWORD", ".env", "config.json"},
+			expected: "2 keys synced, 0 imported, 0 failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secretData := make(map[string][]byte)
+			for _, key := range tt.keys {
+				secretData[key] = []byte("value")
+			}
+			aSecret := &secretsv1alpha1.ASecret{Spec: tt.spec}
+			assert.Equal(t, tt.expected, summarizeKeys(aSecret, secretData).String())
+		})
+	}
+}
+
+func TestReconcileRecordsSummary(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "summarized", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "summarized-secret",
+			AwsSecretPath:    "/summarized",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"username": {Value: "admin"},
+				"host":     {Value: "db.local"},
+				"apiKey":   {OnlyImportRemote: boolPtr(true)},
+				"token":    {OnlyImportRemote: boolPtr(true)},
+			},
+		},
+	}
+
+	// AWS holds apiKey but not token, which cannot be imported
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin","host":"db.local","apiKey":"remote"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil).Maybe()
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "summarized", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, "2 keys synced, 1 imported, 1 failed", updated.Status.Summary)
+}
+
+func TestReconcileRecordsSummaryOfSingleKey(t *testing.T) {
+	aSecret := singleKeyASecret(secretsv1alpha1.ASecretSpec{
+		Data: map[string]secretsv1alpha1.DataSource{
+			"username": {Value: "admin"},
+			"password": {Value: "initial"},
+		},
+	})
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"password":"initial","username":"admin"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "config", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// The keys are counted before they are collapsed into the single key of the Secret
+	var updated secretsv1alpha1.ASecret
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, "2 keys synced, 0 imported, 0 failed", updated.Status.Summary)
+}
+
+func TestReconcileRecordsSummaryOfDotenv(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dotenv", Namespace: "default"},
+		Spec: secretsv1alpha1.ASecretSpec{
+			TargetSecretName: "dotenv-secret",
+			AwsSecretPath:    "/dotenv",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"USERNAME": {Value: "admin"},
+				"HOST":     {Value: "db.local"},
+			},
+			TargetSecretTemplate: &secretsv1alpha1.TargetSecretTemplate{Dotenv: &secretsv1alpha1.DotenvTemplate{}},
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"USERNAME":"admin","HOST":"db.local"}`),
+	}, nil)
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+
+	r, fakeClient := setupASecretReconciler(t, mockClient, aSecret)
+	req := ctrl.Request{NamespacedName: k8sTypes.NamespacedName{Name: "dotenv", Namespace: "default"}}
+
+	// The second reconcile reads the dotenv key back from the existing Secret
+	for range 2 {
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var updated secretsv1alpha1.ASecret
+		require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, &updated))
+		assert.Equal(t, "2 keys synced, 0 imported, 0 failed", updated.Status.Summary)
+	}
+}
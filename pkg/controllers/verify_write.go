@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+)
+
+// verifyAwsWrite reads the AWS secret back until written reports the written value, for ASecrets with verifyWrite.
+// Secrets Manager is eventually consistent, so a stale read is retried with a doubling delay before failing
+func (r *ASecretReconciler) verifyAwsWrite(ctx context.Context, smClient awsclient.SecretsManagerAPI, aSecret *secretsv1alpha1.ASecret, written func(*secretsmanager.GetSecretValueOutput) bool, log logr.Logger) error {
+	if aSecret.Spec.VerifyWrite == nil || !*aSecret.Spec.VerifyWrite {
+		return nil
+	}
+
+	attempts := max(r.AwsClient.Config.VerifyWriteAttempts, 1)
+	interval := r.AwsClient.Config.VerifyWriteInterval
+	secretPath := r.AwsClient.ResolveSecretPath(aSecret.Spec.AwsSecretPath)
+
+	// A cached read would only ever return the value seen before the write
+	ctx = awsclient.WithoutCache(ctx)
+
+	for attempt := 1; ; attempt++ {
+		output, err := smClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretPath),
+		})
+		if err == nil && written(output) {
+			return nil
+		}
+
+		// Denied or invalid reads will not recover on their own
+		if attempt >= attempts || (err != nil && !yasoerrors.IsRetryable(awsclient.ClassifyError(err))) {
+			if err != nil {
+				return fmt.Errorf("failed to verify write of AWS secret %s after %d reads: %w", secretPath, attempt, err)
+			}
+			return fmt.Errorf("AWS secret %s still returns a stale value after %d reads", secretPath, attempts)
+		}
+
+		log.V(1).Info("Verify read of AWS secret is stale, retrying", "path", secretPath, "attempt", attempt, "retryAfter", interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+	awsclient "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/client"
+	"github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func newVerifyWriteReconciler(attempts int) *ASecretReconciler {
+	return &ASecretReconciler{
+		AwsClient: &awsclient.AwsClient{Config: config.AWSConfig{
+			VerifyWriteAttempts: attempts,
+			VerifyWriteInterval: time.Millisecond,
+		}},
+	}
+}
+
+func TestCreateOrUpdateAwsSecretVerifiesWrite(t *testing.T) {
+	written := `{"password":"new"}`
+	stale := &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"password":"old"}`)}
+	consistent := &secretsmanager.GetSecretValueOutput{SecretString: aws.String(written)}
+
+	tests := []struct {
+		name          string
+		verifyWrite   *bool
+		reads         []*secretsmanager.GetSecretValueOutput
+		readErrs      []error
+		expectedError string
+	}{
+		{
+			name:        "verification disabled makes no read",
+			verifyWrite: nil,
+		},
+		{
+			name:        "consistent first read",
+			verifyWrite: boolPtr(true),
+			reads:       []*secretsmanager.GetSecretValueOutput{consistent},
+			readErrs:    []error{nil},
+		},
+		{
+			name:        "one stale read then consistent read",
+			verifyWrite: boolPtr(true),
+			reads:       []*secretsmanager.GetSecretValueOutput{stale, consistent},
+			readErrs:    []error{nil, nil},
+		},
+		{
+			name:        "read error is retried",
+			verifyWrite: boolPtr(true),
+			reads:       []*secretsmanager.GetSecretValueOutput{nil, consistent},
+			readErrs:    []error{errors.New("throttled"), nil},
+		},
+		{
+			name:          "stale after every attempt",
+			verifyWrite:   boolPtr(true),
+			reads:         []*secretsmanager.GetSecretValueOutput{stale, stale, stale},
+			readErrs:      []error{nil, nil, nil},
+			expectedError: "AWS secret /test/verified still returns a stale value after 3 reads",
+		},
+		{
+			name:          "access denied is not retried",
+			verifyWrite:   boolPtr(true),
+			reads:         []*secretsmanager.GetSecretValueOutput{nil},
+			readErrs:      []error{&smithy.GenericAPIError{Code: "AccessDeniedException", Message: "denied"}},
+			expectedError: "failed to verify write of AWS secret /test/verified after 1 reads: api error AccessDeniedException: denied",
+		},
+		{
+			name:          "read error on the last attempt",
+			verifyWrite:   boolPtr(true),
+			reads:         []*secretsmanager.GetSecretValueOutput{stale, stale, nil},
+			readErrs:      []error{nil, nil, errors.New("throttled")},
+			expectedError: "failed to verify write of AWS secret /test/verified after 3 reads: throttled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSecret := &secretsv1alpha1.ASecret{
+				Spec: secretsv1alpha1.ASecretSpec{
+					AwsSecretPath: "/test/verified",
+					VerifyWrite:   tt.verifyWrite,
+				},
+			}
+
+			mockClient := &MockSecretsManagerClient{}
+			mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+			mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil)
+			for i := range tt.reads {
+				mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+					return *input.SecretId == "/test/verified"
+				})).Return(tt.reads[i], tt.readErrs[i]).Once()
+			}
+
+			r := newVerifyWriteReconciler(3)
+			err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, map[string][]byte{"password": []byte("new")}, logr.Discard())
+
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			mockClient.AssertExpectations(t)
+			mockClient.AssertNumberOfCalls(t, "GetSecretValue", len(tt.reads))
+		})
+	}
+}
+
+func TestCreateOrUpdateAwsSecretBinaryVerifiesWrite(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{
+			AwsSecretPath: "/test/verified",
+			ValueType:     "binary",
+			VerifyWrite:   boolPtr(true),
+		},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("DescribeSecret", mock.Anything, mock.Anything).Return(&secretsmanager.DescribeSecretOutput{}, nil)
+	mockClient.On("PutSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, nil)
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{SecretBinary: []byte("old")}, nil).Once()
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{SecretBinary: []byte("new")}, nil).Once()
+
+	r := newVerifyWriteReconciler(3)
+	err := r.createOrUpdateAwsSecret(context.Background(), mockClient, aSecret, map[string][]byte{"cert": []byte("new")}, logr.Discard())
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetSecretValue", 2)
+}
+
+func TestVerifyAwsWriteStopsOnCancelledContext(t *testing.T) {
+	aSecret := &secretsv1alpha1.ASecret{
+		Spec: secretsv1alpha1.ASecretSpec{AwsSecretPath: "/test/verified", VerifyWrite: boolPtr(true)},
+	}
+
+	mockClient := &MockSecretsManagerClient{}
+	mockClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String("old")}, nil).Once()
+
+	r := newVerifyWriteReconciler(5)
+	r.AwsClient.Config.VerifyWriteInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.verifyAwsWrite(ctx, mockClient, aSecret, func(output *secretsmanager.GetSecretValueOutput) bool {
+		return aws.ToString(output.SecretString) == "new"
+	}, logr.Discard())
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,66 @@
+// Package errors defines the provider-independent error kinds the reconciler acts on.
+// Providers classify their own errors into these kinds, so retry and status decisions
+// never depend on a provider SDK's error types
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound means the remote secret does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrThrottled means the provider rejected the request because of rate limiting
+	ErrThrottled = errors.New("throttled")
+	// ErrAccessDenied means the credentials are not allowed to perform the request
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidSpec means the request built from the ASecret spec was rejected as invalid
+	ErrInvalidSpec = errors.New("invalid spec")
+)
+
+// kinds lists every sentinel in the order Kind checks them
+var kinds = []error{ErrNotFound, ErrThrottled, ErrAccessDenied, ErrInvalidSpec}
+
+// classifiedError tags a provider error with its kind without hiding the original error
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// Classify tags err with kind, so errors.Is(err, kind) holds while errors.As still finds the provider error.
+// A nil err stays nil, and a nil kind or an err already of that kind is returned unchanged
+func Classify(err, kind error) error {
+	if err == nil || kind == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &classifiedError{kind: kind, err: err}
+}
+
+// Kind returns the sentinel err was classified as, or nil if it was not classified
+func Kind(err error) error {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// IsRetryable reports whether retrying the same request may succeed. Errors that were not
+// classified are assumed to be transient
+func IsRetryable(err error) bool {
+	switch Kind(err) {
+	case ErrAccessDenied, ErrInvalidSpec:
+		return false
+	default:
+		return err != nil
+	}
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// providerError stands in for an error type defined by a provider SDK
+type providerError struct{ code string }
+
+func (e *providerError) Error() string { return "provider error " + e.code }
+
+func TestClassify(t *testing.T) {
+	cause := &providerError{code: "Throttled"}
+	classified := Classify(cause, ErrThrottled)
+
+	assert.ErrorIs(t, classified, ErrThrottled)
+	assert.NotErrorIs(t, classified, ErrNotFound)
+	assert.Equal(t, cause.Error(), classified.Error(), "the message is the provider's")
+
+	var target *providerError
+	assert.ErrorAs(t, classified, &target, "the provider error stays reachable")
+	assert.Same(t, cause, target)
+
+	wrapped := fmt.Errorf("reading /app/secret: %w", classified)
+	assert.ErrorIs(t, wrapped, ErrThrottled)
+	assert.Equal(t, ErrThrottled, Kind(wrapped))
+}
+
+func TestClassifyUnchanged(t *testing.T) {
+	assert.NoError(t, Classify(nil, ErrNotFound))
+
+	cause := errors.New("boom")
+	assert.Same(t, cause, Classify(cause, nil))
+
+	classified := Classify(cause, ErrNotFound)
+	assert.Same(t, classified, Classify(classified, ErrNotFound), "classifying twice does not nest")
+}
+
+func TestKindAndIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      error
+		retryable bool
+	}{
+		{name: "nil", err: nil, kind: nil, retryable: false},
+		{name: "unclassified", err: errors.New("connection reset"), kind: nil, retryable: true},
+		{name: "not found", err: Classify(errors.New("missing"), ErrNotFound), kind: ErrNotFound, retryable: true},
+		{name: "throttled", err: Classify(errors.New("slow down"), ErrThrottled), kind: ErrThrottled, retryable: true},
+		{name: "access denied", err: Classify(errors.New("denied"), ErrAccessDenied), kind: ErrAccessDenied, retryable: false},
+		{name: "invalid spec", err: Classify(errors.New("bad name"), ErrInvalidSpec), kind: ErrInvalidSpec, retryable: false},
+		{name: "sentinel itself", err: ErrInvalidSpec, kind: ErrInvalidSpec, retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, Kind(tt.err))
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// SecretARN holds the parts of a SecretsManager secret ARN the operator cares about
+type SecretARN struct {
+	Region    string
+	AccountID string
+	Name      string
+}
+
+// IsARN reports whether the secret path is an ARN rather than a secret name
+func IsARN(secretPath string) bool {
+	return arn.IsARN(secretPath)
+}
+
+// ParseSecretARN parses a SecretsManager secret ARN such as
+// arn:aws:secretsmanager:eu-west-1:123456789012:secret:my/secret-AbCdEf
+func ParseSecretARN(secretPath string) (SecretARN, error) {
+	parsed, err := arn.Parse(secretPath)
+	if err != nil {
+		return SecretARN{}, fmt.Errorf("invalid secret ARN %s: %w", secretPath, err)
+	}
+
+	if parsed.Service != "secretsmanager" {
+		return SecretARN{}, fmt.Errorf("ARN %s is not a secretsmanager ARN", secretPath)
+	}
+
+	name, found := strings.CutPrefix(parsed.Resource, "secret:")
+	if !found || name == "" {
+		return SecretARN{}, fmt.Errorf("ARN %s does not reference a secret", secretPath)
+	}
+
+	if len(parsed.AccountID) != 12 {
+		return SecretARN{}, fmt.Errorf("ARN %s has invalid account id %q", secretPath, parsed.AccountID)
+	}
+
+	return SecretARN{
+		Region:    parsed.Region,
+		AccountID: parsed.AccountID,
+		Name:      name,
+	}, nil
+}
+
+// AccountIDFromRoleARN returns the account id of an IAM role ARN
+func AccountIDFromRoleARN(roleArn string) (string, error) {
+	parsed, err := arn.Parse(roleArn)
+	if err != nil {
+		return "", fmt.Errorf("invalid role ARN %s: %w", roleArn, err)
+	}
+
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return "", fmt.Errorf("ARN %s is not an IAM role ARN", roleArn)
+	}
+
+	return parsed.AccountID, nil
+}
+
+// ValidateSecretAccount ensures a secret referenced by ARN lives in the account of the assumed role.
+// Secret names, and ARNs when no role is assumed, are always accepted
+func (c *AwsClient) ValidateSecretAccount(secretPath string) error {
+	if !IsARN(secretPath) {
+		return nil
+	}
+
+	secretARN, err := ParseSecretARN(secretPath)
+	if err != nil {
+		return err
+	}
+
+	if c.Config.AssumeRoleArn == "" {
+		return nil
+	}
+
+	expectedAccount, err := AccountIDFromRoleARN(c.Config.AssumeRoleArn)
+	if err != nil {
+		return err
+	}
+
+	if secretARN.AccountID != expectedAccount {
+		return fmt.Errorf("secret %s belongs to account %s but the assumed role %s belongs to account %s",
+			secretPath, secretARN.AccountID, c.Config.AssumeRoleArn, expectedAccount)
+	}
+
+	return nil
+}
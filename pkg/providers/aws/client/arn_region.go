@@ -0,0 +1,223 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+)
+
+// RegionalClientFactory creates a SecretsManager API bound to the given region
+type RegionalClientFactory func(ctx context.Context, region string) (SecretsManagerAPI, error)
+
+// arnRegionSecretsManager sends calls for secrets referenced by an ARN of another region to a client
+// of that region, created on first use and cached. Names, malformed ARNs and ARNs of the primary
+// region go to the primary API. The regions come from tenant ARNs, so at most maxClients clients are
+// kept and the least recently used one is dropped to make room for a new region
+type arnRegionSecretsManager struct {
+	primary    RegionalSecretsManager
+	factory    RegionalClientFactory
+	maxClients int
+	log        logr.Logger
+
+	mu      sync.Mutex
+	regions map[string]*list.Element
+	// lru orders the cached clients from the most to the least recently used
+	lru *list.List
+}
+
+// regionClient is the cached client of a region, held in the lru list
+type regionClient struct {
+	region string
+	api    SecretsManagerAPI
+}
+
+// NewARNRegionSecretsManager wraps the primary region API so secrets referenced by ARN are read and
+// written in the region encoded in the ARN. maxClients bounds the cached regional clients, 0 disables it
+func NewARNRegionSecretsManager(primary RegionalSecretsManager, factory RegionalClientFactory, maxClients int, log logr.Logger) SecretsManagerAPI {
+	return &arnRegionSecretsManager{
+		primary:    primary,
+		factory:    factory,
+		maxClients: maxClients,
+		log:        log,
+		regions:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// CreateARNRegionSecretsManager wraps the primary API with clients built on demand for the regions of secret ARNs
+func (c *AwsClient) CreateARNRegionSecretsManager(primary SecretsManagerAPI, log logr.Logger) SecretsManagerAPI {
+	return NewARNRegionSecretsManager(RegionalSecretsManager{Region: c.determineRegion(), API: primary}, func(ctx context.Context, region string) (SecretsManagerAPI, error) {
+		return c.createSecretsManagerClientForRegion(ctx, region, log)
+	}, c.Config.MaxAWSClients, log)
+}
+
+// ARNRegion returns the region of a secret referenced by ARN. It reports false for secret names
+// and for ARNs that are not valid secret ARNs, which are left to the primary region to reject
+func ARNRegion(secretID string) (string, bool) {
+	if !IsARN(secretID) {
+		return "", false
+	}
+	secretARN, err := ParseSecretARN(secretID)
+	if err != nil || secretARN.Region == "" {
+		return "", false
+	}
+	return secretARN.Region, true
+}
+
+// apiFor returns the API serving the secret, creating the client of its ARN region when needed
+func (a *arnRegionSecretsManager) apiFor(ctx context.Context, secretID string) (SecretsManagerAPI, error) {
+	region, ok := ARNRegion(secretID)
+	if !ok || region == a.primary.Region {
+		return a.primary.API, nil
+	}
+
+	if api, ok := a.cached(region); ok {
+		return api, nil
+	}
+	// The client is built without holding the lock, so a slow credential lookup does not block the
+	// calls of the regions already cached
+	api, err := a.factory(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Another call may have built a client of the same region meanwhile, which is kept so all calls share it
+	if elem, ok := a.regions[region]; ok {
+		a.lru.MoveToFront(elem)
+		return elem.Value.(*regionClient).api, nil
+	}
+	a.log.Info("Created AWS SecretsManager client for secret ARN region", "region", region)
+	a.regions[region] = a.lru.PushFront(&regionClient{region: region, api: api})
+	if a.maxClients > 0 && a.lru.Len() > a.maxClients {
+		oldest := a.lru.Remove(a.lru.Back()).(*regionClient)
+		delete(a.regions, oldest.region)
+		a.log.Info("Evicted least recently used AWS SecretsManager client", "region", oldest.region, "maxClients", a.maxClients)
+	}
+	return api, nil
+}
+
+// cached returns the cached client of the region, marking it as the most recently used
+func (a *arnRegionSecretsManager) cached(region string) (SecretsManagerAPI, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	elem, ok := a.regions[region]
+	if !ok {
+		return nil, false
+	}
+	a.lru.MoveToFront(elem)
+	return elem.Value.(*regionClient).api, true
+}
+
+func (a *arnRegionSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.GetSecretValue(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.DescribeSecret(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	// Secrets are created by name, so always in the primary region
+	return a.primary.API.CreateSecret(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.PutSecretValue(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.UpdateSecret(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.RestoreSecret(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.TagResource(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.UntagResource(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.ReplicateSecretToRegions(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.RemoveRegionsFromReplication(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) GetResourcePolicy(ctx context.Context, params *secretsmanager.GetResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetResourcePolicyOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.GetResourcePolicy(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.PutResourcePolicy(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error) {
+	api, err := a.apiFor(ctx, aws.ToString(params.SecretId))
+	if err != nil {
+		return nil, err
+	}
+	return api.DeleteResourcePolicy(ctx, params, optFns...)
+}
+
+func (a *arnRegionSecretsManager) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	// Secrets are listed by name, so always in the primary region
+	return a.primary.API.ListSecrets(ctx, params, optFns...)
+}
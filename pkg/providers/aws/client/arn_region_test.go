@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	primaryRegionARN = "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/primary-AbCdEf"
+	crossRegionARN   = "arn:aws:secretsmanager:us-east-1:123456789012:secret:app/cross-AbCdEf"
+)
+
+func TestARNRegion(t *testing.T) {
+	tests := []struct {
+		name           string
+		secretID       string
+		expectedRegion string
+		expectedOK     bool
+	}{
+		{name: "secret name", secretID: "/app/secret", expectedOK: false},
+		{name: "ARN in the primary region", secretID: primaryRegionARN, expectedRegion: "eu-west-1", expectedOK: true},
+		{name: "ARN in another region", secretID: crossRegionARN, expectedRegion: "us-east-1", expectedOK: true},
+		{name: "ARN without region", secretID: "arn:aws:secretsmanager::123456789012:secret:app/secret-AbCdEf", expectedOK: false},
+		{name: "ARN of another service", secretID: "arn:aws:ssm:us-east-1:123456789012:parameter/app", expectedOK: false},
+		{name: "malformed ARN", secretID: "arn:aws:secretsmanager:us-east-1", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, ok := ARNRegion(tt.secretID)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedRegion, region)
+		})
+	}
+}
+
+func TestARNRegionSecretsManagerSelectsClient(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{
+		"/app/name":      "from-primary",
+		primaryRegionARN: "from-primary-arn",
+	}}
+	crossRegion := &regionSecretsManager{values: map[string]string{crossRegionARN: "from-us-east-1"}}
+
+	var created []string
+	api := NewARNRegionSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, func(_ context.Context, region string) (SecretsManagerAPI, error) {
+		created = append(created, region)
+		return crossRegion, nil
+	}, 0, logr.Discard())
+	ctx := context.Background()
+
+	for secretID, expected := range map[string]string{
+		"/app/name":      "from-primary",
+		primaryRegionARN: "from-primary-arn",
+		crossRegionARN:   "from-us-east-1",
+	} {
+		output, err := api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+		require.NoError(t, err, secretID)
+		assert.Equal(t, expected, aws.ToString(output.SecretString), secretID)
+	}
+
+	// Writes follow the ARN region too, reusing the cached client
+	_, err := api.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{SecretId: aws.String(crossRegionARN), SecretString: aws.String("updated")})
+	require.NoError(t, err)
+	_, err = api.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(crossRegionARN)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-east-1"}, created)
+	assert.Equal(t, []string{"GetSecretValue", "PutSecretValue", "DescribeSecret"}, crossRegion.calls)
+	assert.Equal(t, "updated", crossRegion.values[crossRegionARN])
+
+	// New secrets are created by name in the primary region
+	_, err = api.CreateSecret(ctx, &secretsmanager.CreateSecretInput{Name: aws.String("/app/new"), SecretString: aws.String("new")})
+	require.NoError(t, err)
+	assert.Equal(t, "new", primary.values["/app/new"])
+}
+
+func TestARNRegionSecretsManagerMalformedARNUsesPrimary(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{}, err: errors.New("invalid secret id")}
+	api := NewARNRegionSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, func(context.Context, string) (SecretsManagerAPI, error) {
+		t.Fatal("no regional client should be created for a malformed ARN")
+		return nil, nil
+	}, 0, logr.Discard())
+
+	_, err := api.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String("arn:aws:secretsmanager:us-east-1")})
+	assert.EqualError(t, err, "invalid secret id")
+	assert.Equal(t, []string{"GetSecretValue"}, primary.calls)
+}
+
+func TestARNRegionSecretsManagerFactoryError(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{}}
+	attempts := 0
+	api := NewARNRegionSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, func(context.Context, string) (SecretsManagerAPI, error) {
+		attempts++
+		return nil, errors.New("failed to load AWS config")
+	}, 0, logr.Discard())
+
+	for range 2 {
+		_, err := api.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(crossRegionARN)})
+		assert.EqualError(t, err, "failed to load AWS config")
+	}
+	// A failed client is not cached, the next call retries
+	assert.Equal(t, 2, attempts)
+	assert.Empty(t, primary.calls)
+}
+
+func TestARNRegionSecretsManagerEvictsLeastRecentlyUsedClient(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{}}
+	var created []string
+	api := NewARNRegionSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, func(_ context.Context, region string) (SecretsManagerAPI, error) {
+		created = append(created, region)
+		return &regionSecretsManager{values: map[string]string{}}, nil
+	}, 2, logr.Discard())
+	ctx := context.Background()
+	describe := func(region string) {
+		t.Helper()
+		secretARN := "arn:aws:secretsmanager:" + region + ":123456789012:secret:app/secret-AbCdEf"
+		_, err := api.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(secretARN)})
+		require.NoError(t, err)
+	}
+
+	describe("us-east-1")
+	describe("us-west-2")
+	// us-east-1 becomes the most recently used, so us-west-2 is evicted for ap-south-1
+	describe("us-east-1")
+	describe("ap-south-1")
+	describe("us-east-1")
+	describe("us-west-2")
+	assert.Equal(t, []string{"us-east-1", "us-west-2", "ap-south-1", "us-west-2"}, created)
+
+	cache := api.(*arnRegionSecretsManager)
+	assert.Equal(t, 2, cache.lru.Len())
+	assert.Len(t, cache.regions, 2)
+	assert.NotContains(t, cache.regions, "ap-south-1")
+}
+
+func TestARNRegionSecretsManagerConcurrentCreationSharesClient(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{}}
+	var mu sync.Mutex
+	var created []SecretsManagerAPI
+	api := NewARNRegionSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, func(context.Context, string) (SecretsManagerAPI, error) {
+		client := &regionSecretsManager{values: map[string]string{}}
+		mu.Lock()
+		created = append(created, client)
+		mu.Unlock()
+		return client, nil
+	}, 0, logr.Discard()).(*arnRegionSecretsManager)
+
+	var wg sync.WaitGroup
+	apis := make([]SecretsManagerAPI, 8)
+	for i := range apis {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			regional, err := api.apiFor(context.Background(), crossRegionARN)
+			assert.NoError(t, err)
+			apis[i] = regional
+		}()
+	}
+	wg.Wait()
+
+	// Clients built concurrently for the same region are dropped in favour of the first one cached
+	require.NotEmpty(t, created)
+	for _, regional := range apis {
+		assert.Same(t, apis[0], regional)
+	}
+	assert.Equal(t, 1, api.lru.Len())
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func TestParseSecretARN(t *testing.T) {
+	tests := []struct {
+		name     string
+		arn      string
+		expected SecretARN
+		wantErr  bool
+	}{
+		{
+			name: "valid secret ARN",
+			arn:  "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my/app/secret-AbCdEf",
+			expected: SecretARN{
+				Region:    "eu-west-1",
+				AccountID: "123456789012",
+				Name:      "my/app/secret-AbCdEf",
+			},
+		},
+		{
+			name: "valid secret ARN in another partition",
+			arn:  "arn:aws-cn:secretsmanager:cn-north-1:210987654321:secret:db-XyZ123",
+			expected: SecretARN{
+				Region:    "cn-north-1",
+				AccountID: "210987654321",
+				Name:      "db-XyZ123",
+			},
+		},
+		{name: "not an ARN", arn: "/my/app/secret", wantErr: true},
+		{name: "wrong service", arn: "arn:aws:ssm:eu-west-1:123456789012:parameter/my/param", wantErr: true},
+		{name: "not a secret resource", arn: "arn:aws:secretsmanager:eu-west-1:123456789012:other:thing", wantErr: true},
+		{name: "empty secret name", arn: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:", wantErr: true},
+		{name: "missing account id", arn: "arn:aws:secretsmanager:eu-west-1::secret:my-secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSecretARN(tt.arn)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAccountIDFromRoleARN(t *testing.T) {
+	account, err := AccountIDFromRoleARN("arn:aws:iam::123456789012:role/yaso-cross-account")
+	assert.NoError(t, err)
+	assert.Equal(t, "123456789012", account)
+
+	_, err = AccountIDFromRoleARN("arn:aws:iam::123456789012:user/someone")
+	assert.Error(t, err)
+
+	_, err = AccountIDFromRoleARN("not-an-arn")
+	assert.Error(t, err)
+}
+
+func TestValidateSecretAccount(t *testing.T) {
+	tests := []struct {
+		name          string
+		assumeRoleArn string
+		secretPath    string
+		wantErr       bool
+		errMsg        string
+	}{
+		{
+			name:       "secret name without assumed role",
+			secretPath: "/my/app/secret",
+		},
+		{
+			name:          "secret name with assumed role",
+			assumeRoleArn: "arn:aws:iam::123456789012:role/yaso",
+			secretPath:    "/my/app/secret",
+		},
+		{
+			name:       "ARN without assumed role",
+			secretPath: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-secret-AbCdEf",
+		},
+		{
+			name:          "ARN matching the assumed role account",
+			assumeRoleArn: "arn:aws:iam::123456789012:role/yaso",
+			secretPath:    "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-secret-AbCdEf",
+		},
+		{
+			name:          "ARN in another account than the assumed role",
+			assumeRoleArn: "arn:aws:iam::123456789012:role/yaso",
+			secretPath:    "arn:aws:secretsmanager:eu-west-1:999999999999:secret:my-secret-AbCdEf",
+			wantErr:       true,
+			errMsg:        "belongs to account 999999999999",
+		},
+		{
+			name:       "malformed ARN",
+			secretPath: "arn:aws:secretsmanager:eu-west-1:123456789012:parameter:oops",
+			wantErr:    true,
+		},
+		{
+			name:          "malformed assumed role ARN",
+			assumeRoleArn: "arn:aws:iam::123456789012:user/yaso",
+			secretPath:    "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-secret-AbCdEf",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(awsconfig.AWSConfig{AssumeRoleArn: tt.assumeRoleArn})
+
+			err := c.ValidateSecretAccount(tt.secretPath)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
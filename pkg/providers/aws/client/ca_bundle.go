@@ -0,0 +1,74 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// pemBlockPrefix starts every PEM block, telling an inline CA bundle apart from a file path
+const pemBlockPrefix = "-----BEGIN"
+
+// readCABundle returns the PEM certificates of --aws-ca-bundle, given inline or as the path of a file
+func readCABundle(bundle string) ([]byte, error) {
+	if strings.Contains(bundle, pemBlockPrefix) {
+		return []byte(bundle), nil
+	}
+	pemCerts, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AWS CA bundle: %w", err)
+	}
+	return pemCerts, nil
+}
+
+// newCABundleHTTPClient returns an HTTP client for the SDK trusting the CA bundle on top of the system roots,
+// so an endpoint behind a proxy with a private CA and AWS itself, e.g. STS, are both reachable
+func newCABundleHTTPClient(bundle string) (*awshttp.BuildableClient, error) {
+	pemCerts, err := readCABundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("AWS CA bundle contains no PEM certificate")
+	}
+
+	return awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tr.TLSClientConfig.RootCAs = pool
+	}), nil
+}
+
+// ValidateCABundle rejects an --aws-ca-bundle that cannot be read or holds no certificate
+func ValidateCABundle(bundle string) error {
+	if bundle == "" {
+		return nil
+	}
+	_, err := newCABundleHTTPClient(bundle)
+	return err
+}
+
+// httpClientOptions returns the load options sending the SDK requests through an HTTP client trusting
+// --aws-ca-bundle, none when it is not set
+func (c *AwsClient) httpClientOptions() ([]func(*config.LoadOptions) error, error) {
+	if c.Config.CABundle == "" {
+		return nil, nil
+	}
+	httpClient, err := newCABundleHTTPClient(c.Config.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	return []func(*config.LoadOptions) error{config.WithHTTPClient(httpClient)}, nil
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+// newPrivateCAServer starts a TLS server whose self-signed certificate no system root trusts, and returns it
+// with the certificate in PEM form
+func newPrivateCAServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestCABundleHTTPClientTrustsBundle(t *testing.T) {
+	server, caPEM := newPrivateCAServer(t)
+
+	bundleFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(bundleFile, []byte(caPEM), 0o600))
+
+	for name, bundle := range map[string]string{"inline": caPEM, "file": bundleFile} {
+		t.Run(name, func(t *testing.T) {
+			httpClient, err := newCABundleHTTPClient(bundle)
+			require.NoError(t, err)
+
+			transport := httpClient.GetTransport()
+			require.NotNil(t, transport.TLSClientConfig)
+			require.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+			resp, err := httpClient.Do(mustNewRequest(t, server.URL))
+			require.NoError(t, err, "the transport must trust the CA bundle")
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	// Without the bundle, the private CA is rejected
+	_, err := http.DefaultClient.Do(mustNewRequest(t, server.URL))
+	assert.Error(t, err)
+}
+
+func TestValidateCABundle(t *testing.T) {
+	_, caPEM := newPrivateCAServer(t)
+
+	assert.NoError(t, ValidateCABundle(""))
+	assert.NoError(t, ValidateCABundle(caPEM))
+	assert.ErrorContains(t, ValidateCABundle(filepath.Join(t.TempDir(), "missing.pem")), "failed to read AWS CA bundle")
+	assert.ErrorContains(t, ValidateCABundle("-----BEGIN CERTIFICATE-----\nnot base64\n-----END CERTIFICATE-----"), "contains no PEM certificate")
+}
+
+func TestHTTPClientOptions(t *testing.T) {
+	opts, err := (&AwsClient{}).httpClientOptions()
+	require.NoError(t, err)
+	assert.Empty(t, opts, "the SDK default HTTP client is kept without a CA bundle")
+
+	_, caPEM := newPrivateCAServer(t)
+	opts, err = (&AwsClient{Config: awsconfig.AWSConfig{CABundle: caPEM}}).httpClientOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+}
+
+func mustNewRequest(t *testing.T, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return req
+}
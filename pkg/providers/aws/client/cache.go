@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// defaultVersionStage is the stage GetSecretValue returns when none is requested
+const defaultVersionStage = "AWSCURRENT"
+
+// bypassCacheKey marks a context whose reads must reach AWS
+type bypassCacheKey struct{}
+
+// WithoutCache returns a context whose GetSecretValue calls skip the cache, neither reading nor filling it
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// cachedSecretValue is a GetSecretValue response and the time it stops being served
+type cachedSecretValue struct {
+	output  *secretsmanager.GetSecretValueOutput
+	expires time.Time
+}
+
+// cachingSecretsManager serves GetSecretValue responses from memory for a TTL, so several
+// ASecrets referencing the same path only hit AWS once. Writes to a path invalidate its entries
+type cachingSecretsManager struct {
+	SecretsManagerAPI
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]map[string]cachedSecretValue
+	// generations counts writes per secret, so a read that raced with a write is not cached
+	generations map[string]uint64
+}
+
+// NewCachingSecretsManager wraps the API with a GetSecretValue cache keyed by secret id and version.
+// A non-positive TTL disables caching and returns the API unchanged
+func NewCachingSecretsManager(api SecretsManagerAPI, ttl time.Duration) SecretsManagerAPI {
+	if ttl <= 0 {
+		return api
+	}
+
+	return &cachingSecretsManager{
+		SecretsManagerAPI: api,
+		ttl:               ttl,
+		now:               time.Now,
+		entries:           make(map[string]map[string]cachedSecretValue),
+		generations:       make(map[string]uint64),
+	}
+}
+
+func (c *cachingSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if bypass, _ := ctx.Value(bypassCacheKey{}).(bool); bypass {
+		return c.SecretsManagerAPI.GetSecretValue(ctx, params, optFns...)
+	}
+
+	secretID := aws.ToString(params.SecretId)
+	version := cacheVersionKey(params)
+
+	output, generation, ok := c.lookup(secretID, version)
+	if ok {
+		return output, nil
+	}
+
+	output, err := c.SecretsManagerAPI.GetSecretValue(ctx, params, optFns...)
+	if err != nil {
+		// Errors are never cached, a missing secret must be seen as soon as it is created
+		return nil, err
+	}
+
+	c.store(secretID, version, generation, output)
+	return copySecretValueOutput(output), nil
+}
+
+func (c *cachingSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	defer c.invalidate(aws.ToString(params.Name))
+	return c.SecretsManagerAPI.CreateSecret(ctx, params, optFns...)
+}
+
+func (c *cachingSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	defer c.invalidate(aws.ToString(params.SecretId))
+	return c.SecretsManagerAPI.PutSecretValue(ctx, params, optFns...)
+}
+
+// lookup returns a copy of the cached response, if present and not expired, along with
+// the current write generation of the secret
+func (c *cachingSecretsManager) lookup(secretID, version string) (*secretsmanager.GetSecretValueOutput, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generation := c.generations[secretID]
+	entry, ok := c.entries[secretID][version]
+	if !ok {
+		return nil, generation, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries[secretID], version)
+		return nil, generation, false
+	}
+	return copySecretValueOutput(entry.output), generation, true
+}
+
+// store caches a response until the TTL elapses, unless the secret was written since the lookup
+func (c *cachingSecretsManager) store(secretID, version string, generation uint64, output *secretsmanager.GetSecretValueOutput) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[secretID] != generation {
+		return
+	}
+
+	if c.entries[secretID] == nil {
+		c.entries[secretID] = make(map[string]cachedSecretValue)
+	}
+	c.entries[secretID][version] = cachedSecretValue{
+		output:  copySecretValueOutput(output),
+		expires: c.now().Add(c.ttl),
+	}
+}
+
+// invalidate drops every cached version of the secret
+func (c *cachingSecretsManager) invalidate(secretID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, secretID)
+	c.generations[secretID]++
+}
+
+// cacheVersionKey identifies the requested version of a secret, defaulting to the current stage
+func cacheVersionKey(params *secretsmanager.GetSecretValueInput) string {
+	if versionID := aws.ToString(params.VersionId); versionID != "" {
+		return "id:" + versionID
+	}
+	if stage := aws.ToString(params.VersionStage); stage != "" {
+		return "stage:" + stage
+	}
+	return "stage:" + defaultVersionStage
+}
+
+// copySecretValueOutput copies the response so callers cannot alter the cached value
+func copySecretValueOutput(output *secretsmanager.GetSecretValueOutput) *secretsmanager.GetSecretValueOutput {
+	result := *output
+	if output.SecretBinary != nil {
+		result.SecretBinary = append([]byte(nil), output.SecretBinary...)
+	}
+	if output.VersionStages != nil {
+		result.VersionStages = append([]string(nil), output.VersionStages...)
+	}
+	return &result
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretsManager stores secret strings in memory and counts GetSecretValue calls
+type fakeSecretsManager struct {
+	SecretsManagerAPI
+
+	mu      sync.Mutex
+	values  map[string]string
+	getCall atomic.Int64
+}
+
+func newFakeSecretsManager(values map[string]string) *fakeSecretsManager {
+	return &fakeSecretsManager{values: values}
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	f.getCall.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.values[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{
+		Name:         params.SecretId,
+		SecretString: aws.String(value),
+	}, nil
+}
+
+func (f *fakeSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[aws.ToString(params.SecretId)] = aws.ToString(params.SecretString)
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (f *fakeSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[aws.ToString(params.Name)] = aws.ToString(params.SecretString)
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func getSecretString(t *testing.T, api SecretsManagerAPI, input *secretsmanager.GetSecretValueInput) string {
+	t.Helper()
+	output, err := api.GetSecretValue(context.Background(), input)
+	require.NoError(t, err)
+	return aws.ToString(output.SecretString)
+}
+
+func TestNewCachingSecretsManagerDisabled(t *testing.T) {
+	api := newFakeSecretsManager(nil)
+
+	assert.Same(t, api, NewCachingSecretsManager(api, 0))
+	assert.Same(t, api, NewCachingSecretsManager(api, -time.Second))
+}
+
+func TestCachingSecretsManagerGetSecretValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputs        []*secretsmanager.GetSecretValueInput
+		advance       time.Duration
+		expectedCalls int64
+	}{
+		{
+			name: "repeated reads of the same path are served from the cache",
+			inputs: []*secretsmanager.GetSecretValueInput{
+				{SecretId: aws.String("/app/a")},
+				{SecretId: aws.String("/app/a")},
+				{SecretId: aws.String("/app/a"), VersionStage: aws.String("AWSCURRENT")},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "different paths are cached separately",
+			inputs: []*secretsmanager.GetSecretValueInput{
+				{SecretId: aws.String("/app/a")},
+				{SecretId: aws.String("/app/b")},
+				{SecretId: aws.String("/app/b")},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "different version stages are cached separately",
+			inputs: []*secretsmanager.GetSecretValueInput{
+				{SecretId: aws.String("/app/a")},
+				{SecretId: aws.String("/app/a"), VersionStage: aws.String("AWSPREVIOUS")},
+				{SecretId: aws.String("/app/a"), VersionStage: aws.String("AWSPREVIOUS")},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "expired entries are fetched again",
+			inputs: []*secretsmanager.GetSecretValueInput{
+				{SecretId: aws.String("/app/a")},
+				{SecretId: aws.String("/app/a")},
+			},
+			advance:       time.Minute,
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeSecretsManager(map[string]string{"/app/a": "a", "/app/b": "b"})
+			cached := NewCachingSecretsManager(api, time.Minute).(*cachingSecretsManager)
+			now := time.Now()
+			cached.now = func() time.Time { return now }
+
+			for _, input := range tt.inputs {
+				assert.Equal(t, api.values[aws.ToString(input.SecretId)], getSecretString(t, cached, input))
+				now = now.Add(tt.advance)
+			}
+
+			assert.Equal(t, tt.expectedCalls, api.getCall.Load())
+		})
+	}
+}
+
+func TestCachingSecretsManagerDoesNotCacheErrors(t *testing.T) {
+	api := newFakeSecretsManager(map[string]string{})
+	cached := NewCachingSecretsManager(api, time.Minute)
+
+	_, err := cached.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/new")})
+	require.Error(t, err)
+
+	_, err = cached.CreateSecret(context.Background(), &secretsmanager.CreateSecretInput{
+		Name:         aws.String("/app/new"),
+		SecretString: aws.String("created"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "created", getSecretString(t, cached, &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/new")}))
+	assert.Equal(t, int64(2), api.getCall.Load())
+}
+
+func TestCachingSecretsManagerInvalidatesOnPut(t *testing.T) {
+	api := newFakeSecretsManager(map[string]string{"/app/a": "old", "/app/b": "b"})
+	cached := NewCachingSecretsManager(api, time.Hour)
+	inputA := &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/a")}
+	inputB := &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/b")}
+
+	assert.Equal(t, "old", getSecretString(t, cached, inputA))
+	assert.Equal(t, "b", getSecretString(t, cached, inputB))
+
+	_, err := cached.PutSecretValue(context.Background(), &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String("/app/a"),
+		SecretString: aws.String("new"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "new", getSecretString(t, cached, inputA))
+	assert.Equal(t, "b", getSecretString(t, cached, inputB))
+	assert.Equal(t, int64(3), api.getCall.Load(), "only the written path should be fetched again")
+}
+
+func TestCachingSecretsManagerWithoutCache(t *testing.T) {
+	api := newFakeSecretsManager(map[string]string{"/app/a": "old"})
+	cached := NewCachingSecretsManager(api, time.Hour)
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/a")}
+
+	output, err := cached.GetSecretValue(WithoutCache(context.Background()), input)
+	require.NoError(t, err)
+	assert.Equal(t, "old", aws.ToString(output.SecretString))
+
+	// The bypassed read did not fill the cache
+	assert.Equal(t, "old", getSecretString(t, cached, input))
+	assert.Equal(t, int64(2), api.getCall.Load())
+
+	// A cached value is not served to a bypassed read
+	api.values["/app/a"] = "new"
+	output, err = cached.GetSecretValue(WithoutCache(context.Background()), input)
+	require.NoError(t, err)
+	assert.Equal(t, "new", aws.ToString(output.SecretString))
+	assert.Equal(t, "old", getSecretString(t, cached, input))
+	assert.Equal(t, int64(3), api.getCall.Load())
+}
+
+func TestCachingSecretsManagerReturnsCopies(t *testing.T) {
+	api := newFakeSecretsManager(map[string]string{"/app/a": "a"})
+	cached := NewCachingSecretsManager(api, time.Hour)
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/a")}
+
+	output, err := cached.GetSecretValue(context.Background(), input)
+	require.NoError(t, err)
+	output.SecretString = aws.String("tampered")
+
+	assert.Equal(t, "a", getSecretString(t, cached, input))
+}
+
+func TestCachingSecretsManagerConcurrentAccess(t *testing.T) {
+	paths := 5
+	values := make(map[string]string)
+	for i := range paths {
+		values[fmt.Sprintf("/app/%d", i)] = "initial"
+	}
+	api := newFakeSecretsManager(values)
+	cached := NewCachingSecretsManager(api, time.Hour)
+
+	var wg sync.WaitGroup
+	for worker := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				secretID := aws.String(fmt.Sprintf("/app/%d", (worker+i)%paths))
+				if i%10 == 0 {
+					_, err := cached.PutSecretValue(context.Background(), &secretsmanager.PutSecretValueInput{
+						SecretId:     secretID,
+						SecretString: aws.String(fmt.Sprintf("worker-%d", worker)),
+					})
+					assert.NoError(t, err)
+					continue
+				}
+				output, err := cached.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: secretID})
+				assert.NoError(t, err)
+				assert.NotEmpty(t, aws.ToString(output.SecretString))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Once writers are done, every path must reflect the last write
+	for secretID, value := range api.values {
+		assert.Equal(t, value, getSecretString(t, cached, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)}))
+	}
+}
+
+func TestCachingSecretsManagerSkipsReadsRacingWithWrites(t *testing.T) {
+	cached := NewCachingSecretsManager(newFakeSecretsManager(nil), time.Hour).(*cachingSecretsManager)
+	stale := &secretsmanager.GetSecretValueOutput{SecretString: aws.String("stale")}
+
+	// A read looks the path up, a write lands, then the read tries to store what it fetched
+	_, generation, ok := cached.lookup("/app/a", "stage:AWSCURRENT")
+	require.False(t, ok)
+	cached.invalidate("/app/a")
+	cached.store("/app/a", "stage:AWSCURRENT", generation, stale)
+
+	_, _, ok = cached.lookup("/app/a", "stage:AWSCURRENT")
+	assert.False(t, ok)
+}
@@ -7,7 +7,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/go-logr/logr"
 
 	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
@@ -18,7 +20,16 @@ type SecretsManagerAPI interface {
 	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
 	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
 	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
+	RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error)
 	TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error)
+	UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error)
+	ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error)
+	RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error)
+	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+	GetResourcePolicy(ctx context.Context, params *secretsmanager.GetResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetResourcePolicyOutput, error)
+	PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error)
+	DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error)
 }
 
 // Client provides AWS operations
@@ -36,16 +47,29 @@ func NewClient(config awsconfig.AWSConfig) *AwsClient {
 // CreateSecretsManagerClient creates a new AWS SecretsManager client
 func (c *AwsClient) CreateSecretsManagerClient(ctx context.Context, log logr.Logger) (*secretsmanager.Client, error) {
 	// Precedence: 1. Explicit config  2. Environment variables  3. Instance metadata
-	region := c.determineRegion()
-	endpoint := c.determineEndpoint()
+	return c.createSecretsManagerClientForRegion(ctx, c.determineRegion(), log)
+}
+
+// createSecretsManagerClientForRegion creates a SecretsManager client for the given region
+func (c *AwsClient) createSecretsManagerClientForRegion(ctx context.Context, region string, log logr.Logger) (*secretsmanager.Client, error) {
+	return c.newSecretsManagerClient(ctx, region, c.determineEndpoint(), log)
+}
 
+// newSecretsManagerClient creates a SecretsManager client for the given region, sending requests to endpoint when set
+func (c *AwsClient) newSecretsManagerClient(ctx context.Context, region, endpoint string, log logr.Logger) (*secretsmanager.Client, error) {
 	log.Info("Using AWS configuration", "region", region, "customEndpoint", endpoint != "")
 
 	// Create basic config options
 	opts := []func(*config.LoadOptions) error{
 		config.WithRegion(region),
-		config.WithRetryMaxAttempts(c.Config.MaxRetries),
+		config.WithRetryMaxAttempts(retryMaxAttempts(c.Config.MaxRetries)),
+	}
+	httpOpts, err := c.httpClientOptions()
+	if err != nil {
+		log.Error(err, "Failed to configure the AWS CA bundle")
+		return nil, err
 	}
+	opts = append(opts, httpOpts...)
 
 	// Load configuration with explicit region
 	log.V(1).Info("Loading AWS configuration")
@@ -54,6 +78,7 @@ func (c *AwsClient) CreateSecretsManagerClient(ctx context.Context, log logr.Log
 		log.Error(err, "Failed to load AWS config")
 		return nil, err
 	}
+	c.applyAssumeRole(&cfg, log)
 
 	// Create SecretsManager client options
 	var clientOpts []func(*secretsmanager.Options)
@@ -75,12 +100,34 @@ func (c *AwsClient) CreateSecretsManagerClient(ctx context.Context, log logr.Log
 	return smClient, nil
 }
 
+// ValidateMaxRetries rejects a negative --aws-max-retries
+func ValidateMaxRetries(maxRetries int) error {
+	if maxRetries < 0 {
+		return fmt.Errorf("AWS max retries %d is negative, use 0 to disable retries", maxRetries)
+	}
+	return nil
+}
+
+// retryMaxAttempts maps MaxRetries to the max attempts of the SDK retryer. The SDK treats 0 as its own default,
+// so 0 retries is passed as a single attempt
+func retryMaxAttempts(maxRetries int) int {
+	if maxRetries == 0 {
+		return 1
+	}
+	return maxRetries
+}
+
 // GetCredentialProviderInfo returns information about which credential provider was used
 func (c *AwsClient) GetCredentialProviderInfo(ctx context.Context, log logr.Logger) (string, error) {
 	// Determine the region to use
 	region := c.determineRegion()
 
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	httpOpts, err := c.httpClientOptions()
+	if err != nil {
+		log.Error(err, "Failed to configure the AWS CA bundle")
+		return "", err
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{config.WithRegion(region)}, httpOpts...)...)
 	if err != nil {
 		log.Error(err, "Failed to load AWS config for credential check")
 		return "", err
@@ -96,6 +143,17 @@ func (c *AwsClient) GetCredentialProviderInfo(ctx context.Context, log logr.Logg
 	return creds.Source, nil
 }
 
+// applyAssumeRole replaces the credentials with the configured cross-account role, if any
+func (c *AwsClient) applyAssumeRole(cfg *aws.Config, log logr.Logger) {
+	if c.Config.AssumeRoleArn == "" {
+		return
+	}
+
+	log.Info("Assuming IAM role", "roleArn", c.Config.AssumeRoleArn)
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*cfg), c.Config.AssumeRoleArn)
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+}
+
 // Add helper methods for determining configuration
 func (c *AwsClient) determineRegion() string {
 	// Explicit config has highest priority
@@ -126,6 +184,12 @@ func (c *AwsClient) TestConnection(ctx context.Context, log logr.Logger) error {
 	opts := []func(*config.LoadOptions) error{
 		config.WithRegion(region),
 	}
+	httpOpts, err := c.httpClientOptions()
+	if err != nil {
+		log.Error(err, "Failed to configure the AWS CA bundle")
+		return err
+	}
+	opts = append(opts, httpOpts...)
 
 	log.Info("Testing AWS connectivity", "region", region)
 	cfg, err := config.LoadDefaultConfig(ctx, opts...)
@@ -133,6 +197,7 @@ func (c *AwsClient) TestConnection(ctx context.Context, log logr.Logger) error {
 		log.Error(err, "Failed to load config for connectivity test")
 		return err
 	}
+	c.applyAssumeRole(&cfg, log)
 
 	// Create client
 	client := secretsmanager.NewFromConfig(cfg)
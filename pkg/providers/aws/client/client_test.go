@@ -0,0 +1,28 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryMaxAttempts(t *testing.T) {
+	tests := []struct {
+		maxRetries int
+		expected   int
+	}{
+		{maxRetries: 0, expected: 1},
+		{maxRetries: 1, expected: 1},
+		{maxRetries: 5, expected: 5},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retryMaxAttempts(tt.maxRetries), "maxRetries %d", tt.maxRetries)
+	}
+}
+
+func TestValidateMaxRetries(t *testing.T) {
+	assert.NoError(t, ValidateMaxRetries(0))
+	assert.NoError(t, ValidateMaxRetries(5))
+	assert.Error(t, ValidateMaxRetries(-1))
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+)
+
+// EndpointClientFactory creates a SecretsManager API sending its requests to the given endpoint URL
+type EndpointClientFactory func(ctx context.Context, endpoint string) (SecretsManagerAPI, error)
+
+// ErrEndpointNotAllowed is returned for an endpoint URL the operator was not started with in --allowed-endpoint-urls
+var ErrEndpointNotAllowed = errors.New("endpoint URL is not allowed")
+
+// EndpointSecretsManagers serves the ASecrets that override the AWS endpoint, with one API per endpoint
+// URL created on first use and cached. Only allowed URLs get an API, so tenants cannot send signed requests
+// to hosts of their choosing and the cache never holds more APIs than there are allowed URLs
+type EndpointSecretsManagers struct {
+	factory EndpointClientFactory
+	allowed map[string]bool
+	log     logr.Logger
+
+	mu        sync.Mutex
+	endpoints map[string]SecretsManagerAPI
+}
+
+// NewEndpointSecretsManagers returns the per-endpoint APIs built by factory for the allowed endpoint URLs
+func NewEndpointSecretsManagers(factory EndpointClientFactory, allowed []string, log logr.Logger) *EndpointSecretsManagers {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, endpoint := range allowed {
+		allowedSet[endpoint] = true
+	}
+	return &EndpointSecretsManagers{
+		factory:   factory,
+		allowed:   allowedSet,
+		log:       log,
+		endpoints: make(map[string]SecretsManagerAPI),
+	}
+}
+
+// CreateEndpointSecretsManagers builds the per-endpoint APIs in the primary region. Each endpoint gets its
+// own rate limit and cache, so secrets of the same name behind different endpoints never mix
+func (c *AwsClient) CreateEndpointSecretsManagers(log logr.Logger) *EndpointSecretsManagers {
+	return NewEndpointSecretsManagers(func(ctx context.Context, endpoint string) (SecretsManagerAPI, error) {
+		smClient, err := c.createSecretsManagerClientForEndpoint(ctx, endpoint, log)
+		if err != nil {
+			return nil, err
+		}
+		rateLimited := NewRateLimitedSecretsManager(smClient, c.Config.RateLimit, c.Config.RateBurst)
+		retrying := NewRetryingSecretsManager(rateLimited, c.Config.ThrottleRetries, c.Config.ThrottleRetryDelay)
+		return NewCachingSecretsManager(retrying, c.Config.SecretCacheTTL), nil
+	}, c.Config.AllowedEndpointURLs, log)
+}
+
+// createSecretsManagerClientForEndpoint creates a SecretsManager client of the primary region for the endpoint URL,
+// falling back to the global endpoint when it is empty
+func (c *AwsClient) createSecretsManagerClientForEndpoint(ctx context.Context, endpoint string, log logr.Logger) (*secretsmanager.Client, error) {
+	if endpoint == "" {
+		endpoint = c.determineEndpoint()
+	}
+	return c.newSecretsManagerClient(ctx, c.determineRegion(), endpoint, log)
+}
+
+// For returns the API of the endpoint URL, creating it when needed. An endpoint URL that is not allowed
+// returns an error wrapping ErrEndpointNotAllowed
+func (e *EndpointSecretsManagers) For(ctx context.Context, endpoint string) (SecretsManagerAPI, error) {
+	if !e.allowed[endpoint] {
+		return nil, fmt.Errorf("%w: %s is not listed in --allowed-endpoint-urls", ErrEndpointNotAllowed, endpoint)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if api, ok := e.endpoints[endpoint]; ok {
+		return api, nil
+	}
+	api, err := e.factory(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	e.log.Info("Created AWS SecretsManager client for ASecret endpoint", "endpoint", endpoint)
+	e.endpoints[endpoint] = api
+	return api, nil
+}
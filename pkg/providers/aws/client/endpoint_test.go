@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func TestCreateSecretsManagerClientForEndpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		globalEndpoint string
+		endpoint       string
+		expected       *string
+	}{
+		{
+			name:           "per-secret endpoint overrides the global endpoint",
+			globalEndpoint: "http://localstack:4566",
+			endpoint:       "http://localstack-b:4566",
+			expected:       aws.String("http://localstack-b:4566"),
+		},
+		{
+			name:           "global endpoint without override",
+			globalEndpoint: "http://localstack:4566",
+			expected:       aws.String("http://localstack:4566"),
+		},
+		{
+			name:     "per-secret endpoint without global endpoint",
+			endpoint: "http://localstack-b:4566",
+			expected: aws.String("http://localstack-b:4566"),
+		},
+		{
+			name:     "AWS default endpoint",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_ENDPOINT_URL", "")
+			c := NewClient(awsconfig.AWSConfig{Region: "eu-west-1", EndpointURL: tt.globalEndpoint})
+
+			smClient, err := c.createSecretsManagerClientForEndpoint(context.Background(), tt.endpoint, logr.Discard())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, smClient.Options().BaseEndpoint)
+			assert.Equal(t, "eu-west-1", smClient.Options().Region)
+		})
+	}
+}
+
+func TestEndpointSecretsManagersCachesPerEndpoint(t *testing.T) {
+	apis := map[string]*regionSecretsManager{
+		"http://localstack-a:4566": {values: map[string]string{"/app": "from-a"}},
+		"http://localstack-b:4566": {values: map[string]string{"/app": "from-b"}},
+	}
+	var created []string
+	endpoints := NewEndpointSecretsManagers(func(_ context.Context, endpoint string) (SecretsManagerAPI, error) {
+		created = append(created, endpoint)
+		return apis[endpoint], nil
+	}, []string{"http://localstack-a:4566", "http://localstack-b:4566"}, logr.Discard())
+	ctx := context.Background()
+
+	for _, endpoint := range []string{"http://localstack-a:4566", "http://localstack-b:4566", "http://localstack-a:4566"} {
+		api, err := endpoints.For(ctx, endpoint)
+		require.NoError(t, err)
+		assert.Same(t, apis[endpoint], api)
+	}
+	assert.Equal(t, []string{"http://localstack-a:4566", "http://localstack-b:4566"}, created)
+}
+
+func TestEndpointSecretsManagersFactoryError(t *testing.T) {
+	attempts := 0
+	endpoints := NewEndpointSecretsManagers(func(context.Context, string) (SecretsManagerAPI, error) {
+		attempts++
+		return nil, errors.New("no credentials")
+	}, []string{"http://localstack:4566"}, logr.Discard())
+
+	for range 2 {
+		_, err := endpoints.For(context.Background(), "http://localstack:4566")
+		require.EqualError(t, err, "no credentials")
+	}
+	// A failed construction is not cached, so it is retried by the next reconcile
+	assert.Equal(t, 2, attempts)
+}
+
+func TestEndpointSecretsManagersRefusesEndpointsNotAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+	}{
+		{
+			name:    "no allowed endpoint",
+			allowed: nil,
+		},
+		{
+			name:    "endpoint missing from the allowed ones",
+			allowed: []string{"http://localstack:4566"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := NewEndpointSecretsManagers(func(context.Context, string) (SecretsManagerAPI, error) {
+				t.Fatal("no API must be created for an endpoint that is not allowed")
+				return nil, nil
+			}, tt.allowed, logr.Discard())
+
+			_, err := endpoints.For(context.Background(), "http://169.254.169.254")
+			require.ErrorIs(t, err, ErrEndpointNotAllowed)
+			assert.Contains(t, err.Error(), "http://169.254.169.254")
+			assert.Empty(t, endpoints.endpoints)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/smithy-go"
+
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+)
+
+// errorKinds maps the AWS API error codes the operator acts on to their provider-independent kind
+var errorKinds = map[string]error{
+	"ResourceNotFoundException":   yasoerrors.ErrNotFound,
+	"ThrottlingException":         yasoerrors.ErrThrottled,
+	"TooManyRequestsException":    yasoerrors.ErrThrottled,
+	"RequestLimitExceeded":        yasoerrors.ErrThrottled,
+	"AccessDeniedException":       yasoerrors.ErrAccessDenied,
+	"UnrecognizedClientException": yasoerrors.ErrAccessDenied,
+	"InvalidParameterException":   yasoerrors.ErrInvalidSpec,
+	"InvalidRequestException":     yasoerrors.ErrInvalidSpec,
+	"ValidationException":         yasoerrors.ErrInvalidSpec,
+}
+
+// ClassifyError tags an AWS SecretsManager error with its kind from pkg/errors, leaving the
+// error unchanged when its code is not one the operator distinguishes
+func ClassifyError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	return yasoerrors.Classify(err, errorKinds[apiErr.ErrorCode()])
+}
+
+// IsMarkedForDeletion reports whether err rejects a request on a secret scheduled for deletion, e.g.
+// "You can't perform this operation on the secret because it was marked for deletion." SecretsManager
+// only tells these apart from other invalid requests by their message
+func IsMarkedForDeletion(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRequestException" &&
+		strings.Contains(apiErr.ErrorMessage(), "for deletion")
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "resource not found",
+			err:      &smTypes.ResourceNotFoundException{Message: aws.String("Secrets Manager can't find the specified secret.")},
+			expected: yasoerrors.ErrNotFound,
+		},
+		{
+			name: "not found wrapped in an operation error",
+			err: &smithy.OperationError{
+				ServiceID:     "Secrets Manager",
+				OperationName: "GetSecretValue",
+				Err:           &smTypes.ResourceNotFoundException{Message: aws.String("not found")},
+			},
+			expected: yasoerrors.ErrNotFound,
+		},
+		{
+			name:     "throttling",
+			err:      &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"},
+			expected: yasoerrors.ErrThrottled,
+		},
+		{
+			name:     "access denied",
+			err:      &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform secretsmanager:GetSecretValue"},
+			expected: yasoerrors.ErrAccessDenied,
+		},
+		{
+			name:     "invalid parameter",
+			err:      &smTypes.InvalidParameterException{Message: aws.String("Invalid name")},
+			expected: yasoerrors.ErrInvalidSpec,
+		},
+		{
+			name:     "invalid request",
+			err:      &smTypes.InvalidRequestException{Message: aws.String("secret is scheduled for deletion")},
+			expected: yasoerrors.ErrInvalidSpec,
+		},
+		{
+			name:     "unknown API error code",
+			err:      &smTypes.InternalServiceError{Message: aws.String("internal")},
+			expected: nil,
+		},
+		{
+			name:     "not an API error",
+			err:      errors.New("dial tcp: connection refused"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := ClassifyError(fmt.Errorf("reconcile: %w", tt.err))
+
+			assert.Equal(t, tt.expected, yasoerrors.Kind(classified))
+			assert.ErrorIs(t, classified, tt.err, "the AWS error stays reachable")
+		})
+	}
+
+	assert.NoError(t, ClassifyError(nil))
+}
+
+func TestIsMarkedForDeletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "read of a secret marked for deletion",
+			err:      &smTypes.InvalidRequestException{Message: aws.String("You can't perform this operation on the secret because it was marked for deletion.")},
+			expected: true,
+		},
+		{
+			name: "wrapped in an operation error",
+			err: &smithy.OperationError{
+				ServiceID:     "Secrets Manager",
+				OperationName: "GetSecretValue",
+				Err:           &smTypes.InvalidRequestException{Message: aws.String("secret is scheduled for deletion")},
+			},
+			expected: true,
+		},
+		{
+			name:     "other invalid request",
+			err:      &smTypes.InvalidRequestException{Message: aws.String("You can't perform this operation on a secret replica.")},
+			expected: false,
+		},
+		{
+			name:     "not found",
+			err:      &smTypes.ResourceNotFoundException{Message: aws.String("marked for deletion")},
+			expected: false,
+		},
+		{
+			name:     "not an API error",
+			err:      errors.New("marked for deletion"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsMarkedForDeletion(fmt.Errorf("reconcile: %w", tt.err)))
+		})
+	}
+}
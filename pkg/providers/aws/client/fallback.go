@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+)
+
+// RegionalSecretsManager is a SecretsManager API bound to a region
+type RegionalSecretsManager struct {
+	Region string
+	API    SecretsManagerAPI
+}
+
+// regionFallbackSecretsManager reads secrets from the primary region first and, when a secret is
+// not found there, from each fallback region in order. Writes to an existing secret go to the
+// region it was last read from; new secrets are always created in the primary region
+type regionFallbackSecretsManager struct {
+	primary   RegionalSecretsManager
+	fallbacks []RegionalSecretsManager
+	log       logr.Logger
+
+	mu sync.Mutex
+	// foundIn remembers the fallback serving each secret id; secrets served by the primary region are absent
+	foundIn map[string]RegionalSecretsManager
+}
+
+// NewRegionFallbackSecretsManager wraps the primary region API with fallback regions.
+// Without fallbacks the primary API is returned unchanged
+func NewRegionFallbackSecretsManager(primary RegionalSecretsManager, fallbacks []RegionalSecretsManager, log logr.Logger) SecretsManagerAPI {
+	if len(fallbacks) == 0 {
+		return primary.API
+	}
+
+	return &regionFallbackSecretsManager{
+		primary:   primary,
+		fallbacks: fallbacks,
+		log:       log,
+		foundIn:   make(map[string]RegionalSecretsManager),
+	}
+}
+
+// CreateRegionFallbackSecretsManager builds a SecretsManager client for each configured fallback region
+// and wraps the primary API with them. Fallbacks equal to the primary region are ignored
+func (c *AwsClient) CreateRegionFallbackSecretsManager(ctx context.Context, primary SecretsManagerAPI, log logr.Logger) (SecretsManagerAPI, error) {
+	primaryRegion := c.determineRegion()
+
+	var fallbacks []RegionalSecretsManager
+	for _, region := range c.Config.RegionFallbacks {
+		if region == primaryRegion {
+			continue
+		}
+		smClient, err := c.createSecretsManagerClientForRegion(ctx, region, log)
+		if err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, RegionalSecretsManager{Region: region, API: smClient})
+	}
+
+	if len(fallbacks) > 0 {
+		log.Info("Using AWS fallback regions", "primary", primaryRegion, "fallbacks", c.Config.RegionFallbacks)
+	}
+	return NewRegionFallbackSecretsManager(RegionalSecretsManager{Region: primaryRegion, API: primary}, fallbacks, log), nil
+}
+
+func (f *regionFallbackSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	secretID := aws.ToString(params.SecretId)
+
+	output, err := f.primary.API.GetSecretValue(ctx, params, optFns...)
+	// Secrets referenced by ARN are pinned to the ARN's region
+	if !isNotFound(err) || IsARN(secretID) {
+		if err == nil {
+			f.forget(secretID)
+		}
+		return output, err
+	}
+
+	for _, fallback := range f.fallbacks {
+		fallbackOutput, fallbackErr := fallback.API.GetSecretValue(ctx, params, optFns...)
+		if isNotFound(fallbackErr) {
+			continue
+		}
+		if fallbackErr == nil {
+			f.log.V(1).Info("AWS secret found in fallback region", "path", secretID, "region", fallback.Region)
+			f.remember(secretID, fallback)
+		}
+		return fallbackOutput, fallbackErr
+	}
+
+	// Not found anywhere, report the primary region's error so the secret is created there
+	f.forget(secretID)
+	return output, err
+}
+
+func (f *regionFallbackSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.DescribeSecret(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	return f.primary.API.CreateSecret(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.PutSecretValue(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.UpdateSecret(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.RestoreSecret(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.TagResource(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.UntagResource(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.ReplicateSecretToRegions(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.RemoveRegionsFromReplication(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) GetResourcePolicy(ctx context.Context, params *secretsmanager.GetResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetResourcePolicyOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.GetResourcePolicy(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.PutResourcePolicy(ctx, params, optFns...)
+}
+
+func (f *regionFallbackSecretsManager) DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error) {
+	return f.regionFor(aws.ToString(params.SecretId)).API.DeleteResourcePolicy(ctx, params, optFns...)
+}
+
+// ListSecrets only lists the primary region, fallback regions are searched for a single secret at a time
+func (f *regionFallbackSecretsManager) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	return f.primary.API.ListSecrets(ctx, params, optFns...)
+}
+
+// regionFor returns the region the secret was last read from, defaulting to the primary region
+func (f *regionFallbackSecretsManager) regionFor(secretID string) RegionalSecretsManager {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if fallback, ok := f.foundIn[secretID]; ok {
+		return fallback
+	}
+	return f.primary
+}
+
+// remember records that the secret is served by a fallback region
+func (f *regionFallbackSecretsManager) remember(secretID string, fallback RegionalSecretsManager) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.foundIn[secretID] = fallback
+}
+
+// forget records that the secret is served by the primary region
+func (f *regionFallbackSecretsManager) forget(secretID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.foundIn, secretID)
+}
+
+// isNotFound reports whether the error means the secret does not exist
+func isNotFound(err error) bool {
+	return errors.Is(ClassifyError(err), yasoerrors.ErrNotFound)
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regionSecretsManager is an in-memory region recording the calls it receives
+type regionSecretsManager struct {
+	values map[string]string
+	err    error
+	calls  []string
+}
+
+func (r *regionSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	r.calls = append(r.calls, "GetSecretValue")
+	if r.err != nil {
+		return nil, r.err
+	}
+	value, ok := r.values[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, &smTypes.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func (r *regionSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	r.calls = append(r.calls, "DescribeSecret")
+	return &secretsmanager.DescribeSecretOutput{}, nil
+}
+
+func (r *regionSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	r.calls = append(r.calls, "CreateSecret")
+	r.values[aws.ToString(params.Name)] = aws.ToString(params.SecretString)
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func (r *regionSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	r.calls = append(r.calls, "PutSecretValue")
+	r.values[aws.ToString(params.SecretId)] = aws.ToString(params.SecretString)
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (r *regionSecretsManager) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	r.calls = append(r.calls, "TagResource")
+	return &secretsmanager.TagResourceOutput{}, nil
+}
+
+func (r *regionSecretsManager) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	r.calls = append(r.calls, "RestoreSecret")
+	return &secretsmanager.RestoreSecretOutput{}, nil
+}
+
+func (r *regionSecretsManager) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	r.calls = append(r.calls, "UpdateSecret")
+	return &secretsmanager.UpdateSecretOutput{}, nil
+}
+
+func (r *regionSecretsManager) UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+	r.calls = append(r.calls, "UntagResource")
+	return &secretsmanager.UntagResourceOutput{}, nil
+}
+
+func (r *regionSecretsManager) ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error) {
+	r.calls = append(r.calls, "ReplicateSecretToRegions")
+	return &secretsmanager.ReplicateSecretToRegionsOutput{}, nil
+}
+
+func (r *regionSecretsManager) RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	r.calls = append(r.calls, "RemoveRegionsFromReplication")
+	return &secretsmanager.RemoveRegionsFromReplicationOutput{}, nil
+}
+
+func (r *regionSecretsManager) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	r.calls = append(r.calls, "ListSecrets")
+	return &secretsmanager.ListSecretsOutput{}, nil
+}
+
+func (r *regionSecretsManager) GetResourcePolicy(ctx context.Context, params *secretsmanager.GetResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetResourcePolicyOutput, error) {
+	r.calls = append(r.calls, "GetResourcePolicy")
+	return &secretsmanager.GetResourcePolicyOutput{}, nil
+}
+
+func (r *regionSecretsManager) PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error) {
+	r.calls = append(r.calls, "PutResourcePolicy")
+	return &secretsmanager.PutResourcePolicyOutput{}, nil
+}
+
+func (r *regionSecretsManager) DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error) {
+	r.calls = append(r.calls, "DeleteResourcePolicy")
+	return &secretsmanager.DeleteResourcePolicyOutput{}, nil
+}
+
+func newFallbackTestRegions() (*regionSecretsManager, *regionSecretsManager, *regionSecretsManager, SecretsManagerAPI) {
+	primary := &regionSecretsManager{values: map[string]string{"/app/primary": "from-primary"}}
+	first := &regionSecretsManager{values: map[string]string{"/app/migrating": "from-first", "/app/both": "first"}}
+	second := &regionSecretsManager{values: map[string]string{"/app/both": "second", "/app/old": "from-second"}}
+
+	api := NewRegionFallbackSecretsManager(
+		RegionalSecretsManager{Region: "eu-west-1", API: primary},
+		[]RegionalSecretsManager{
+			{Region: "eu-central-1", API: first},
+			{Region: "us-east-1", API: second},
+		},
+		logr.Discard(),
+	)
+	return primary, first, second, api
+}
+
+func TestNewRegionFallbackSecretsManagerWithoutFallbacks(t *testing.T) {
+	primary := &regionSecretsManager{values: map[string]string{}}
+
+	assert.Same(t, primary, NewRegionFallbackSecretsManager(RegionalSecretsManager{Region: "eu-west-1", API: primary}, nil, logr.Discard()))
+}
+
+func TestRegionFallbackGetSecretValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		secretID      string
+		expectedValue string
+		expectFound   bool
+		primaryCalls  int
+		firstCalls    int
+		secondCalls   int
+	}{
+		{
+			name:          "found in the primary region",
+			secretID:      "/app/primary",
+			expectedValue: "from-primary",
+			expectFound:   true,
+			primaryCalls:  1,
+		},
+		{
+			name:          "not found in primary, found in the first fallback",
+			secretID:      "/app/migrating",
+			expectedValue: "from-first",
+			expectFound:   true,
+			primaryCalls:  1,
+			firstCalls:    1,
+		},
+		{
+			name:          "not found in primary nor first fallback, found in the second",
+			secretID:      "/app/old",
+			expectedValue: "from-second",
+			expectFound:   true,
+			primaryCalls:  1,
+			firstCalls:    1,
+			secondCalls:   1,
+		},
+		{
+			name:          "fallbacks are tried in order",
+			secretID:      "/app/both",
+			expectedValue: "first",
+			expectFound:   true,
+			primaryCalls:  1,
+			firstCalls:    1,
+		},
+		{
+			name:         "not found anywhere",
+			secretID:     "/app/missing",
+			expectFound:  false,
+			primaryCalls: 1,
+			firstCalls:   1,
+			secondCalls:  1,
+		},
+		{
+			name:         "ARNs are never looked up in fallback regions",
+			secretID:     "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app-AbCdEf",
+			expectFound:  false,
+			primaryCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, first, second, api := newFallbackTestRegions()
+
+			output, err := api.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(tt.secretID)})
+			if tt.expectFound {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, aws.ToString(output.SecretString))
+			} else {
+				var notFound *smTypes.ResourceNotFoundException
+				assert.ErrorAs(t, err, &notFound)
+			}
+
+			assert.Len(t, primary.calls, tt.primaryCalls)
+			assert.Len(t, first.calls, tt.firstCalls)
+			assert.Len(t, second.calls, tt.secondCalls)
+		})
+	}
+}
+
+func TestRegionFallbackDoesNotMaskOtherErrors(t *testing.T) {
+	primary, first, _, _ := newFallbackTestRegions()
+	primary.err = errors.New("access denied")
+	api := NewRegionFallbackSecretsManager(
+		RegionalSecretsManager{Region: "eu-west-1", API: primary},
+		[]RegionalSecretsManager{{Region: "eu-central-1", API: first}},
+		logr.Discard(),
+	)
+
+	_, err := api.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/migrating")})
+	assert.EqualError(t, err, "access denied")
+	assert.Empty(t, first.calls)
+}
+
+func TestRegionFallbackWritesFollowTheServingRegion(t *testing.T) {
+	primary, first, _, api := newFallbackTestRegions()
+	ctx := context.Background()
+
+	// A secret read from a fallback region is updated there
+	_, err := api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/migrating")})
+	require.NoError(t, err)
+	_, err = api.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{SecretId: aws.String("/app/migrating"), SecretString: aws.String("updated")})
+	require.NoError(t, err)
+	_, err = api.TagResource(ctx, &secretsmanager.TagResourceInput{SecretId: aws.String("/app/migrating")})
+	require.NoError(t, err)
+	_, err = api.UntagResource(ctx, &secretsmanager.UntagResourceInput{SecretId: aws.String("/app/migrating")})
+	require.NoError(t, err)
+	assert.Equal(t, "updated", first.values["/app/migrating"])
+	assert.Equal(t, []string{"GetSecretValue", "PutSecretValue", "TagResource", "UntagResource"}, first.calls)
+
+	// Missing secrets are created in the primary region
+	_, err = api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/new")})
+	require.Error(t, err)
+	_, err = api.CreateSecret(ctx, &secretsmanager.CreateSecretInput{Name: aws.String("/app/new"), SecretString: aws.String("created")})
+	require.NoError(t, err)
+	assert.Equal(t, "created", primary.values["/app/new"])
+
+	// Once the secret is migrated to the primary region, it takes precedence again
+	primary.values["/app/migrating"] = "migrated"
+	output, err := api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("/app/migrating")})
+	require.NoError(t, err)
+	assert.Equal(t, "migrated", aws.ToString(output.SecretString))
+	_, err = api.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{SecretId: aws.String("/app/migrating"), SecretString: aws.String("again")})
+	require.NoError(t, err)
+	assert.Equal(t, "again", primary.values["/app/migrating"])
+	assert.Equal(t, "updated", first.values["/app/migrating"])
+}
@@ -0,0 +1,26 @@
+package client
+
+import "strings"
+
+// JoinSecretPath prepends the prefix to the secret path with exactly one slash between them.
+// ARNs and empty paths are returned unchanged, and so is every path when the prefix is empty
+func JoinSecretPath(prefix, secretPath string) string {
+	if prefix == "" || secretPath == "" || IsARN(secretPath) {
+		return secretPath
+	}
+
+	prefix = strings.TrimRight(prefix, "/")
+	secretPath = strings.TrimLeft(secretPath, "/")
+	if prefix == "" {
+		return "/" + secretPath
+	}
+	return prefix + "/" + secretPath
+}
+
+// ResolveSecretPath returns the path of the secret in AWS, with the configured secret path prefix applied
+func (c *AwsClient) ResolveSecretPath(secretPath string) string {
+	if c == nil {
+		return secretPath
+	}
+	return JoinSecretPath(c.Config.SecretPathPrefix, secretPath)
+}
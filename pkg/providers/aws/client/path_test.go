@@ -0,0 +1,42 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsconfig "github.com/yaso/yet-another-secrets-operator/pkg/providers/aws/config"
+)
+
+func TestJoinSecretPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefix     string
+		secretPath string
+		expected   string
+	}{
+		{name: "empty prefix keeps the path", prefix: "", secretPath: "/my-app/secrets", expected: "/my-app/secrets"},
+		{name: "simple join", prefix: "myorg/prod", secretPath: "my-app/secrets", expected: "myorg/prod/my-app/secrets"},
+		{name: "trailing slash on prefix", prefix: "myorg/prod/", secretPath: "my-app/secrets", expected: "myorg/prod/my-app/secrets"},
+		{name: "leading slash on path", prefix: "myorg/prod", secretPath: "/my-app/secrets", expected: "myorg/prod/my-app/secrets"},
+		{name: "slashes on both sides", prefix: "myorg/prod//", secretPath: "//my-app/secrets", expected: "myorg/prod/my-app/secrets"},
+		{name: "leading slash on prefix is kept", prefix: "/myorg/prod", secretPath: "my-app", expected: "/myorg/prod/my-app"},
+		{name: "slash-only prefix", prefix: "/", secretPath: "my-app", expected: "/my-app"},
+		{name: "empty path stays empty", prefix: "myorg/prod", secretPath: "", expected: ""},
+		{name: "ARN is never prefixed", prefix: "myorg/prod", secretPath: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-app-AbCdEf", expected: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:my-app-AbCdEf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, JoinSecretPath(tt.prefix, tt.secretPath))
+		})
+	}
+}
+
+func TestResolveSecretPath(t *testing.T) {
+	c := NewClient(awsconfig.AWSConfig{SecretPathPrefix: "myorg/prod/"})
+	assert.Equal(t, "myorg/prod/my-app", c.ResolveSecretPath("/my-app"))
+
+	var unset *AwsClient
+	assert.Equal(t, "/my-app", unset.ResolveSecretPath("/my-app"))
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedSecretsManager throttles calls to the wrapped SecretsManager API client-side,
+// so concurrent reconciles share a single request budget
+type rateLimitedSecretsManager struct {
+	api     SecretsManagerAPI
+	limiter *rate.Limiter
+}
+
+// NewRateLimitedSecretsManager wraps the API with a token bucket allowing requestsPerSecond
+// with the given burst. A non-positive rate disables limiting and returns the API unchanged
+func NewRateLimitedSecretsManager(api SecretsManagerAPI, requestsPerSecond float64, burst int) SecretsManagerAPI {
+	if requestsPerSecond <= 0 {
+		return api
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimitedSecretsManager{
+		api:     api,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+	}
+}
+
+func (r *rateLimitedSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetSecretValue(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.DescribeSecret(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.CreateSecret(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.PutSecretValue(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.UpdateSecret(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.RestoreSecret(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.TagResource(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.UntagResource(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) ReplicateSecretToRegions(ctx context.Context, params *secretsmanager.ReplicateSecretToRegionsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ReplicateSecretToRegionsOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.ReplicateSecretToRegions(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) RemoveRegionsFromReplication(ctx context.Context, params *secretsmanager.RemoveRegionsFromReplicationInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.RemoveRegionsFromReplication(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.ListSecrets(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) GetResourcePolicy(ctx context.Context, params *secretsmanager.GetResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetResourcePolicyOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.GetResourcePolicy(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) PutResourcePolicy(ctx context.Context, params *secretsmanager.PutResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutResourcePolicyOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.PutResourcePolicy(ctx, params, optFns...)
+}
+
+func (r *rateLimitedSecretsManager) DeleteResourcePolicy(ctx context.Context, params *secretsmanager.DeleteResourcePolicyInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteResourcePolicyOutput, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.api.DeleteResourcePolicy(ctx, params, optFns...)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSecretsManager counts GetSecretValue calls and fails every other operation
+type countingSecretsManager struct {
+	SecretsManagerAPI
+	calls atomic.Int64
+}
+
+func (c *countingSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	c.calls.Add(1)
+	return &secretsmanager.GetSecretValueOutput{}, nil
+}
+
+func TestNewRateLimitedSecretsManagerDisabled(t *testing.T) {
+	api := &countingSecretsManager{}
+
+	assert.Same(t, api, NewRateLimitedSecretsManager(api, 0, 10))
+	assert.Same(t, api, NewRateLimitedSecretsManager(api, -1, 10))
+}
+
+func TestRateLimitedSecretsManagerThrottlesConcurrentCalls(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        float64
+		burst       int
+		workers     int
+		callsEach   int
+		minDuration time.Duration
+	}{
+		{
+			name:        "burst absorbs all calls",
+			rate:        10,
+			burst:       20,
+			workers:     4,
+			callsEach:   5,
+			minDuration: 0,
+		},
+		{
+			// 20 calls, 5 from the burst, the remaining 15 at 50/s take at least 300ms
+			name:        "calls beyond the burst are spread at the rate",
+			rate:        50,
+			burst:       5,
+			workers:     4,
+			callsEach:   5,
+			minDuration: 280 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &countingSecretsManager{}
+			limited := NewRateLimitedSecretsManager(api, tt.rate, tt.burst)
+
+			start := time.Now()
+			var wg sync.WaitGroup
+			for range tt.workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range tt.callsEach {
+						_, err := limited.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{})
+						assert.NoError(t, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.GreaterOrEqual(t, time.Since(start), tt.minDuration)
+			assert.Equal(t, int64(tt.workers*tt.callsEach), api.calls.Load())
+		})
+	}
+}
+
+func TestRateLimitedSecretsManagerHonoursContext(t *testing.T) {
+	api := &countingSecretsManager{}
+	limited := NewRateLimitedSecretsManager(api, 0.001, 1)
+
+	_, err := limited.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limited.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{})
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), api.calls.Load())
+}
+
+func BenchmarkRateLimitedSecretsManager(b *testing.B) {
+	api := &countingSecretsManager{}
+	limited := NewRateLimitedSecretsManager(api, 1e9, 1000)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = limited.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{})
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+)
+
+// SecretsLister is the part of the SecretsManager API the reachability check calls
+type SecretsLister interface {
+	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+}
+
+// ReachabilityCheck lists a single secret every Interval in the background and serves the last result as a
+// readiness check, so the probe handler never waits on AWS. A result older than StaleAfter fails the check,
+// which covers a test that hangs past its timeout
+type ReachabilityCheck struct {
+	// Lister is the SecretsManager API under test
+	Lister SecretsLister
+	// Interval is the time between two tests, each test is also bounded by it
+	Interval time.Duration
+	// StaleAfter is the age after which the last result no longer counts, three intervals when zero
+	StaleAfter time.Duration
+	Log        logr.Logger
+
+	// now returns the current time, time.Now when nil
+	now func() time.Time
+
+	mu       sync.Mutex
+	lastErr  error
+	lastTest time.Time
+}
+
+// Start tests reachability right away and then every Interval until ctx is done.
+// It matches manager.Runnable so the manager runs it in the background
+func (c *ReachabilityCheck) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.test(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reports false, every replica tests reachability for its own readiness
+func (c *ReachabilityCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// test lists a single secret and records the outcome
+func (c *ReachabilityCheck) test(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.Interval)
+	defer cancel()
+
+	_, err := c.Lister.ListSecrets(ctx, &secretsmanager.ListSecretsInput{MaxResults: aws.Int32(1)})
+	if err != nil {
+		err = fmt.Errorf("AWS SecretsManager is unreachable: %w", err)
+	}
+	c.record(err)
+}
+
+// record stores the outcome of a test, logging when reachability changes
+func (c *ReachabilityCheck) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case err != nil && c.lastErr == nil:
+		c.Log.Error(err, "AWS reachability check failed")
+	case err == nil && c.lastErr != nil:
+		c.Log.Info("AWS SecretsManager is reachable again")
+	}
+	c.lastErr = err
+	c.lastTest = c.clock()
+}
+
+// Check reports the last test result, failing when no test completed yet or the result is stale.
+// It matches healthz.Checker so it can be registered as a readiness check
+func (c *ReachabilityCheck) Check(_ *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lastTest.IsZero() {
+		return fmt.Errorf("AWS reachability not tested yet")
+	}
+	staleAfter := c.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 3 * c.Interval
+	}
+	if age := c.clock().Sub(c.lastTest); age > staleAfter {
+		return fmt.Errorf("last AWS reachability test is stale (%s old)", age.Round(time.Second))
+	}
+	return c.lastErr
+}
+
+func (c *ReachabilityCheck) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
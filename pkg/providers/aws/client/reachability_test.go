@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLister returns err from every ListSecrets call and counts the calls
+type fakeLister struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (f *fakeLister) ListSecrets(_ context.Context, _ *secretsmanager.ListSecretsInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return &secretsmanager.ListSecretsOutput{}, f.err
+}
+
+func (f *fakeLister) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeLister) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestReachabilityCheckServesCachedResult(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &fakeLister{}
+	check := &ReachabilityCheck{Lister: lister, Interval: time.Minute, Log: logr.Discard(), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	require.EqualError(t, check.Check(nil), "AWS reachability not tested yet")
+
+	check.test(ctx)
+	for range 3 {
+		require.NoError(t, check.Check(nil))
+	}
+	assert.Equal(t, 1, lister.callCount(), "probes are served from the cached result")
+
+	lister.setErr(errors.New("dial tcp: i/o timeout"))
+	now = now.Add(time.Minute)
+	check.test(ctx)
+	require.EqualError(t, check.Check(nil), "AWS SecretsManager is unreachable: dial tcp: i/o timeout")
+
+	lister.setErr(nil)
+	now = now.Add(time.Minute)
+	check.test(ctx)
+	require.NoError(t, check.Check(nil))
+}
+
+func TestReachabilityCheckStaleResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		staleAfter time.Duration
+		age        time.Duration
+		expected   string
+	}{
+		{name: "fresh result", age: 2 * time.Minute},
+		{name: "default window of three intervals", age: 4 * time.Minute, expected: "last AWS reachability test is stale (4m0s old)"},
+		{name: "custom window", staleAfter: 90 * time.Second, age: 2 * time.Minute, expected: "last AWS reachability test is stale (2m0s old)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			check := &ReachabilityCheck{Lister: &fakeLister{}, Interval: time.Minute, StaleAfter: tt.staleAfter, Log: logr.Discard(), now: func() time.Time { return now }}
+			check.test(context.Background())
+
+			now = now.Add(tt.age)
+			if tt.expected != "" {
+				assert.EqualError(t, check.Check(nil), tt.expected)
+			} else {
+				assert.NoError(t, check.Check(nil))
+			}
+		})
+	}
+}
+
+func TestReachabilityCheckStart(t *testing.T) {
+	lister := &fakeLister{}
+	check := &ReachabilityCheck{Lister: lister, Interval: 10 * time.Millisecond, Log: logr.Discard()}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- check.Start(ctx) }()
+
+	require.Eventually(t, func() bool { return lister.callCount() >= 2 }, time.Second, time.Millisecond)
+	assert.NoError(t, check.Check(nil))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after the context was cancelled")
+	}
+}
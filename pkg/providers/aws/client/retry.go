@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+
+	yasoerrors "github.com/yaso/yet-another-secrets-operator/pkg/errors"
+)
+
+// maxRetryDelay caps the delay between two retries of a throttled call
+const maxRetryDelay = 10 * time.Second
+
+// retryingSecretsManager retries throttled and server-side failures of GetSecretValue and PutSecretValue
+// with decorrelated jitter, on top of the SDK retries. Other calls are passed through unchanged
+type retryingSecretsManager struct {
+	SecretsManagerAPI
+	retries   int
+	baseDelay time.Duration
+}
+
+// NewRetryingSecretsManager wraps the API so GetSecretValue and PutSecretValue are retried up to retries
+// times after a throttling or 5xx error, waiting between baseDelay and maxRetryDelay. Non-positive
+// retries disable retrying and return the API unchanged
+func NewRetryingSecretsManager(api SecretsManagerAPI, retries int, baseDelay time.Duration) SecretsManagerAPI {
+	if retries <= 0 {
+		return api
+	}
+	return &retryingSecretsManager{SecretsManagerAPI: api, retries: retries, baseDelay: baseDelay}
+}
+
+func (r *retryingSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	return retry(ctx, r, func() (*secretsmanager.GetSecretValueOutput, error) {
+		return r.SecretsManagerAPI.GetSecretValue(ctx, params, optFns...)
+	})
+}
+
+// PutSecretValue is safe to retry as long as the caller sets ClientRequestToken: every attempt sends the same
+// params, so AWS ignores a retry of a write that already landed instead of adding another version
+func (r *retryingSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	return retry(ctx, r, func() (*secretsmanager.PutSecretValueOutput, error) {
+		return r.SecretsManagerAPI.PutSecretValue(ctx, params, optFns...)
+	})
+}
+
+// retry runs call until it succeeds, fails with an error that is not transient, or the retries are used up
+func retry[T any](ctx context.Context, r *retryingSecretsManager, call func() (T, error)) (T, error) {
+	delay := r.baseDelay
+	for attempt := 0; ; attempt++ {
+		output, err := call()
+		if err == nil || attempt >= r.retries || !isTransientError(err) {
+			return output, err
+		}
+
+		delay = nextRetryDelay(r.baseDelay, delay, rand.Float64())
+		select {
+		case <-ctx.Done():
+			return output, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// nextRetryDelay returns the decorrelated jitter delay following previous, a random duration between base
+// and three times previous, capped at maxRetryDelay. random is in [0, 1)
+func nextRetryDelay(base, previous time.Duration, random float64) time.Duration {
+	upper := 3 * previous
+	if upper < base {
+		upper = base
+	}
+	delay := base + time.Duration(random*float64(upper-base))
+	return min(delay, maxRetryDelay)
+}
+
+// isTransientError reports whether err is a throttling error or a server-side failure of SecretsManager
+func isTransientError(err error) bool {
+	if errors.Is(ClassifyError(err), yasoerrors.ErrThrottled) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorFault() == smithy.FaultServer {
+		return true
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &statusErr) && statusErr.HTTPStatusCode() >= http.StatusInternalServerError
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySecretsManager fails the first failures GetSecretValue and PutSecretValue calls with err
+type flakySecretsManager struct {
+	SecretsManagerAPI
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakySecretsManager) call() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakySecretsManager) GetSecretValue(_ context.Context, _ *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return &secretsmanager.GetSecretValueOutput{}, nil
+}
+
+func (f *flakySecretsManager) PutSecretValue(_ context.Context, _ *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+// serverError is an SDK response error with the given HTTP status
+func serverError(status int) error {
+	return &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("upstream failure"),
+	}}
+}
+
+func TestNewRetryingSecretsManagerDisabled(t *testing.T) {
+	api := &flakySecretsManager{}
+
+	assert.Same(t, api, NewRetryingSecretsManager(api, 0, time.Millisecond))
+	assert.Same(t, api, NewRetryingSecretsManager(api, -1, time.Millisecond))
+}
+
+func TestRetryingSecretsManager(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded"}
+
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		expectedCalls int
+		expectError   bool
+	}{
+		{name: "success is not retried", expectedCalls: 1},
+		{name: "throttling is retried until success", failures: 2, err: throttled, expectedCalls: 3},
+		{name: "too many requests is retried", failures: 1, err: &smithy.GenericAPIError{Code: "TooManyRequestsException"}, expectedCalls: 2},
+		{name: "server fault is retried", failures: 1, err: &smithy.GenericAPIError{Code: "InternalServiceError", Fault: smithy.FaultServer}, expectedCalls: 2},
+		{name: "5xx response is retried", failures: 2, err: serverError(http.StatusServiceUnavailable), expectedCalls: 3},
+		{name: "retries are bounded", failures: 10, err: throttled, expectedCalls: 4, expectError: true},
+		{name: "access denied is not retried", failures: 1, err: &smithy.GenericAPIError{Code: "AccessDeniedException"}, expectedCalls: 1, expectError: true},
+		{name: "4xx response is not retried", failures: 1, err: serverError(http.StatusBadRequest), expectedCalls: 1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		for _, operation := range []string{"GetSecretValue", "PutSecretValue"} {
+			t.Run(tt.name+"/"+operation, func(t *testing.T) {
+				flaky := &flakySecretsManager{failures: tt.failures, err: tt.err}
+				api := NewRetryingSecretsManager(flaky, 3, time.Millisecond)
+
+				var err error
+				if operation == "GetSecretValue" {
+					_, err = api.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{})
+				} else {
+					_, err = api.PutSecretValue(context.Background(), &secretsmanager.PutSecretValueInput{})
+				}
+
+				if tt.expectError {
+					require.ErrorIs(t, err, tt.err)
+				} else {
+					require.NoError(t, err)
+				}
+				assert.Equal(t, tt.expectedCalls, flaky.calls)
+			})
+		}
+	}
+}
+
+func TestRetryingSecretsManagerStopsOnCancelledContext(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	flaky := &flakySecretsManager{failures: 10, err: throttled}
+	api := NewRetryingSecretsManager(flaky, 5, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{})
+	require.ErrorIs(t, err, throttled)
+	assert.Equal(t, 1, flaky.calls)
+}
+
+func TestNextRetryDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		previous time.Duration
+		random   float64
+		expected time.Duration
+	}{
+		{name: "lowest delay is the base", previous: base, random: 0, expected: base},
+		{name: "highest delay is three times the previous one", previous: time.Second, random: 0.999999999, expected: 3 * time.Second},
+		{name: "delays grow from the previous one", previous: time.Second, random: 0.5, expected: 1550 * time.Millisecond},
+		{name: "delays are capped", previous: 8 * time.Second, random: 0.9, expected: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, float64(tt.expected), float64(nextRetryDelay(base, tt.previous, tt.random)), float64(time.Millisecond))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"github.com/google/uuid"
+)
+
+// clientRequestTokenNamespace scopes the operator's idempotency tokens
+var clientRequestTokenNamespace = uuid.NewSHA1(uuid.NameSpaceDNS, []byte("yet-another-secrets.io"))
+
+// ClientRequestToken derives the CreateSecret idempotency token from the secret path and value.
+// A create retried after a partial failure sends the same token, which AWS treats as the same request
+// as long as the value is unchanged, instead of failing because the secret already exists
+func ClientRequestToken(secretPath string, value []byte) string {
+	data := make([]byte, 0, len(secretPath)+1+len(value))
+	data = append(data, secretPath...)
+	data = append(data, 0)
+	data = append(data, value...)
+	return uuid.NewSHA1(clientRequestTokenNamespace, data).String()
+}
+
+// PutSecretValueToken derives the PutSecretValue idempotency token from the secret path, the AWSCURRENT
+// version the write replaces and the new value. Every retry of a write sends the same token, so a retry
+// after a lost response does not add a second version. Writing a value back after another one replaces a
+// different version and gets a new token, where a token of the path and value alone would repeat the
+// first write's and AWS would ignore the request
+func PutSecretValueToken(secretPath, currentVersionID string, value []byte) string {
+	return ClientRequestToken(secretPath+"\x00"+currentVersionID, value)
+}
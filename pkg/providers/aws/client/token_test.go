@@ -0,0 +1,35 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRequestToken(t *testing.T) {
+	token := ClientRequestToken("/my-app/secrets", []byte(`{"password":"secret"}`))
+
+	// AWS requires 32 to 64 characters, a UUID is what the SDK itself would send
+	parsed, err := uuid.Parse(token)
+	require.NoError(t, err)
+	assert.Len(t, token, 36)
+	assert.Equal(t, uuid.Version(5), parsed.Version())
+
+	assert.Equal(t, token, ClientRequestToken("/my-app/secrets", []byte(`{"password":"secret"}`)), "identical content must give the same token")
+	assert.NotEqual(t, token, ClientRequestToken("/my-app/secrets", []byte(`{"password":"other"}`)), "different content must give another token")
+	assert.NotEqual(t, token, ClientRequestToken("/other-app/secrets", []byte(`{"password":"secret"}`)), "different paths must give another token")
+	assert.NotEqual(t, ClientRequestToken("/a", []byte("b/c")), ClientRequestToken("/a/b", []byte("c")), "path and value must not run together")
+}
+
+func TestPutSecretValueToken(t *testing.T) {
+	token := PutSecretValueToken("/my-app/secrets", "v1", []byte(`{"password":"secret"}`))
+
+	_, err := uuid.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, token, PutSecretValueToken("/my-app/secrets", "v1", []byte(`{"password":"secret"}`)), "a retried write must send the same token")
+	assert.NotEqual(t, token, PutSecretValueToken("/my-app/secrets", "v3", []byte(`{"password":"secret"}`)), "writing a value back over a newer version must get another token")
+	assert.NotEqual(t, token, PutSecretValueToken("/my-app/secrets", "v1", []byte(`{"password":"other"}`)), "different content must give another token")
+	assert.NotEqual(t, token, ClientRequestToken("/my-app/secrets", []byte(`{"password":"secret"}`)), "writes must not reuse the token of the create")
+}
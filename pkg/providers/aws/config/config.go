@@ -2,39 +2,93 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 )
 
 // OperatorConfig holds all configuration for the operator
 type OperatorConfig struct {
-	AWS    AWSConfig
-	Health HealthConfig
-	Leader LeaderElectionConfig
-	Debug  bool
+	AWS        AWSConfig
+	Health     HealthConfig
+	Leader     LeaderElectionConfig
+	Controller ControllerConfig
+	Webhook    WebhookConfig
+	Debug      bool
+	LogFormat  string
 }
 
 // AWSConfig holds AWS-specific configuration
 type AWSConfig struct {
-	Region           string
-	EndpointURL      string
-	MaxRetries       int
-	RemoveRemoteKeys bool
-	DefaultKmsKeyId  string
-	Tags             map[string]string
+	Region              string
+	RegionFallbacks     []string
+	EndpointURL         string
+	AllowedEndpointURLs []string
+	CABundle            string
+	MaxRetries          int
+	RemoveRemoteKeys    bool
+	PruneTags           bool
+	DefaultKmsKeyId     string
+	AssumeRoleArn       string
+	RateLimit           float64
+	RateBurst           int
+	SecretCacheTTL      time.Duration
+	VerifyWriteAttempts int
+	VerifyWriteInterval time.Duration
+	ThrottleRetries     int
+	ThrottleRetryDelay  time.Duration
+	SkipConnTest        bool
+	SecretPathPrefix    string
+	MaxSecretBytes      int
+	MaxPrefixSecrets    int
+	MaxAWSClients       int
+	Tags                map[string]string
 }
 
 // HealthConfig holds health server configuration
 type HealthConfig struct {
 	ProbeBindAddress   string
 	MetricsBindAddress string
+	PprofBindAddress   string
+	AWSCheckInterval   time.Duration
 }
 
 // LeaderElectionConfig holds leader election configuration
 type LeaderElectionConfig struct {
+	Enabled   bool
+	ID        string
+	Namespace string
+}
+
+// ControllerConfig holds reconciliation behavior configuration
+type ControllerConfig struct {
+	StartupSweepSpread        time.Duration
+	WatchNamespaces           []string
+	MaxConcurrentReconciles   int
+	DryRun                    bool
+	ReconcileMode             string
+	RefreshJitter             int
+	GlobalResyncPeriod        time.Duration
+	RecordLastChange          bool
+	ReadOnly                  bool
+	SkipNamespaceAccessCheck  bool
+	Once                      string
+	ObjectLabelSelector       string
+	EnableGeneratorController bool
+	DetectRemoteConflicts     bool
+	GracefulShutdownTimeout   time.Duration
+	StartupSpread             time.Duration
+	GCOrphans                 bool
+	GCDelete                  bool
+}
+
+// WebhookConfig holds admission webhook server configuration
+type WebhookConfig struct {
 	Enabled bool
-	ID      string
+	Port    int
+	CertDir string
 }
 
 // NewDefaultConfig returns a config with default values
@@ -45,22 +99,66 @@ func NewDefaultConfig() *OperatorConfig {
 
 	return &OperatorConfig{
 		AWS: AWSConfig{
-			Region:           "",
-			EndpointURL:      "",
-			MaxRetries:       5,
-			RemoveRemoteKeys: true,
-			DefaultKmsKeyId:  "",
-			Tags:             defaultTags,
+			Region:              "",
+			RegionFallbacks:     nil,
+			EndpointURL:         "",
+			AllowedEndpointURLs: nil,
+			CABundle:            "",
+			MaxRetries:          5,
+			RemoveRemoteKeys:    true,
+			PruneTags:           false,
+			DefaultKmsKeyId:     "",
+			AssumeRoleArn:       "",
+			RateLimit:           10,
+			RateBurst:           20,
+			SecretCacheTTL:      0,
+			VerifyWriteAttempts: 3,
+			VerifyWriteInterval: time.Second,
+			ThrottleRetries:     3,
+			ThrottleRetryDelay:  200 * time.Millisecond,
+			SkipConnTest:        false,
+			SecretPathPrefix:    "",
+			MaxSecretBytes:      65536,
+			MaxPrefixSecrets:    100,
+			MaxAWSClients:       32,
+			Tags:                defaultTags,
 		},
 		Health: HealthConfig{
 			ProbeBindAddress:   ":8081",
 			MetricsBindAddress: ":8080",
+			AWSCheckInterval:   time.Minute,
 		},
 		Leader: LeaderElectionConfig{
+			Enabled:   false,
+			ID:        "aso.yaso.io",
+			Namespace: "",
+		},
+		Controller: ControllerConfig{
+			StartupSweepSpread:        0,
+			MaxConcurrentReconciles:   1,
+			DryRun:                    false,
+			ReconcileMode:             "event",
+			RefreshJitter:             10,
+			GlobalResyncPeriod:        0,
+			RecordLastChange:          false,
+			ReadOnly:                  false,
+			SkipNamespaceAccessCheck:  false,
+			Once:                      "",
+			ObjectLabelSelector:       "",
+			EnableGeneratorController: true,
+			DetectRemoteConflicts:     false,
+			GracefulShutdownTimeout:   30 * time.Second,
+			StartupSpread:             0,
+			GCOrphans:                 false,
+			GCDelete:                  false,
+		},
+		Webhook: WebhookConfig{
 			Enabled: false,
-			ID:      "aso.yaso.io",
+			Port:    9443,
+			CertDir: "",
 		},
-		Debug: false,
+		Debug:     false,
+		LogFormat: "",
 	}
 }
 
@@ -68,20 +166,67 @@ func NewDefaultConfig() *OperatorConfig {
 func (c *OperatorConfig) AddFlags(flags *pflag.FlagSet) {
 	// AWS flags
 	flags.StringVar(&c.AWS.Region, "aws-region", c.AWS.Region, "AWS Region to use")
+	flags.StringSliceVar(&c.AWS.RegionFallbacks, "aws-region-fallbacks", c.AWS.RegionFallbacks, "Comma-separated list of regions to read a secret from, in order, when it is not found in the primary region.")
 	flags.StringVar(&c.AWS.EndpointURL, "aws-endpoint", c.AWS.EndpointURL, "Custom AWS endpoint URL")
-	flags.IntVar(&c.AWS.MaxRetries, "aws-max-retries", c.AWS.MaxRetries, "Maximum number of AWS API retries")
+	flags.StringSliceVar(&c.AWS.AllowedEndpointURLs, "allowed-endpoint-urls", c.AWS.AllowedEndpointURLs, "Comma-separated list of endpoint URLs ASecrets may send their requests to with spec.endpointURL. Empty refuses every spec.endpointURL.")
+	flags.StringVar(&c.AWS.CABundle, "aws-ca-bundle", c.AWS.CABundle, "Path of a PEM file, or inline PEM certificates, of CAs trusted for AWS requests on top of the system roots, e.g. for an --aws-endpoint proxy with a private CA.")
+	flags.IntVar(&c.AWS.MaxRetries, "aws-max-retries", c.AWS.MaxRetries, "Maximum number of AWS API attempts of the SDK retryer, 0 makes a single attempt without retries")
 	flags.BoolVar(&c.AWS.RemoveRemoteKeys, "remove-remote-keys", c.AWS.RemoveRemoteKeys, "Remove remote keys if they don't exist in the CR.")
+	flags.BoolVar(&c.AWS.PruneTags, "prune-aws-tags", c.AWS.PruneTags, "Remove tags from updated AWS secrets that are neither in the ASecret spec nor in the global tags.")
 	flags.StringVar(&c.AWS.DefaultKmsKeyId, "aws-default-kms-key-id", c.AWS.DefaultKmsKeyId, "Default KMS key ID for encryption")
+	flags.StringVar(&c.AWS.AssumeRoleArn, "aws-assume-role-arn", c.AWS.AssumeRoleArn, "IAM role ARN to assume for cross-account access. Secrets referenced by ARN must belong to this role's account.")
+	flags.Float64Var(&c.AWS.RateLimit, "aws-rate-limit", c.AWS.RateLimit, "Maximum AWS SecretsManager requests per second shared by all reconciles. Set to 0 to disable.")
+	flags.DurationVar(&c.AWS.SecretCacheTTL, "secret-cache-ttl", c.AWS.SecretCacheTTL, "How long AWS GetSecretValue responses are cached and shared between ASecrets referencing the same path. Set to 0 to disable.")
+	flags.IntVar(&c.AWS.RateBurst, "aws-rate-burst", c.AWS.RateBurst, "Number of AWS SecretsManager requests allowed in a burst above the rate limit.")
+	flags.IntVar(&c.AWS.VerifyWriteAttempts, "aws-verify-write-attempts", c.AWS.VerifyWriteAttempts, "Number of reads confirming a write for ASecrets with verifyWrite before the sync fails.")
+	flags.DurationVar(&c.AWS.VerifyWriteInterval, "aws-verify-write-interval", c.AWS.VerifyWriteInterval, "Delay before retrying a stale verify read, doubled after each attempt.")
+	flags.IntVar(&c.AWS.ThrottleRetries, "aws-throttle-retries", c.AWS.ThrottleRetries, "Number of retries of AWS secret reads and writes failing with throttling or a server error. Set to 0 to disable.")
+	flags.DurationVar(&c.AWS.ThrottleRetryDelay, "aws-throttle-retry-base-delay", c.AWS.ThrottleRetryDelay, "Smallest delay before retrying a throttled AWS call, grown with decorrelated jitter after each retry.")
+	flags.IntVar(&c.AWS.MaxSecretBytes, "max-secret-bytes", c.AWS.MaxSecretBytes, "Largest AWS secret value, in bytes, the operator writes. Larger values fail the sync with a SecretTooLarge reason instead of an AWS error. Set to 0 to disable the check.")
+	flags.IntVar(&c.AWS.MaxPrefixSecrets, "max-prefix-secrets", c.AWS.MaxPrefixSecrets, "Most AWS secrets a single ASecret imports through its pathPrefix. A prefix matching more fails the sync. Set to 0 to disable the cap.")
+	flags.IntVar(&c.AWS.MaxAWSClients, "max-aws-clients", c.AWS.MaxAWSClients, "Most SecretsManager clients kept for the regions of secret ARNs. Beyond it the least recently used client is dropped and rebuilt on its next use. Set to 0 to disable the bound.")
+	flags.StringVar(&c.AWS.SecretPathPrefix, "secret-path-prefix", c.AWS.SecretPathPrefix, "Prefix prepended to the awsSecretPath of every ASecret, e.g. myorg/prod/. Secrets referenced by ARN are not prefixed.")
+	flags.BoolVar(&c.AWS.SkipConnTest, "skip-aws-test", c.AWS.SkipConnTest, "Skip the AWS SecretsManager connectivity test at startup, for endpoints that only become reachable later.")
 
 	// Health and metrics flags
 	flags.StringVar(&c.Health.ProbeBindAddress, "health-probe-bind-address", c.Health.ProbeBindAddress, "The address the probe endpoint binds to.")
 	flags.StringVar(&c.Health.MetricsBindAddress, "metrics-bind-address", c.Health.MetricsBindAddress, "The address the metrics endpoint binds to.")
+	flags.StringVar(&c.Health.PprofBindAddress, "pprof-bind-address", c.Health.PprofBindAddress, "The address the pprof endpoint (/debug/pprof/) binds to, e.g. 127.0.0.1:6060. Empty disables profiling.")
+	flags.DurationVar(&c.Health.AWSCheckInterval, "aws-readiness-interval", c.Health.AWSCheckInterval, "Interval of the background AWS SecretsManager reachability test backing the aws readiness check. Set to 0 to disable the check.")
 
 	// Leader election flags
 	flags.BoolVar(&c.Leader.Enabled, "leader-elect", c.Leader.Enabled, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flags.StringVar(&c.Leader.ID, "leader-elect-id", c.Leader.ID, "Name of the leader election lease. Operator instances sharing a cluster, e.g. staging and prod, need distinct IDs.")
+	flags.StringVar(&c.Leader.Namespace, "leader-elect-namespace", c.Leader.Namespace, "Namespace of the leader election lease. Defaults to the namespace the operator runs in.")
+
+	// Controller flags
+	flags.StringSliceVar(&c.Controller.WatchNamespaces, "watch-namespaces", c.Controller.WatchNamespaces, "Comma-separated list of namespaces to reconcile ASecrets in. Empty means all namespaces.")
+	flags.BoolVar(&c.Controller.SkipNamespaceAccessCheck, "skip-namespace-access-check", c.Controller.SkipNamespaceAccessCheck, "Skip the startup check that the ServiceAccount holds the required permissions in each of --watch-namespaces.")
+	flags.IntVar(&c.Controller.MaxConcurrentReconciles, "max-concurrent-reconciles", c.Controller.MaxConcurrentReconciles, "Maximum number of ASecrets reconciled in parallel.")
+	flags.StringVar(&c.Controller.ReconcileMode, "reconcile-mode", c.Controller.ReconcileMode, "How ASecrets are kept in sync: \"event\" also reconciles on changes to managed Secrets and refreshes hourly, \"poll\" only watches ASecrets and refreshes every 6h.")
+	flags.DurationVar(&c.Controller.GracefulShutdownTimeout, "graceful-shutdown-timeout", c.Controller.GracefulShutdownTimeout, "Time given to in-flight reconciles to finish their writes to Kubernetes and AWS after a termination signal before the operator exits. Set to 0 to stop immediately.")
+	flags.BoolVar(&c.Controller.DetectRemoteConflicts, "detect-remote-conflicts", c.Controller.DetectRemoteConflicts, "Refuse to sync an ASecret writing the same AWS secret as another ASecret, e.g. in another namespace, with a RemotePathConflict condition. The first ASecret reconciled keeps the secret.")
+	flags.BoolVar(&c.Controller.EnableGeneratorController, "enable-generator-controller", c.Controller.EnableGeneratorController, "Run the AGenerator controller. When false, the operator needs no AGenerator RBAC and ASecret keys that need a generated value fail to sync.")
+	flags.StringVar(&c.Controller.ObjectLabelSelector, "object-label-selector", c.Controller.ObjectLabelSelector, "Label selector, e.g. tier=prod, restricting the ASecrets reconciled and cached by this operator, to shard ASecrets across operators. Empty means all ASecrets.")
+	flags.IntVar(&c.Controller.RefreshJitter, "refresh-jitter", c.Controller.RefreshJitter, "Percentage by which each refresh requeue is randomly shortened or lengthened, so ASecrets created together do not refresh at the same instant. Set to 0 to disable.")
+	flags.DurationVar(&c.Controller.GlobalResyncPeriod, "global-resync-period", c.Controller.GlobalResyncPeriod, "Period of the informer resync that re-reconciles every ASecret regardless of its refreshInterval, as a safety net for missed events. Set to 0 to disable.")
+	flags.BoolVar(&c.Controller.RecordLastChange, "record-last-change", c.Controller.RecordLastChange, "Record the key names added, changed and removed by the last update of each Secret in the yet-another-secrets.io/last-change annotation.")
+	flags.BoolVar(&c.Controller.ReadOnly, "read-only", c.Controller.ReadOnly, "Never create, update, tag or replicate AWS secrets, treating AWS as a source of truth managed elsewhere. Kubernetes Secrets are still written.")
+	flags.StringVar(&c.Controller.Once, "once", c.Controller.Once, "Reconcile the single ASecret <namespace>/<name> once and exit, non-zero if it did not sync, e.g. from an init container. No manager or leader election is started.")
+	flags.BoolVar(&c.Controller.GCOrphans, "gc-orphans", c.Controller.GCOrphans, "Report the AWS secrets carrying the managed-by tag that no ASecret references anymore and exit. No manager or leader election is started.")
+	flags.BoolVar(&c.Controller.GCDelete, "gc-delete", c.Controller.GCDelete, "With --gc-orphans, delete the orphaned AWS secrets with the default recovery window instead of only reporting them.")
+	flags.BoolVar(&c.Controller.DryRun, "dry-run", c.Controller.DryRun, "Compute and log the changes of each reconcile without writing to Kubernetes Secrets or AWS.")
+	flags.DurationVar(&c.Controller.StartupSpread, "startup-spread", c.Controller.StartupSpread, "Window within which the first reconcile of each ASecret already in sync is deferred by a random delay after startup, to avoid a burst of AWS calls. New and changed ASecrets are reconciled right away. Set to 0 to disable.")
+	flags.DurationVar(&c.Controller.StartupSweepSpread, "startup-sweep-spread", c.Controller.StartupSweepSpread, "Window over which all ASecrets are re-reconciled after startup to repair drift. ASecrets already deferred by --startup-spread are skipped. Set to 0 to disable.")
+
+	// Webhook flags
+	flags.BoolVar(&c.Webhook.Enabled, "enable-webhooks", c.Webhook.Enabled, "Enable the ASecret validating admission webhook.")
+	flags.IntVar(&c.Webhook.Port, "webhook-port", c.Webhook.Port, "The port the webhook server listens on.")
+	flags.StringVar(&c.Webhook.CertDir, "webhook-cert-dir", c.Webhook.CertDir, "Directory containing the webhook server TLS certificate (tls.crt/tls.key). Defaults to the controller-runtime location.")
 
 	// Debug
 	flags.BoolVar(&c.Debug, "debug", c.Debug, "Enable development mode of zap for logging extra informations.")
+	flags.StringVar(&c.LogFormat, "log-format", c.LogFormat, "Format of the operator logs, json or console. Defaults to console with --debug and json otherwise.")
 }
 
 // LoadFromEnv loads config values from environment variables
@@ -91,16 +236,43 @@ func (c *OperatorConfig) LoadFromEnv() {
 		c.AWS.Region = GetDefaultRegion()
 	}
 
+	// AWS fallback regions
+	if len(c.AWS.RegionFallbacks) == 0 {
+		if regions := os.Getenv("AWS_REGION_FALLBACKS"); regions != "" {
+			c.AWS.RegionFallbacks = strings.Split(regions, ",")
+		}
+	}
+	c.AWS.RegionFallbacks = normalizeList(c.AWS.RegionFallbacks)
+
 	// AWS Endpoint URL
 	if c.AWS.EndpointURL == "" {
 		c.AWS.EndpointURL = os.Getenv("AWS_ENDPOINT_URL")
 	}
+	c.AWS.AllowedEndpointURLs = normalizeList(c.AWS.AllowedEndpointURLs)
 
 	// AWS Default KMS Key ID
 	if c.AWS.DefaultKmsKeyId == "" {
 		c.AWS.DefaultKmsKeyId = os.Getenv("AWS_DEFAULT_KMS_KEY_ID")
 	}
 
+	// AWS Assume Role ARN
+	if c.AWS.AssumeRoleArn == "" {
+		c.AWS.AssumeRoleArn = os.Getenv("AWS_ASSUME_ROLE_ARN")
+	}
+
+	// Skip the startup connectivity test
+	if !c.AWS.SkipConnTest {
+		c.AWS.SkipConnTest, _ = strconv.ParseBool(os.Getenv("SKIP_AWS_CONN_TEST"))
+	}
+
+	// Watched namespaces
+	if len(c.Controller.WatchNamespaces) == 0 {
+		if namespaces := os.Getenv("WATCH_NAMESPACES"); namespaces != "" {
+			c.Controller.WatchNamespaces = strings.Split(namespaces, ",")
+		}
+	}
+	c.Controller.WatchNamespaces = normalizeList(c.Controller.WatchNamespaces)
+
 	// Load tags from environment variables
 	for _, env := range os.Environ() {
 		if strings.HasPrefix(env, "AWS_TAG_") {
@@ -113,6 +285,21 @@ func (c *OperatorConfig) LoadFromEnv() {
 	}
 }
 
+// normalizeList trims list entries such as namespaces or regions and drops empty and duplicate ones, keeping order
+func normalizeList(entries []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		result = append(result, entry)
+	}
+	return result
+}
+
 // getDefaultRegion tries to get an AWS region from environment variables
 func GetDefaultRegion() string {
 	possibleEnvVars := []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
@@ -127,11 +314,28 @@ func GetDefaultRegion() string {
 // ToAWSConfig converts the config to a format usable by controllers
 func (c *OperatorConfig) ToAWSConfig() AWSConfig {
 	return AWSConfig{
-		Region:           c.AWS.Region,
-		EndpointURL:      c.AWS.EndpointURL,
-		MaxRetries:       c.AWS.MaxRetries,
-		RemoveRemoteKeys: c.AWS.RemoveRemoteKeys,
-		DefaultKmsKeyId:  c.AWS.DefaultKmsKeyId,
-		Tags:             c.AWS.Tags,
+		Region:              c.AWS.Region,
+		RegionFallbacks:     c.AWS.RegionFallbacks,
+		EndpointURL:         c.AWS.EndpointURL,
+		AllowedEndpointURLs: c.AWS.AllowedEndpointURLs,
+		CABundle:            c.AWS.CABundle,
+		MaxRetries:          c.AWS.MaxRetries,
+		RemoveRemoteKeys:    c.AWS.RemoveRemoteKeys,
+		PruneTags:           c.AWS.PruneTags,
+		DefaultKmsKeyId:     c.AWS.DefaultKmsKeyId,
+		AssumeRoleArn:       c.AWS.AssumeRoleArn,
+		RateLimit:           c.AWS.RateLimit,
+		RateBurst:           c.AWS.RateBurst,
+		SecretCacheTTL:      c.AWS.SecretCacheTTL,
+		VerifyWriteAttempts: c.AWS.VerifyWriteAttempts,
+		VerifyWriteInterval: c.AWS.VerifyWriteInterval,
+		ThrottleRetries:     c.AWS.ThrottleRetries,
+		ThrottleRetryDelay:  c.AWS.ThrottleRetryDelay,
+		SkipConnTest:        c.AWS.SkipConnTest,
+		SecretPathPrefix:    c.AWS.SecretPathPrefix,
+		MaxSecretBytes:      c.AWS.MaxSecretBytes,
+		MaxPrefixSecrets:    c.AWS.MaxPrefixSecrets,
+		MaxAWSClients:       c.AWS.MaxAWSClients,
+		Tags:                c.AWS.Tags,
 	}
 }
@@ -0,0 +1,557 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected []string
+	}{
+		{
+			name:     "defaults to all namespaces",
+			args:     []string{},
+			expected: nil,
+		},
+		{
+			name:     "comma-separated flag",
+			args:     []string{"--watch-namespaces=team-a,team-b"},
+			expected: []string{"team-a", "team-b"},
+		},
+		{
+			name:     "repeated flag",
+			args:     []string{"--watch-namespaces=team-a", "--watch-namespaces=team-b"},
+			expected: []string{"team-a", "team-b"},
+		},
+		{
+			name:     "spaces, empty entries and duplicates are dropped",
+			args:     []string{"--watch-namespaces= team-a ,,team-b,team-a"},
+			expected: []string{"team-a", "team-b"},
+		},
+		{
+			name:     "environment variable when flag is not set",
+			args:     []string{},
+			env:      "team-c, team-d",
+			expected: []string{"team-c", "team-d"},
+		},
+		{
+			name:     "flag takes precedence over environment variable",
+			args:     []string{"--watch-namespaces=team-a"},
+			env:      "team-c",
+			expected: []string{"team-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCH_NAMESPACES", tt.env)
+
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+			cfg.LoadFromEnv()
+
+			assert.Equal(t, tt.expected, cfg.Controller.WatchNamespaces)
+		})
+	}
+}
+
+func TestConcurrencyFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedWorkers int
+		expectedRate    float64
+		expectedBurst   int
+		expectedTTL     time.Duration
+	}{
+		{
+			name:            "defaults",
+			args:            []string{},
+			expectedWorkers: 1,
+			expectedRate:    10,
+			expectedBurst:   20,
+		},
+		{
+			name:            "tuned for many ASecrets",
+			args:            []string{"--max-concurrent-reconciles=8", "--aws-rate-limit=25.5", "--aws-rate-burst=50", "--secret-cache-ttl=30s"},
+			expectedWorkers: 8,
+			expectedRate:    25.5,
+			expectedBurst:   50,
+			expectedTTL:     30 * time.Second,
+		},
+		{
+			name:            "rate limit disabled",
+			args:            []string{"--aws-rate-limit=0"},
+			expectedWorkers: 1,
+			expectedRate:    0,
+			expectedBurst:   20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+
+			awsConfig := cfg.ToAWSConfig()
+			assert.Equal(t, tt.expectedWorkers, cfg.Controller.MaxConcurrentReconciles)
+			assert.Equal(t, tt.expectedRate, awsConfig.RateLimit)
+			assert.Equal(t, tt.expectedBurst, awsConfig.RateBurst)
+			assert.Equal(t, tt.expectedTTL, awsConfig.SecretCacheTTL)
+		})
+	}
+}
+
+func TestVerifyWriteFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedAttempts int
+		expectedInterval time.Duration
+	}{
+		{
+			name:             "defaults",
+			args:             []string{},
+			expectedAttempts: 3,
+			expectedInterval: time.Second,
+		},
+		{
+			name:             "custom backoff",
+			args:             []string{"--aws-verify-write-attempts=5", "--aws-verify-write-interval=250ms"},
+			expectedAttempts: 5,
+			expectedInterval: 250 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+
+			awsConfig := cfg.ToAWSConfig()
+			assert.Equal(t, tt.expectedAttempts, awsConfig.VerifyWriteAttempts)
+			assert.Equal(t, tt.expectedInterval, awsConfig.VerifyWriteInterval)
+		})
+	}
+}
+
+func TestThrottleRetryFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedRetries int
+		expectedDelay   time.Duration
+	}{
+		{
+			name:            "defaults",
+			args:            []string{},
+			expectedRetries: 3,
+			expectedDelay:   200 * time.Millisecond,
+		},
+		{
+			name:            "custom retries",
+			args:            []string{"--aws-throttle-retries=6", "--aws-throttle-retry-base-delay=50ms"},
+			expectedRetries: 6,
+			expectedDelay:   50 * time.Millisecond,
+		},
+		{
+			name:            "disabled",
+			args:            []string{"--aws-throttle-retries=0"},
+			expectedRetries: 0,
+			expectedDelay:   200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+
+			awsConfig := cfg.ToAWSConfig()
+			assert.Equal(t, tt.expectedRetries, awsConfig.ThrottleRetries)
+			assert.Equal(t, tt.expectedDelay, awsConfig.ThrottleRetryDelay)
+		})
+	}
+}
+
+func TestRegionFallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected []string
+	}{
+		{
+			name:     "no fallbacks by default",
+			args:     []string{},
+			expected: nil,
+		},
+		{
+			name:     "flag keeps the order of precedence",
+			args:     []string{"--aws-region-fallbacks=eu-central-1,us-east-1"},
+			expected: []string{"eu-central-1", "us-east-1"},
+		},
+		{
+			name:     "environment variable when flag is not set",
+			args:     []string{},
+			env:      "us-west-2, eu-west-1,,us-west-2",
+			expected: []string{"us-west-2", "eu-west-1"},
+		},
+		{
+			name:     "flag takes precedence over environment variable",
+			args:     []string{"--aws-region-fallbacks=eu-central-1"},
+			env:      "us-west-2",
+			expected: []string{"eu-central-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION_FALLBACKS", tt.env)
+			t.Setenv("AWS_REGION", "eu-west-1")
+
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+			cfg.LoadFromEnv()
+
+			assert.Equal(t, tt.expected, cfg.ToAWSConfig().RegionFallbacks)
+		})
+	}
+}
+
+func TestSkipConnTest(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected bool
+	}{
+		{
+			name:     "connectivity test runs by default",
+			args:     []string{},
+			expected: false,
+		},
+		{
+			name:     "flag skips the test",
+			args:     []string{"--skip-aws-test"},
+			expected: true,
+		},
+		{
+			name:     "environment variable skips the test",
+			args:     []string{},
+			env:      "true",
+			expected: true,
+		},
+		{
+			name:     "invalid environment variable keeps the test",
+			args:     []string{},
+			env:      "maybe",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SKIP_AWS_CONN_TEST", tt.env)
+
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+			cfg.LoadFromEnv()
+
+			assert.Equal(t, tt.expected, cfg.AWS.SkipConnTest)
+			assert.Equal(t, tt.expected, cfg.ToAWSConfig().SkipConnTest)
+		})
+	}
+}
+
+func TestLeaderElectionFlags(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		expectedEnabled   bool
+		expectedID        string
+		expectedNamespace string
+	}{
+		{
+			name:            "defaults",
+			args:            []string{},
+			expectedEnabled: false,
+			expectedID:      "aso.yaso.io",
+		},
+		{
+			name:              "per-deployment lease",
+			args:              []string{"--leader-elect", "--leader-elect-id=yaso-staging.yaso.io", "--leader-elect-namespace=yaso-staging"},
+			expectedEnabled:   true,
+			expectedID:        "yaso-staging.yaso.io",
+			expectedNamespace: "yaso-staging",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			cfg.AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+
+			assert.Equal(t, tt.expectedEnabled, cfg.Leader.Enabled)
+			assert.Equal(t, tt.expectedID, cfg.Leader.ID)
+			assert.Equal(t, tt.expectedNamespace, cfg.Leader.Namespace)
+		})
+	}
+}
+
+func TestSecretPathPrefixFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.ToAWSConfig().SecretPathPrefix)
+
+	require.NoError(t, flags.Parse([]string{"--secret-path-prefix=myorg/prod/"}))
+	assert.Equal(t, "myorg/prod/", cfg.ToAWSConfig().SecretPathPrefix)
+}
+
+func TestMaxSecretBytesFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, 65536, cfg.ToAWSConfig().MaxSecretBytes)
+
+	require.NoError(t, flags.Parse([]string{"--max-secret-bytes=1024"}))
+	assert.Equal(t, 1024, cfg.ToAWSConfig().MaxSecretBytes)
+}
+
+func TestMaxPrefixSecretsFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, 100, cfg.ToAWSConfig().MaxPrefixSecrets)
+
+	require.NoError(t, flags.Parse([]string{"--max-prefix-secrets=500"}))
+	assert.Equal(t, 500, cfg.ToAWSConfig().MaxPrefixSecrets)
+}
+
+func TestMaxAWSClientsFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, 32, cfg.ToAWSConfig().MaxAWSClients)
+
+	require.NoError(t, flags.Parse([]string{"--max-aws-clients=4"}))
+	assert.Equal(t, 4, cfg.ToAWSConfig().MaxAWSClients)
+}
+
+func TestAllowedEndpointURLsFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	cfg.LoadFromEnv()
+	assert.Empty(t, cfg.ToAWSConfig().AllowedEndpointURLs)
+
+	require.NoError(t, flags.Parse([]string{"--allowed-endpoint-urls=http://localstack-a:4566, http://localstack-b:4566,,http://localstack-a:4566"}))
+	cfg.LoadFromEnv()
+	assert.Equal(t, []string{"http://localstack-a:4566", "http://localstack-b:4566"}, cfg.ToAWSConfig().AllowedEndpointURLs)
+}
+
+func TestAWSCABundleFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.ToAWSConfig().CABundle)
+
+	require.NoError(t, flags.Parse([]string{"--aws-ca-bundle=/etc/ssl/proxy-ca.pem"}))
+	assert.Equal(t, "/etc/ssl/proxy-ca.pem", cfg.ToAWSConfig().CABundle)
+}
+
+func TestPprofBindAddressFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.Health.PprofBindAddress, "profiling is off by default")
+
+	require.NoError(t, flags.Parse([]string{"--pprof-bind-address=127.0.0.1:6060"}))
+	assert.Equal(t, "127.0.0.1:6060", cfg.Health.PprofBindAddress)
+}
+
+func TestReconcileModeFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, "event", cfg.Controller.ReconcileMode)
+
+	require.NoError(t, flags.Parse([]string{"--reconcile-mode=poll"}))
+	assert.Equal(t, "poll", cfg.Controller.ReconcileMode)
+}
+
+func TestRefreshJitterFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, 10, cfg.Controller.RefreshJitter)
+
+	require.NoError(t, flags.Parse([]string{"--refresh-jitter=25"}))
+	assert.Equal(t, 25, cfg.Controller.RefreshJitter)
+}
+
+func TestGlobalResyncPeriodFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, time.Duration(0), cfg.Controller.GlobalResyncPeriod)
+
+	require.NoError(t, flags.Parse([]string{"--global-resync-period=12h"}))
+	assert.Equal(t, 12*time.Hour, cfg.Controller.GlobalResyncPeriod)
+}
+
+func TestGracefulShutdownTimeoutFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, 30*time.Second, cfg.Controller.GracefulShutdownTimeout)
+
+	require.NoError(t, flags.Parse([]string{"--graceful-shutdown-timeout=2m"}))
+	assert.Equal(t, 2*time.Minute, cfg.Controller.GracefulShutdownTimeout)
+}
+
+func TestStartupSpreadFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, time.Duration(0), cfg.Controller.StartupSpread)
+
+	require.NoError(t, flags.Parse([]string{"--startup-spread=5m"}))
+	assert.Equal(t, 5*time.Minute, cfg.Controller.StartupSpread)
+}
+
+func TestStartupSweepSpreadFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, time.Duration(0), cfg.Controller.StartupSweepSpread)
+
+	require.NoError(t, flags.Parse([]string{"--startup-sweep-spread=10m"}))
+	assert.Equal(t, 10*time.Minute, cfg.Controller.StartupSweepSpread)
+}
+
+func TestLogFormatFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.LogFormat)
+
+	require.NoError(t, flags.Parse([]string{"--log-format=json", "--debug"}))
+	assert.Equal(t, "json", cfg.LogFormat)
+	assert.True(t, cfg.Debug)
+}
+
+func TestRecordLastChangeFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.False(t, cfg.Controller.RecordLastChange)
+
+	require.NoError(t, flags.Parse([]string{"--record-last-change"}))
+	assert.True(t, cfg.Controller.RecordLastChange)
+}
+
+func TestAWSReadinessIntervalFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Equal(t, time.Minute, cfg.Health.AWSCheckInterval)
+
+	require.NoError(t, flags.Parse([]string{"--aws-readiness-interval=0"}))
+	assert.Equal(t, time.Duration(0), cfg.Health.AWSCheckInterval)
+}
+
+func TestReadOnlyFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.False(t, cfg.Controller.ReadOnly)
+
+	require.NoError(t, flags.Parse([]string{"--read-only"}))
+	assert.True(t, cfg.Controller.ReadOnly)
+}
+
+func TestObjectLabelSelectorFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.Controller.ObjectLabelSelector)
+
+	require.NoError(t, flags.Parse([]string{"--object-label-selector", "tier=prod,team!=legacy"}))
+	assert.Equal(t, "tier=prod,team!=legacy", cfg.Controller.ObjectLabelSelector)
+}
+
+func TestDetectRemoteConflictsFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.False(t, cfg.Controller.DetectRemoteConflicts)
+
+	require.NoError(t, flags.Parse([]string{"--detect-remote-conflicts"}))
+	assert.True(t, cfg.Controller.DetectRemoteConflicts)
+}
+
+func TestEnableGeneratorControllerFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.True(t, cfg.Controller.EnableGeneratorController)
+
+	require.NoError(t, flags.Parse([]string{"--enable-generator-controller=false"}))
+	assert.False(t, cfg.Controller.EnableGeneratorController)
+}
+
+func TestOnceFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.Empty(t, cfg.Controller.Once)
+
+	require.NoError(t, flags.Parse([]string{"--once", "default/app-secrets"}))
+	assert.Equal(t, "default/app-secrets", cfg.Controller.Once)
+}
+
+func TestGCOrphansFlags(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.False(t, cfg.Controller.GCOrphans)
+	assert.False(t, cfg.Controller.GCDelete)
+
+	require.NoError(t, flags.Parse([]string{"--gc-orphans", "--gc-delete"}))
+	assert.True(t, cfg.Controller.GCOrphans)
+	assert.True(t, cfg.Controller.GCDelete)
+}
+
+func TestSkipNamespaceAccessCheckFlag(t *testing.T) {
+	cfg := NewDefaultConfig()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(flags)
+	assert.False(t, cfg.Controller.SkipNamespaceAccessCheck)
+
+	require.NoError(t, flags.Parse([]string{"--skip-namespace-access-check"}))
+	assert.True(t, cfg.Controller.SkipNamespaceAccessCheck)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	bootstrapTokenChars        = "abcdefghijklmnopqrstuvwxyz0123456789"
+	bootstrapTokenIDLength     = 6
+	bootstrapTokenSecretLength = 16
+
+	// BootstrapTokenSecretType is the type of Secrets holding bootstrap tokens
+	BootstrapTokenSecretType = "bootstrap.kubernetes.io/token"
+	// BootstrapTokenSecretPrefix prefixes the name of bootstrap token Secrets, followed by the token id
+	BootstrapTokenSecretPrefix = "bootstrap-token-"
+)
+
+var bootstrapTokenPattern = regexp.MustCompile(`^([a-z0-9]{6})\.([a-z0-9]{16})$`)
+
+// GenerateBootstrapToken generates a random token in the "<token-id>.<token-secret>" bootstrap token format
+func GenerateBootstrapToken() (string, error) {
+	tokenID, err := randomBootstrapTokenString(bootstrapTokenIDLength)
+	if err != nil {
+		return "", err
+	}
+
+	tokenSecret, err := randomBootstrapTokenString(bootstrapTokenSecretLength)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenID + "." + tokenSecret, nil
+}
+
+// ValidateBootstrapToken checks that the token matches [a-z0-9]{6}.[a-z0-9]{16}
+func ValidateBootstrapToken(token string) error {
+	if !bootstrapTokenPattern.MatchString(token) {
+		return fmt.Errorf("token does not match the bootstrap token format %s", bootstrapTokenPattern.String())
+	}
+	return nil
+}
+
+// BootstrapTokenSecretData assembles the data of a bootstrap token Secret for the token with the given usages
+// (e.g. "authentication", "signing")
+func BootstrapTokenSecretData(token string, usages ...string) (map[string][]byte, error) {
+	if err := ValidateBootstrapToken(token); err != nil {
+		return nil, err
+	}
+
+	tokenID, tokenSecret, _ := strings.Cut(token, ".")
+	data := map[string][]byte{
+		"token-id":     []byte(tokenID),
+		"token-secret": []byte(tokenSecret),
+	}
+	for _, usage := range usages {
+		data["usage-bootstrap-"+usage] = []byte("true")
+	}
+
+	return data, nil
+}
+
+// randomBootstrapTokenString generates a random string of lowercase letters and digits
+func randomBootstrapTokenString(length int) (string, error) {
+	result := make([]byte, length)
+
+	for i := range result {
+		randomIndex, err := secureIndex(len(bootstrapTokenChars))
+		if err != nil {
+			return "", err
+		}
+		result[i] = bootstrapTokenChars[randomIndex]
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+var bootstrapTokenRegex = regexp.MustCompile(`^[a-z0-9]{6}\.[a-z0-9]{16}$`)
+
+func TestGenerateBootstrapToken(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 50 {
+		token, err := GenerateBootstrapToken()
+		require.NoError(t, err)
+		assert.Regexp(t, bootstrapTokenRegex, token)
+		assert.NoError(t, ValidateBootstrapToken(token))
+		assert.False(t, seen[token], "token generated twice")
+		seen[token] = true
+	}
+}
+
+func TestGenerateValueBootstrapToken(t *testing.T) {
+	spec := secretsv1alpha1.AGeneratorSpec{
+		Type:             GeneratorTypeBootstrapToken,
+		Length:           32,
+		IncludeUppercase: true,
+	}
+
+	require.NoError(t, ValidateGeneratorSpec(spec))
+	value, err := GenerateValue(spec)
+	require.NoError(t, err)
+	assert.Regexp(t, bootstrapTokenRegex, value)
+}
+
+func TestValidateBootstrapToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid token", token: "abcdef.0123456789abcdef", wantErr: false},
+		{name: "uppercase characters", token: "ABCDEF.0123456789abcdef", wantErr: true},
+		{name: "token id too short", token: "abcde.0123456789abcdef", wantErr: true},
+		{name: "token secret too long", token: "abcdef.0123456789abcdef0", wantErr: true},
+		{name: "missing separator", token: "abcdef0123456789abcdef", wantErr: true},
+		{name: "wrong separator", token: "abcdef:0123456789abcdef", wantErr: true},
+		{name: "empty", token: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBootstrapToken(tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBootstrapTokenSecretData(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		usages   []string
+		expected map[string][]byte
+		wantErr  bool
+	}{
+		{
+			name:  "token without usages",
+			token: "abcdef.0123456789abcdef",
+			expected: map[string][]byte{
+				"token-id":     []byte("abcdef"),
+				"token-secret": []byte("0123456789abcdef"),
+			},
+		},
+		{
+			name:   "token with usages",
+			token:  "07401b.f395accd246ae52d",
+			usages: []string{"authentication", "signing"},
+			expected: map[string][]byte{
+				"token-id":                       []byte("07401b"),
+				"token-secret":                   []byte("f395accd246ae52d"),
+				"usage-bootstrap-authentication": []byte("true"),
+				"usage-bootstrap-signing":        []byte("true"),
+			},
+		},
+		{
+			name:    "invalid token",
+			token:   "not-a-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := BootstrapTokenSecretData(tt.token, tt.usages...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, data)
+			assert.Regexp(t, `^[a-z0-9]{6}$`, string(data["token-id"]))
+			assert.Regexp(t, `^[a-z0-9]{16}$`, string(data["token-secret"]))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// CompressedValuePrefix marks a value stored as base64 encoded gzip. Plain values must not start with it
+	CompressedValuePrefix = "yaso-gzip:"
+
+	// maxDecompressedSize caps decompressed values at the size limit of a Kubernetes Secret
+	maxDecompressedSize = 1 << 20
+)
+
+// CompressValue wraps the value in the compressed envelope, or returns it unchanged when
+// the envelope would not be smaller than the value itself
+func CompressValue(value []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(value); err != nil {
+		return nil, fmt.Errorf("failed to compress value: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress value: %v", err)
+	}
+
+	envelope := CompressedValuePrefix + base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(envelope) >= len(value) {
+		return value, nil
+	}
+	return []byte(envelope), nil
+}
+
+// IsCompressedValue reports whether the value carries the compressed envelope marker
+func IsCompressedValue(value string) bool {
+	return strings.HasPrefix(value, CompressedValuePrefix)
+}
+
+// DecompressValue unwraps a value in the compressed envelope. Values without the marker are returned as-is
+func DecompressValue(value string) ([]byte, error) {
+	if !IsCompressedValue(value) {
+		return []byte(value), nil
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, CompressedValuePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed value: %v", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed value: %v", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed value: %v", err)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, fmt.Errorf("decompressed value exceeds %d bytes", maxDecompressedSize)
+	}
+	return decompressed, nil
+}
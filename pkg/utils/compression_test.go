@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressValueRoundTrip(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		wantCompressed bool
+	}{
+		{name: "large repetitive text", value: strings.Repeat("-----BEGIN CERTIFICATE-----\nMIIB\n", 500), wantCompressed: true},
+		{name: "large json document", value: `{"items":[` + strings.Repeat(`{"name":"a","enabled":true},`, 300) + `{}]}`, wantCompressed: true},
+		{name: "short value that would grow", value: "admin", wantCompressed: false},
+		{name: "empty value", value: "", wantCompressed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressed, err := CompressValue([]byte(tt.value))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCompressed, IsCompressedValue(string(compressed)))
+			if tt.wantCompressed {
+				assert.Less(t, len(compressed), len(tt.value)/5, "expected a substantial size reduction")
+			} else {
+				assert.Equal(t, tt.value, string(compressed))
+			}
+
+			decompressed, err := DecompressValue(string(compressed))
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, string(decompressed))
+		})
+	}
+}
+
+func TestCompressValueIsDeterministic(t *testing.T) {
+	value := []byte(strings.Repeat("password=secret\n", 100))
+
+	first, err := CompressValue(value)
+	require.NoError(t, err)
+	second, err := CompressValue(value)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestDecompressValueErrors(t *testing.T) {
+	var oversized bytes.Buffer
+	writer := gzip.NewWriter(&oversized)
+	_, err := writer.Write(make([]byte, maxDecompressedSize+1))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "invalid base64", value: CompressedValuePrefix + "not base64!!", wantErr: "invalid compressed value"},
+		{name: "not gzip", value: CompressedValuePrefix + base64.StdEncoding.EncodeToString([]byte("plain")), wantErr: "invalid compressed value"},
+		{name: "exceeds the Secret size limit", value: CompressedValuePrefix + base64.StdEncoding.EncodeToString(oversized.Bytes()), wantErr: "decompressed value exceeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecompressValue(tt.value)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// hkdfBlockLength is the number of bytes expanded at once, the most HKDF-SHA256 produces for one info
+const hkdfBlockLength = 255 * sha256.Size
+
+// GenerateDeterministicString derives a string according to the generator specification from the seed.
+// The same seed and info always yield the same string, so the result is only as secret as the seed
+func GenerateDeterministicString(spec secretsv1alpha1.AGeneratorSpec, seed []byte, info string) (string, error) {
+	if len(seed) == 0 {
+		return "", errors.New("deterministic generation requires a non-empty seed")
+	}
+	prk, err := hkdf.Extract(sha256.New, seed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract key from seed: %v", err)
+	}
+	stream := &hkdfStream{prk: prk, info: info}
+	return generateString(spec, stream.index)
+}
+
+// hkdfStream reads an unbounded byte stream from a pseudorandom key, expanding one block per counter value
+type hkdfStream struct {
+	prk     []byte
+	info    string
+	counter int
+	buf     []byte
+}
+
+func (s *hkdfStream) next() (byte, error) {
+	if len(s.buf) == 0 {
+		block, err := hkdf.Expand(sha256.New, s.prk, fmt.Sprintf("%s/%d", s.info, s.counter), hkdfBlockLength)
+		if err != nil {
+			return 0, fmt.Errorf("failed to expand key from seed: %v", err)
+		}
+		s.counter++
+		s.buf = block
+	}
+	b := s.buf[0]
+	s.buf = s.buf[1:]
+	return b, nil
+}
+
+// index returns an index in [0, n) from the stream. Like secureIndex, bytes above the largest multiple
+// of n are rejected rather than reduced modulo n, so the characters of the pool stay equally likely
+func (s *hkdfStream) index(n int) (int, error) {
+	if n <= 0 || n > 256 {
+		return 0, fmt.Errorf("cannot select an index from a pool of %d", n)
+	}
+	limit := 256 - 256%n
+	for {
+		b, err := s.next()
+		if err != nil {
+			return 0, err
+		}
+		if int(b) < limit {
+			return int(b) % n, nil
+		}
+	}
+}
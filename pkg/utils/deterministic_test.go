@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+func deterministicSpec(length int) secretsv1alpha1.AGeneratorSpec {
+	return secretsv1alpha1.AGeneratorSpec{
+		Length:              length,
+		IncludeUppercase:    true,
+		IncludeLowercase:    true,
+		IncludeNumbers:      true,
+		IncludeSpecialChars: true,
+		SpecialChars:        "!@#",
+		Deterministic:       true,
+	}
+}
+
+func TestGenerateDeterministicStringIsReproducible(t *testing.T) {
+	spec := deterministicSpec(32)
+
+	first, err := GenerateDeterministicString(spec, []byte("seed"), "default/app/password")
+	require.NoError(t, err)
+	second, err := GenerateDeterministicString(spec, []byte("seed"), "default/app/password")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Len(t, first, 32)
+}
+
+func TestGenerateDeterministicStringDiffers(t *testing.T) {
+	spec := deterministicSpec(32)
+	reference, err := GenerateDeterministicString(spec, []byte("seed"), "default/app/password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		seed string
+		info string
+	}{
+		{name: "different key", seed: "seed", info: "default/app/apiKey"},
+		{name: "different ASecret", seed: "seed", info: "default/other/password"},
+		{name: "different seed", seed: "other-seed", info: "default/app/password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := GenerateDeterministicString(spec, []byte(tt.seed), tt.info)
+			require.NoError(t, err)
+			assert.NotEqual(t, reference, value)
+		})
+	}
+}
+
+func TestGenerateDeterministicStringUsesPool(t *testing.T) {
+	spec := secretsv1alpha1.AGeneratorSpec{Length: 20000, IncludeNumbers: true, Deterministic: true}
+
+	// Longer than one expanded block, so the stream has to continue with the next counter
+	value, err := GenerateDeterministicString(spec, []byte("seed"), "default/app/pin")
+	require.NoError(t, err)
+	require.Len(t, value, 20000)
+	assert.Empty(t, strings.Trim(value, "0123456789"))
+
+	counts := make([]int, 10)
+	for _, c := range value {
+		counts[c-'0']++
+	}
+	assert.Less(t, chiSquare(counts, len(value)), chiSquareCritical(9))
+}
+
+func TestGenerateDeterministicStringEmptySeed(t *testing.T) {
+	_, err := GenerateDeterministicString(deterministicSpec(16), nil, "default/app/password")
+	assert.EqualError(t, err, "deterministic generation requires a non-empty seed")
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DotenvQuoteIfNeeded quotes only values containing characters that are unsafe for shells
+	DotenvQuoteIfNeeded = "if-needed"
+	// DotenvQuoteAlways quotes every value
+	DotenvQuoteAlways = "always"
+
+	// DotenvNewlinesEscape writes newlines as \n inside quoted values
+	DotenvNewlinesEscape = "escape"
+	// DotenvNewlinesPreserve keeps literal newlines inside quoted values
+	DotenvNewlinesPreserve = "preserve"
+)
+
+var (
+	dotenvKeyPattern       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dotenvSafeValuePattern = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,-]*$`)
+)
+
+// RenderDotenv renders the secret data as a dotenv file, one KEY=value line per key sorted by key
+func RenderDotenv(data map[string][]byte, quoting, newlines string) ([]byte, error) {
+	if quoting == "" {
+		quoting = DotenvQuoteIfNeeded
+	}
+	if newlines == "" {
+		newlines = DotenvNewlinesEscape
+	}
+	if quoting != DotenvQuoteIfNeeded && quoting != DotenvQuoteAlways {
+		return nil, fmt.Errorf("unsupported dotenv quoting %q", quoting)
+	}
+	if newlines != DotenvNewlinesEscape && newlines != DotenvNewlinesPreserve {
+		return nil, fmt.Errorf("unsupported dotenv newline handling %q", newlines)
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if !dotenvKeyPattern.MatchString(k) {
+			return nil, fmt.Errorf("key %q is not a valid dotenv variable name", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(formatDotenvValue(string(data[k]), quoting, newlines))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// formatDotenvValue quotes and escapes a single value
+func formatDotenvValue(value, quoting, newlines string) string {
+	if quoting == DotenvQuoteIfNeeded && dotenvSafeValuePattern.MatchString(value) {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range value {
+		switch c {
+		case '\\', '"', '$', '`':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case '\n':
+			if newlines == DotenvNewlinesEscape {
+				b.WriteString(`\n`)
+			} else {
+				b.WriteRune(c)
+			}
+		case '\r':
+			if newlines == DotenvNewlinesEscape {
+				b.WriteString(`\r`)
+			} else {
+				b.WriteRune(c)
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
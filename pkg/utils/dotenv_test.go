@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderDotenv(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		quoting  string
+		newlines string
+		expected string
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "simple values are not quoted when quoting if needed",
+			data:     map[string][]byte{"USERNAME": []byte("admin"), "DB_HOST": []byte("db.example.com:5432")},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "DB_HOST=db.example.com:5432\nUSERNAME=admin\n",
+		},
+		{
+			name:     "defaults to quoting if needed and escaping newlines",
+			data:     map[string][]byte{"USERNAME": []byte("admin"), "CERT": []byte("line1\nline2")},
+			expected: "CERT=\"line1\\nline2\"\nUSERNAME=admin\n",
+		},
+		{
+			name:     "always quote",
+			data:     map[string][]byte{"USERNAME": []byte("admin")},
+			quoting:  DotenvQuoteAlways,
+			expected: "USERNAME=\"admin\"\n",
+		},
+		{
+			name:     "value with spaces is quoted",
+			data:     map[string][]byte{"GREETING": []byte("hello world")},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "GREETING=\"hello world\"\n",
+		},
+		{
+			name:     "value with quotes is escaped",
+			data:     map[string][]byte{"QUOTED": []byte(`say "hi" it's me`)},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "QUOTED=\"say \\\"hi\\\" it's me\"\n",
+		},
+		{
+			name:     "value with equals sign is quoted",
+			data:     map[string][]byte{"DSN": []byte("user=admin;password=secret")},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "DSN=\"user=admin;password=secret\"\n",
+		},
+		{
+			name:     "shell expansion characters are escaped",
+			data:     map[string][]byte{"PASSWORD": []byte("pa$$`w0rd`\\")},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "PASSWORD=\"pa\\$\\$\\`w0rd\\`\\\\\"\n",
+		},
+		{
+			name:     "newlines escaped",
+			data:     map[string][]byte{"KEY": []byte("a\r\nb")},
+			quoting:  DotenvQuoteIfNeeded,
+			newlines: DotenvNewlinesEscape,
+			expected: "KEY=\"a\\r\\nb\"\n",
+		},
+		{
+			name:     "newlines preserved",
+			data:     map[string][]byte{"KEY": []byte("a\nb")},
+			quoting:  DotenvQuoteIfNeeded,
+			newlines: DotenvNewlinesPreserve,
+			expected: "KEY=\"a\nb\"\n",
+		},
+		{
+			name:     "empty value is left unquoted",
+			data:     map[string][]byte{"EMPTY": []byte("")},
+			quoting:  DotenvQuoteIfNeeded,
+			expected: "EMPTY=\n",
+		},
+		{
+			name:     "empty data renders nothing",
+			data:     map[string][]byte{},
+			expected: "",
+		},
+		{
+			name:    "invalid variable name",
+			data:    map[string][]byte{"tls.crt": []byte("x")},
+			wantErr: true,
+			errMsg:  "not a valid dotenv variable name",
+		},
+		{
+			name:    "unsupported quoting",
+			data:    map[string][]byte{"KEY": []byte("x")},
+			quoting: "never",
+			wantErr: true,
+			errMsg:  "unsupported dotenv quoting",
+		},
+		{
+			name:     "unsupported newline handling",
+			data:     map[string][]byte{"KEY": []byte("x")},
+			newlines: "strip",
+			wantErr:  true,
+			errMsg:   "unsupported dotenv newline handling",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderDotenv(tt.data, tt.quoting, tt.newlines)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, string(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	// EncodingNone stores values as-is
+	EncodingNone = "none"
+	// EncodingBase64 stores values with standard base64
+	EncodingBase64 = "base64"
+	// EncodingBase64URL stores values with URL-safe base64
+	EncodingBase64URL = "base64url"
+)
+
+// DecodeValue decodes a value stored in the given encoding, accepting padded and unpadded input
+func DecodeValue(value string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", EncodingNone:
+		return []byte(value), nil
+	case EncodingBase64:
+		return decodeBase64(value, base64.StdEncoding, base64.RawStdEncoding)
+	case EncodingBase64URL:
+		return decodeBase64(value, base64.URLEncoding, base64.RawURLEncoding)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+// EncodeValue encodes a raw value in the given encoding, always producing padded output
+func EncodeValue(value []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", EncodingNone:
+		return value, nil
+	case EncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(value)), nil
+	case EncodingBase64URL:
+		return []byte(base64.URLEncoding.EncodeToString(value)), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+// decodeBase64 tries the padded encoding first and falls back to the unpadded one
+func decodeBase64(value string, padded, raw *base64.Encoding) ([]byte, error) {
+	decoded, err := padded.DecodeString(value)
+	if err == nil {
+		return decoded, nil
+	}
+	decoded, rawErr := raw.DecodeString(value)
+	if rawErr == nil {
+		return decoded, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		encoding string
+		expected []byte
+		wantErr  bool
+	}{
+		{name: "empty encoding returns value as-is", value: "plain", encoding: "", expected: []byte("plain")},
+		{name: "none returns value as-is", value: "plain", encoding: EncodingNone, expected: []byte("plain")},
+		{name: "padded base64", value: "SGVsbG8gV29ybGQ=", encoding: EncodingBase64, expected: []byte("Hello World")},
+		{name: "unpadded base64", value: "SGVsbG8gV29ybGQ", encoding: EncodingBase64, expected: []byte("Hello World")},
+		{name: "binary base64", value: "/+8A", encoding: EncodingBase64, expected: []byte{0xff, 0xef, 0x00}},
+		{name: "padded base64url", value: "_-8A", encoding: EncodingBase64URL, expected: []byte{0xff, 0xef, 0x00}},
+		{name: "unpadded base64url", value: "SGk", encoding: EncodingBase64URL, expected: []byte("Hi")},
+		{name: "malformed base64", value: "not base64!!", encoding: EncodingBase64, wantErr: true},
+		{name: "base64url alphabet rejected by base64", value: "_-8A", encoding: EncodingBase64, wantErr: true},
+		{name: "base64 alphabet rejected by base64url", value: "/+8A", encoding: EncodingBase64URL, wantErr: true},
+		{name: "unsupported encoding", value: "x", encoding: "hex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeValue(tt.value, tt.encoding)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    []byte
+		encoding string
+		expected string
+		wantErr  bool
+	}{
+		{name: "none returns value as-is", value: []byte("plain"), encoding: EncodingNone, expected: "plain"},
+		{name: "base64", value: []byte{0xff, 0xef, 0x00}, encoding: EncodingBase64, expected: "/+8A"},
+		{name: "base64url", value: []byte{0xff, 0xef, 0x00}, encoding: EncodingBase64URL, expected: "_-8A"},
+		{name: "base64 is padded", value: []byte("Hi"), encoding: EncodingBase64, expected: "SGk="},
+		{name: "unsupported encoding", value: []byte("x"), encoding: "hex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EncodeValue(tt.value, tt.encoding)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, string(result))
+
+				decoded, err := DecodeValue(string(result), tt.encoding)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.value, decoded)
+			}
+		})
+	}
+}
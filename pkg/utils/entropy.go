@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+)
+
+// DefaultEntropyFailureThreshold is the number of consecutive failed test generations before the entropy check fails
+const DefaultEntropyFailureThreshold = 3
+
+// EntropyCheck verifies that the random source used by the generators still produces values.
+// A single failed read is tolerated, the check only fails once FailureThreshold reads in a row failed
+type EntropyCheck struct {
+	// Reader is the random source under test, crypto/rand when nil
+	Reader io.Reader
+	// FailureThreshold is the number of consecutive failures tolerated, DefaultEntropyFailureThreshold when zero
+	FailureThreshold int
+
+	mu       sync.Mutex
+	failures int
+}
+
+// Test runs a single test generation and reports its error, without updating the failure count
+func (c *EntropyCheck) Test() error {
+	reader := c.Reader
+	if reader == nil {
+		reader = rand.Reader
+	}
+	if _, err := rand.Int(reader, big.NewInt(256)); err != nil {
+		return fmt.Errorf("entropy source failed to generate a random number: %w", err)
+	}
+	return nil
+}
+
+// Check runs a test generation and fails once the entropy source failed persistently.
+// It matches healthz.Checker so it can be registered as a readiness check
+func (c *EntropyCheck) Check(_ *http.Request) error {
+	err := c.Test()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.failures = 0
+		return nil
+	}
+
+	c.failures++
+	threshold := c.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultEntropyFailureThreshold
+	}
+	if c.failures < threshold {
+		return nil
+	}
+	return fmt.Errorf("%w (%d consecutive failures)", err, c.failures)
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyReader fails the first failures reads, then reads from crypto/rand
+type flakyReader struct {
+	failures int
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.failures > 0 {
+		f.failures--
+		return 0, errors.New("entropy unavailable")
+	}
+	return rand.Read(p)
+}
+
+func TestEntropyCheckTest(t *testing.T) {
+	assert.NoError(t, (&EntropyCheck{}).Test())
+	assert.EqualError(t, (&EntropyCheck{Reader: &flakyReader{failures: 1}}).Test(),
+		"entropy source failed to generate a random number: entropy unavailable")
+}
+
+func TestEntropyCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		reader    io.Reader
+		threshold int
+		expected  []bool
+	}{
+		{
+			name:     "working source always passes",
+			reader:   &flakyReader{},
+			expected: []bool{true, true, true, true},
+		},
+		{
+			name:     "transient failures are tolerated",
+			reader:   &flakyReader{failures: 2},
+			expected: []bool{true, true, true, true},
+		},
+		{
+			name:     "persistent failures fail the check",
+			reader:   &flakyReader{failures: 4},
+			expected: []bool{true, true, false, false, true},
+		},
+		{
+			name:      "custom threshold",
+			reader:    &flakyReader{failures: 1},
+			threshold: 1,
+			expected:  []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &EntropyCheck{Reader: tt.reader, FailureThreshold: tt.threshold}
+			for i, healthy := range tt.expected {
+				err := check.Check(nil)
+				if healthy {
+					require.NoError(t, err, "check %d", i)
+				} else {
+					require.Error(t, err, "check %d", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxFlattenDepth is the deepest level of nested objects flattened into dotted keys.
+// Objects nested deeper are kept JSON-encoded under the key of their parent
+const MaxFlattenDepth = 10
+
+// FlattenJSON flattens nested objects of a JSON document into dotted keys, so {"config":{"host":"db"}}
+// becomes "config.host". Strings are kept as-is, arrays and other values are JSON-encoded.
+// An empty object is kept as "{}" so that it survives a round trip through UnflattenJSON
+func FlattenJSON(obj map[string]interface{}) (map[string]string, error) {
+	result := make(map[string]string)
+	if err := flattenInto(result, "", obj, 1); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func flattenInto(result map[string]string, prefix string, obj map[string]interface{}, depth int) error {
+	for k, v := range obj {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 && depth < MaxFlattenDepth {
+			if err := flattenInto(result, key, nested, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if str, ok := v.(string); ok {
+			result[key] = str
+			continue
+		}
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode %q: %w", key, err)
+		}
+		result[key] = string(bytes)
+	}
+	return nil
+}
+
+// UnflattenJSON is the inverse of FlattenJSON, nesting dotted keys back into objects.
+// Keys with an empty segment, such as ".dockerconfigjson", are kept at the top level as-is.
+// A key that is both a value and the parent of another key, such as "a" and "a.b", is an error
+// unless the value of "a" is itself an object the other key can be merged into
+func UnflattenJSON(data map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	// Parents sort before their children, so an object value is always set before keys merge into it
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		segments := strings.Split(key, ".")
+		if slices.Contains(segments, "") {
+			segments = []string{key}
+		}
+
+		node := result
+		for _, segment := range segments[:len(segments)-1] {
+			child, exists := node[segment]
+			if !exists {
+				child = make(map[string]interface{})
+				node[segment] = child
+			}
+			childObj, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key %q conflicts with the value of its parent %q", key, segment)
+			}
+			node = childObj
+		}
+
+		leaf := segments[len(segments)-1]
+		if _, exists := node[leaf]; exists {
+			return nil, fmt.Errorf("key %q is defined more than once", key)
+		}
+		node[leaf] = data[key]
+	}
+	return result, nil
+}
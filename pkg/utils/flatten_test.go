@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nestedDocument builds a document with depth levels of objects, the innermost holding "leaf": "value"
+func nestedDocument(depth int) string {
+	return strings.Repeat(`{"l":`, depth-1) + `{"leaf":"value"}` + strings.Repeat(`}`, depth-1)
+}
+
+func TestFlattenJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected map[string]string
+	}{
+		{
+			name:     "flat document",
+			document: `{"username":"admin","port":5432}`,
+			expected: map[string]string{"username": "admin", "port": "5432"},
+		},
+		{
+			name:     "nested objects become dotted keys",
+			document: `{"config":{"host":"db","port":5432,"tls":{"enabled":true}}}`,
+			expected: map[string]string{"config.host": "db", "config.port": "5432", "config.tls.enabled": "true"},
+		},
+		{
+			name:     "arrays are JSON-encoded, objects inside them are not flattened",
+			document: `{"config":{"hosts":["a","b"],"replicas":[{"host":"c"}]}}`,
+			expected: map[string]string{"config.hosts": `["a","b"]`, "config.replicas": `[{"host":"c"}]`},
+		},
+		{
+			name:     "empty object and null are kept",
+			document: `{"config":{},"optional":null}`,
+			expected: map[string]string{"config": "{}", "optional": "null"},
+		},
+		{
+			name:     "objects at the depth limit are flattened",
+			document: nestedDocument(MaxFlattenDepth),
+			expected: map[string]string{strings.Repeat("l.", MaxFlattenDepth-1) + "leaf": "value"},
+		},
+		{
+			name:     "objects past the depth limit stay JSON-encoded",
+			document: nestedDocument(MaxFlattenDepth + 1),
+			expected: map[string]string{strings.TrimSuffix(strings.Repeat("l.", MaxFlattenDepth), "."): `{"leaf":"value"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.document), &obj))
+
+			result, err := FlattenJSON(obj)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestUnflattenJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          map[string]interface{}
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "dotted keys become nested objects",
+			data:     map[string]interface{}{"username": "admin", "config.host": "db", "config.tls.enabled": true},
+			expected: `{"config":{"host":"db","tls":{"enabled":true}},"username":"admin"}`,
+		},
+		{
+			name:     "arrays are kept as values",
+			data:     map[string]interface{}{"config.hosts": []interface{}{"a", "b"}},
+			expected: `{"config":{"hosts":["a","b"]}}`,
+		},
+		{
+			name:     "keys with an empty segment stay at the top level",
+			data:     map[string]interface{}{".dockerconfigjson": "{}", "a..b": "x", "trailing.": "y"},
+			expected: `{".dockerconfigjson":"{}","a..b":"x","trailing.":"y"}`,
+		},
+		{
+			name:     "object value merges with its dotted children",
+			data:     map[string]interface{}{"config": map[string]interface{}{"host": "db"}, "config.port": float64(5432)},
+			expected: `{"config":{"host":"db","port":5432}}`,
+		},
+		{
+			name:          "scalar value conflicts with dotted children",
+			data:          map[string]interface{}{"config": "plain", "config.host": "db"},
+			expectedError: `key "config.host" conflicts with the value of its parent "config"`,
+		},
+		{
+			name:          "dotted key duplicates a key of an object value",
+			data:          map[string]interface{}{"config": map[string]interface{}{"host": "db"}, "config.host": "other"},
+			expectedError: `key "config.host" is defined more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := UnflattenJSON(tt.data)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			encoded, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(encoded))
+		})
+	}
+}
+
+func TestFlattenJSONRoundTrip(t *testing.T) {
+	document := `{"username":"admin","config":{"host":"db","port":5432,"hosts":["a","b"],"empty":{}},"deep":` + nestedDocument(MaxFlattenDepth+2) + `}`
+
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(document), &obj))
+	flat, err := FlattenJSON(obj)
+	require.NoError(t, err)
+
+	// Values are decoded the way prepareAwsSecretString decodes them before nesting
+	decoded := make(map[string]interface{})
+	for k, v := range flat {
+		var value interface{}
+		if json.Unmarshal([]byte(v), &value) == nil {
+			decoded[k] = value
+		} else {
+			decoded[k] = v
+		}
+	}
+
+	nested, err := UnflattenJSON(decoded)
+	require.NoError(t, err)
+	encoded, err := json.Marshal(nested)
+	require.NoError(t, err)
+	assert.JSONEq(t, document, string(encoded))
+}
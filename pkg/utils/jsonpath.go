@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractJSONProperty returns the value at the dot-separated property path of a JSON document.
+// String values are returned as-is, other values are returned JSON-encoded.
+// The boolean result is false when the property does not exist.
+func ExtractJSONProperty(document string, property string) (string, bool, error) {
+	segments := splitPropertyPath(property)
+	if len(segments) == 0 {
+		return "", false, fmt.Errorf("property path %q is empty", property)
+	}
+
+	var current interface{}
+	if err := json.Unmarshal([]byte(document), &current); err != nil {
+		return "", false, fmt.Errorf("failed to parse JSON document: %w", err)
+	}
+
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[segment]
+			if !exists {
+				return "", false, nil
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false, nil
+			}
+			current = node[index]
+		default:
+			return "", false, nil
+		}
+	}
+
+	if str, ok := current.(string); ok {
+		return str, true, nil
+	}
+
+	bytes, err := json.Marshal(current)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to encode property %q: %w", property, err)
+	}
+	return string(bytes), true, nil
+}
+
+// splitPropertyPath splits ".a.b.c" or "a.b.c" into its segments
+func splitPropertyPath(property string) []string {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(property), ".")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, ".")
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractJSONProperty(t *testing.T) {
+	document := `{
+		"rds": {"password": "s3cret", "port": 5432, "replicas": [{"host": "r1"}, {"host": "r2"}]},
+		"apiKey": "top-level",
+		"enabled": true,
+		"nothing": null
+	}`
+
+	tests := []struct {
+		name         string
+		document     string
+		property     string
+		expected     string
+		expectExists bool
+		wantErr      bool
+	}{
+		{name: "nested string with leading dot", document: document, property: ".rds.password", expected: "s3cret", expectExists: true},
+		{name: "nested string without leading dot", document: document, property: "rds.password", expected: "s3cret", expectExists: true},
+		{name: "top-level string", document: document, property: ".apiKey", expected: "top-level", expectExists: true},
+		{name: "number is JSON encoded", document: document, property: ".rds.port", expected: "5432", expectExists: true},
+		{name: "boolean is JSON encoded", document: document, property: ".enabled", expected: "true", expectExists: true},
+		{name: "null is JSON encoded", document: document, property: ".nothing", expected: "null", expectExists: true},
+		{name: "object is JSON encoded", document: document, property: ".rds.replicas.0", expected: `{"host":"r1"}`, expectExists: true},
+		{name: "array index", document: document, property: ".rds.replicas.1.host", expected: "r2", expectExists: true},
+		{name: "missing top-level property", document: document, property: ".missing", expectExists: false},
+		{name: "missing nested property", document: document, property: ".rds.username", expectExists: false},
+		{name: "path through a scalar", document: document, property: ".apiKey.value", expectExists: false},
+		{name: "array index out of range", document: document, property: ".rds.replicas.5", expectExists: false},
+		{name: "non-numeric array index", document: document, property: ".rds.replicas.first", expectExists: false},
+		{name: "empty property", document: document, property: ".", wantErr: true},
+		{name: "invalid document", document: `{"rds": `, property: ".rds", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, exists, err := ExtractJSONProperty(tt.document, tt.property)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectExists, exists)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
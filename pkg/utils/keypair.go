@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+const (
+	// KeyPairAlgorithmRSA generates RSA key pairs
+	KeyPairAlgorithmRSA = "rsa"
+	// KeyPairAlgorithmECDSA generates ECDSA key pairs
+	KeyPairAlgorithmECDSA = "ecdsa"
+	// KeyPairFormatPEM encodes the private key as PKCS#8 and the public key as PKIX PEM blocks
+	KeyPairFormatPEM = "pem"
+
+	// PublicKeySuffix is appended to the ASecret key to name the Secret key holding the public key
+	PublicKeySuffix = ".pub"
+)
+
+var ecdsaCurves = map[int]elliptic.Curve{
+	256: elliptic.P256(),
+	384: elliptic.P384(),
+	521: elliptic.P521(),
+}
+
+// keyPairDefaults returns the key pair spec with its unset fields defaulted
+func keyPairDefaults(spec *secretsv1alpha1.KeyPairSpec) secretsv1alpha1.KeyPairSpec {
+	var resolved secretsv1alpha1.KeyPairSpec
+	if spec != nil {
+		resolved = *spec
+	}
+	if resolved.Algorithm == "" {
+		resolved.Algorithm = KeyPairAlgorithmRSA
+	}
+	if resolved.Format == "" {
+		resolved.Format = KeyPairFormatPEM
+	}
+	if resolved.Bits == 0 {
+		resolved.Bits = 2048
+		if resolved.Algorithm == KeyPairAlgorithmECDSA {
+			resolved.Bits = 256
+		}
+	}
+	return resolved
+}
+
+// validateKeyPairSpec checks that the algorithm, key size and format are supported
+func validateKeyPairSpec(spec *secretsv1alpha1.KeyPairSpec) error {
+	resolved := keyPairDefaults(spec)
+
+	switch resolved.Algorithm {
+	case KeyPairAlgorithmRSA:
+		if resolved.Bits != 2048 && resolved.Bits != 3072 && resolved.Bits != 4096 {
+			return fmt.Errorf("unsupported rsa key size %d, must be 2048, 3072 or 4096", resolved.Bits)
+		}
+	case KeyPairAlgorithmECDSA:
+		if _, ok := ecdsaCurves[resolved.Bits]; !ok {
+			return fmt.Errorf("unsupported ecdsa key size %d, must be 256, 384 or 521", resolved.Bits)
+		}
+	default:
+		return fmt.Errorf("unsupported key pair algorithm %q", resolved.Algorithm)
+	}
+
+	if resolved.Format != KeyPairFormatPEM {
+		return fmt.Errorf("unsupported key pair format %q", resolved.Format)
+	}
+
+	return nil
+}
+
+// GenerateKeyPair generates a key pair and returns the PEM encoded private and public keys
+func GenerateKeyPair(spec *secretsv1alpha1.KeyPairSpec) (string, string, error) {
+	if err := validateKeyPairSpec(spec); err != nil {
+		return "", "", err
+	}
+	resolved := keyPairDefaults(spec)
+
+	var privateKey crypto.Signer
+	var err error
+	switch resolved.Algorithm {
+	case KeyPairAlgorithmRSA:
+		privateKey, err = rsa.GenerateKey(rand.Reader, resolved.Bits)
+	case KeyPairAlgorithmECDSA:
+		privateKey, err = ecdsa.GenerateKey(ecdsaCurves[resolved.Bits], rand.Reader)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate %s key: %v", resolved.Algorithm, err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode public key: %v", err)
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return string(privatePEM), string(publicPEM), nil
+}
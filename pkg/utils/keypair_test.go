@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// decodePEM returns the DER bytes of the single PEM block of the given type
+func decodePEM(t *testing.T, data, blockType string) []byte {
+	t.Helper()
+	block, rest := pem.Decode([]byte(data))
+	require.NotNil(t, block, "no PEM block found")
+	assert.Equal(t, blockType, block.Type)
+	assert.Empty(t, rest)
+	return block.Bytes
+}
+
+func TestGenerateKeyPair(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *secretsv1alpha1.KeyPairSpec
+		wantBits int
+	}{
+		{name: "defaults to rsa 2048", spec: nil, wantBits: 2048},
+		{name: "rsa 3072", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmRSA, Bits: 3072}, wantBits: 3072},
+		{name: "ecdsa defaults to 256", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmECDSA}, wantBits: 256},
+		{name: "ecdsa 384", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmECDSA, Bits: 384, Format: KeyPairFormatPEM}, wantBits: 384},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privatePEM, publicPEM, err := GenerateKeyPair(tt.spec)
+			require.NoError(t, err)
+
+			privateKey, err := x509.ParsePKCS8PrivateKey(decodePEM(t, privatePEM, "PRIVATE KEY"))
+			require.NoError(t, err)
+			publicKey, err := x509.ParsePKIXPublicKey(decodePEM(t, publicPEM, "PUBLIC KEY"))
+			require.NoError(t, err)
+
+			switch key := privateKey.(type) {
+			case *rsa.PrivateKey:
+				assert.Equal(t, tt.wantBits, key.N.BitLen())
+				assert.True(t, key.PublicKey.Equal(publicKey))
+			case *ecdsa.PrivateKey:
+				assert.Equal(t, tt.wantBits, key.Curve.Params().BitSize)
+				assert.True(t, key.PublicKey.Equal(publicKey))
+			default:
+				t.Fatalf("unexpected private key type %T", privateKey)
+			}
+		})
+	}
+}
+
+func TestValidateKeyPairSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *secretsv1alpha1.KeyPairSpec
+		wantErr string
+	}{
+		{name: "nil spec uses defaults", spec: nil},
+		{name: "rsa 4096", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmRSA, Bits: 4096}},
+		{name: "ecdsa 521", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmECDSA, Bits: 521}},
+		{name: "unsupported algorithm", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: "dsa"}, wantErr: `unsupported key pair algorithm "dsa"`},
+		{name: "weak rsa key", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmRSA, Bits: 1024}, wantErr: "unsupported rsa key size 1024, must be 2048, 3072 or 4096"},
+		{name: "unknown ecdsa curve", spec: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmECDSA, Bits: 2048}, wantErr: "unsupported ecdsa key size 2048, must be 256, 384 or 521"},
+		{name: "unsupported format", spec: &secretsv1alpha1.KeyPairSpec{Format: "der"}, wantErr: `unsupported key pair format "der"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGeneratorSpec(secretsv1alpha1.AGeneratorSpec{Type: GeneratorTypeKeyPair, KeyPair: tt.spec})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenerateValueKeyPairReturnsPrivateKey(t *testing.T) {
+	value, err := GenerateValue(secretsv1alpha1.AGeneratorSpec{
+		Type:    GeneratorTypeKeyPair,
+		KeyPair: &secretsv1alpha1.KeyPairSpec{Algorithm: KeyPairAlgorithmECDSA},
+	})
+	require.NoError(t, err)
+
+	_, err = x509.ParsePKCS8PrivateKey(decodePEM(t, value, "PRIVATE KEY"))
+	assert.NoError(t, err)
+}
+
+func TestGenerateKeyPairRejectsUnsupportedAlgorithm(t *testing.T) {
+	_, _, err := GenerateKeyPair(&secretsv1alpha1.KeyPairSpec{Algorithm: "ed448"})
+	assert.EqualError(t, err, `unsupported key pair algorithm "ed448"`)
+}
@@ -0,0 +1,25 @@
+package utils
+
+import "strings"
+
+const (
+	// LineEndingsNone keeps values as-is
+	LineEndingsNone = "none"
+	// LineEndingsLF converts CRLF line endings to LF
+	LineEndingsLF = "lf"
+	// LineEndingsCRLF converts LF line endings to CRLF
+	LineEndingsCRLF = "crlf"
+)
+
+// NormalizeLineEndings rewrites every line ending of the value in the given mode.
+// Unknown modes, like none, return the value unchanged
+func NormalizeLineEndings(value string, mode string) string {
+	switch mode {
+	case LineEndingsLF:
+		return strings.ReplaceAll(value, "\r\n", "\n")
+	case LineEndingsCRLF:
+		return strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\n", "\r\n")
+	default:
+		return value
+	}
+}
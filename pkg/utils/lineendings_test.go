@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLineEndings(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		mode     string
+		expected string
+	}{
+		{name: "CRLF to LF", value: "line1\r\nline2\r\n", mode: LineEndingsLF, expected: "line1\nline2\n"},
+		{name: "mixed to LF", value: "line1\r\nline2\nline3", mode: LineEndingsLF, expected: "line1\nline2\nline3"},
+		{name: "lone CR kept by LF", value: "line1\rline2", mode: LineEndingsLF, expected: "line1\rline2"},
+		{name: "LF to CRLF", value: "line1\nline2\n", mode: LineEndingsCRLF, expected: "line1\r\nline2\r\n"},
+		{name: "CRLF stays CRLF", value: "line1\r\nline2\n", mode: LineEndingsCRLF, expected: "line1\r\nline2\r\n"},
+		{name: "none keeps value", value: "line1\r\nline2\n", mode: LineEndingsNone, expected: "line1\r\nline2\n"},
+		{name: "empty mode keeps value", value: "line1\r\n", mode: "", expected: "line1\r\n"},
+		{name: "no line endings", value: "single", mode: LineEndingsLF, expected: "single"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeLineEndings(tt.value, tt.mode))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// secureIndex returns a uniformly distributed index in [0, n) read from crypto/rand.
+// rand.Int rejects out-of-range samples instead of reducing them modulo n, so pools whose size
+// does not divide 256 are not biased. Every generator selecting characters goes through it
+func secureIndex(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("cannot select an index from an empty pool")
+	}
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random number: %v", err)
+	}
+	return int(index.Int64()), nil
+}
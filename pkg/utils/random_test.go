@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+// chiSquareCritical approximates the chi-square value a uniform source exceeds with probability
+// about one in a million (Wilson-Hilferty), so the uniformity tests practically never flake
+func chiSquareCritical(degreesOfFreedom int) float64 {
+	k := float64(degreesOfFreedom)
+	const z = 4.75
+	return k * math.Pow(1-2/(9*k)+z*math.Sqrt(2/(9*k)), 3)
+}
+
+// chiSquare compares the observed counts of each value with a uniform distribution
+func chiSquare(counts []int, samples int) float64 {
+	expected := float64(samples) / float64(len(counts))
+	var statistic float64
+	for _, count := range counts {
+		diff := float64(count) - expected
+		statistic += diff * diff / expected
+	}
+	return statistic
+}
+
+func TestSecureIndexIsUniform(t *testing.T) {
+	// None of these pool sizes divides 256, where naive modulo reduction would be biased.
+	// 62 is the alphanumeric pool, 36 the bootstrap token pool
+	for _, n := range []int{3, 36, 62, 100, 200} {
+		samples := n * 2000
+		counts := make([]int, n)
+		for range samples {
+			index, err := secureIndex(n)
+			require.NoError(t, err)
+			require.True(t, index >= 0 && index < n, "index %d out of range [0, %d)", index, n)
+			counts[index]++
+		}
+
+		statistic := chiSquare(counts, samples)
+		assert.Less(t, statistic, chiSquareCritical(n-1), "pool of %d is not uniform", n)
+	}
+}
+
+func TestSecureIndexEmptyPool(t *testing.T) {
+	_, err := secureIndex(0)
+	assert.EqualError(t, err, "cannot select an index from an empty pool")
+}
+
+func TestGenerateRandomStringIsUniform(t *testing.T) {
+	spec := secretsv1alpha1.AGeneratorSpec{
+		Length:           100,
+		IncludeUppercase: true,
+		IncludeLowercase: true,
+		IncludeNumbers:   true,
+	}
+	pool := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+	counts := make([]int, len(pool))
+	samples := 0
+	for range 1000 {
+		value, err := GenerateRandomString(spec)
+		require.NoError(t, err)
+		for _, c := range value {
+			counts[strings.IndexRune(pool, c)]++
+			samples++
+		}
+	}
+
+	assert.Less(t, chiSquare(counts, samples), chiSquareCritical(len(pool)-1))
+}
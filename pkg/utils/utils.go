@@ -1,16 +1,42 @@
 package utils
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/big"
 
 	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
 )
 
-// validateGeneratorSpec validates that the generator specification is valid
+const (
+	// GeneratorTypePassword generates a random string from the configured character sets
+	GeneratorTypePassword = "password"
+	// GeneratorTypeBootstrapToken generates a Kubernetes bootstrap token
+	GeneratorTypeBootstrapToken = "bootstrap-token"
+	// GeneratorTypeKeyPair generates a private key and its public key
+	GeneratorTypeKeyPair = "key-pair"
+)
+
+// ValidateGeneratorSpec validates that the generator specification is valid
 func ValidateGeneratorSpec(spec secretsv1alpha1.AGeneratorSpec) error {
+	switch spec.Type {
+	case "", GeneratorTypePassword:
+	case GeneratorTypeBootstrapToken, GeneratorTypeKeyPair:
+		if spec.Deterministic {
+			return fmt.Errorf("deterministic generation is not supported for generator type %q", spec.Type)
+		}
+		if spec.Type == GeneratorTypeKeyPair {
+			return validateKeyPairSpec(spec.KeyPair)
+		}
+		// The bootstrap token format is fixed, character options do not apply
+		return nil
+	default:
+		return fmt.Errorf("unsupported generator type %q", spec.Type)
+	}
+
+	if spec.Deterministic && spec.SeedSecretRef == nil {
+		return errors.New("seedSecretRef is required for a deterministic generator")
+	}
+
 	// Ensure at least one character type is enabled
 	if !spec.IncludeUppercase && !spec.IncludeLowercase && !spec.IncludeNumbers && !spec.IncludeSpecialChars {
 		return errors.New("at least one character type (uppercase, lowercase, numbers, or special chars) must be enabled")
@@ -24,8 +50,29 @@ func ValidateGeneratorSpec(spec secretsv1alpha1.AGeneratorSpec) error {
 	return nil
 }
 
-// generateRandomString generates a random string according to the generator specification
+// GenerateValue generates a value in the format selected by the generator type
+func GenerateValue(spec secretsv1alpha1.AGeneratorSpec) (string, error) {
+	switch spec.Type {
+	case "", GeneratorTypePassword:
+		return GenerateRandomString(spec)
+	case GeneratorTypeBootstrapToken:
+		return GenerateBootstrapToken()
+	case GeneratorTypeKeyPair:
+		privateKey, _, err := GenerateKeyPair(spec.KeyPair)
+		return privateKey, err
+	default:
+		return "", fmt.Errorf("unsupported generator type %q", spec.Type)
+	}
+}
+
+// GenerateRandomString generates a random string according to the generator specification
 func GenerateRandomString(spec secretsv1alpha1.AGeneratorSpec) (string, error) {
+	return generateString(spec, secureIndex)
+}
+
+// generateString builds a string of the spec length from the characters the spec enables,
+// choosing each character with index
+func generateString(spec secretsv1alpha1.AGeneratorSpec, index func(n int) (int, error)) (string, error) {
 	var chars string
 
 	if spec.IncludeUppercase {
@@ -49,14 +96,13 @@ func GenerateRandomString(spec secretsv1alpha1.AGeneratorSpec) (string, error) {
 	}
 
 	result := make([]byte, spec.Length)
-	maxVal := big.NewInt(int64(len(chars)))
 
 	for i := 0; i < spec.Length; i++ {
-		randomIndex, err := rand.Int(rand.Reader, maxVal)
+		charIndex, err := index(len(chars))
 		if err != nil {
-			return "", fmt.Errorf("failed to generate random number: %v", err)
+			return "", err
 		}
-		result[i] = chars[randomIndex.Int64()]
+		result[i] = chars[charIndex]
 	}
 
 	return string(result), nil
@@ -104,6 +104,23 @@ func TestValidateGeneratorSpec(t *testing.T) {
 			wantErr: true,
 			errMsg:  "length must be greater than 0",
 		},
+		{
+			name: "bootstrap-token ignores character options",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Type: "bootstrap-token",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid spec - unsupported type",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Type:             "uuid",
+				Length:           16,
+				IncludeUppercase: true,
+			},
+			wantErr: true,
+			errMsg:  "unsupported generator type",
+		},
 		{
 			name: "invalid spec - negative length",
 			spec: secretsv1alpha1.AGeneratorSpec{
@@ -116,6 +133,36 @@ func TestValidateGeneratorSpec(t *testing.T) {
 			wantErr: true,
 			errMsg:  "length must be greater than 0",
 		},
+		{
+			name: "deterministic spec with a seed",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length:           16,
+				IncludeLowercase: true,
+				Deterministic:    true,
+				SeedSecretRef:    &secretsv1alpha1.SecretKeyReference{Name: "seed", Namespace: "default", Key: "seed"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid spec - deterministic without a seed",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Length:           16,
+				IncludeLowercase: true,
+				Deterministic:    true,
+			},
+			wantErr: true,
+			errMsg:  "seedSecretRef is required for a deterministic generator",
+		},
+		{
+			name: "invalid spec - deterministic bootstrap token",
+			spec: secretsv1alpha1.AGeneratorSpec{
+				Type:          GeneratorTypeBootstrapToken,
+				Deterministic: true,
+				SeedSecretRef: &secretsv1alpha1.SecretKeyReference{Name: "seed", Namespace: "default", Key: "seed"},
+			},
+			wantErr: true,
+			errMsg:  `deterministic generation is not supported for generator type "bootstrap-token"`,
+		},
 		{
 			name: "edge case - length 1",
 			spec: secretsv1alpha1.AGeneratorSpec{
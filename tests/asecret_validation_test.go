@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/yaso/yet-another-secrets-operator/api/v1alpha1"
+)
+
+var _ = Describe("ASecret CEL validation", func() {
+	var created int
+
+	newASecret := func(spec secretsv1alpha1.ASecretSpec) *secretsv1alpha1.ASecret {
+		created++
+		spec.TargetSecretName = "validated-secret"
+		if spec.AwsSecretPath == "" && len(spec.BinaryKeyMap) == 0 {
+			spec.AwsSecretPath = "/validated"
+		}
+		return &secretsv1alpha1.ASecret{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("validated-%d", created), Namespace: "default"},
+			Spec:       spec,
+		}
+	}
+	enabled := true
+
+	DescribeTable("rejects contradictory specs at create",
+		func(spec secretsv1alpha1.ASecretSpec, message string) {
+			err := k8sClient.Create(ctx, newASecret(spec))
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("binary secret with several data keys", secretsv1alpha1.ASecretSpec{
+			ValueType: "binary",
+			Data: map[string]secretsv1alpha1.DataSource{
+				"tls.crt": {Value: "Y2VydA=="},
+				"tls.key": {Value: "a2V5"},
+			},
+		}, "a binary secret holds at most one data key"),
+		Entry("rawKey without valueType raw", secretsv1alpha1.ASecretSpec{
+			RawKey: "url",
+		}, "rawKey requires valueType raw"),
+		Entry("binaryKeyMap without valueType binary", secretsv1alpha1.ASecretSpec{
+			BinaryKeyMap: map[string]string{"tls.crt": "/validated/crt"},
+		}, "binaryKeyMap requires valueType binary"),
+		Entry("flattenNested without valueType json", secretsv1alpha1.ASecretSpec{
+			ValueType:     "kv",
+			FlattenNested: &enabled,
+		}, "flattenNested requires valueType json"),
+		Entry("import-only key with a hardcoded value", secretsv1alpha1.ASecretSpec{
+			Data: map[string]secretsv1alpha1.DataSource{
+				"apiKey": {Value: "hardcoded", OnlyImportRemote: &enabled},
+			},
+		}, "a key with onlyImportRemote cannot set value, generatorRef, generatorRefs, configMapRef or secretRef"),
+		Entry("import-only key with a generatorRef", secretsv1alpha1.ASecretSpec{
+			Data: map[string]secretsv1alpha1.DataSource{
+				"apiKey": {GeneratorRef: &secretsv1alpha1.GeneratorReference{Name: "gen"}, OnlyImportRemote: &enabled},
+			},
+		}, "a key with onlyImportRemote cannot set value, generatorRef, generatorRefs, configMapRef or secretRef"),
+		Entry("import-only key with a secretRef", secretsv1alpha1.ASecretSpec{
+			Data: map[string]secretsv1alpha1.DataSource{
+				"apiKey": {SecretRef: &secretsv1alpha1.LocalSecretKeyReference{Name: "app-token", Key: "token"}, OnlyImportRemote: &enabled},
+			},
+		}, "a key with onlyImportRemote cannot set value, generatorRef, generatorRefs, configMapRef or secretRef"),
+	)
+
+	DescribeTable("accepts consistent specs",
+		func(spec secretsv1alpha1.ASecretSpec) {
+			Expect(k8sClient.Create(ctx, newASecret(spec))).To(Succeed())
+		},
+		Entry("binary secret with one key", secretsv1alpha1.ASecretSpec{
+			ValueType: "binary",
+			Data:      map[string]secretsv1alpha1.DataSource{"tls.crt": {Value: "Y2VydA=="}},
+		}),
+		Entry("binary bundle through binaryKeyMap", secretsv1alpha1.ASecretSpec{
+			ValueType:    "binary",
+			BinaryKeyMap: map[string]string{"tls.crt": "/validated/crt", "tls.key": "/validated/key"},
+			Data: map[string]secretsv1alpha1.DataSource{
+				"tls.crt": {OnlyImportRemote: &enabled},
+				"tls.key": {OnlyImportRemote: &enabled},
+			},
+		}),
+		Entry("raw secret with its key", secretsv1alpha1.ASecretSpec{
+			ValueType: "raw",
+			RawKey:    "url",
+		}),
+		Entry("json secret flattening nested objects", secretsv1alpha1.ASecretSpec{
+			ValueType:     "json",
+			FlattenNested: &enabled,
+		}),
+		Entry("kv secret with import-only and hardcoded keys", secretsv1alpha1.ASecretSpec{
+			Data: map[string]secretsv1alpha1.DataSource{
+				"apiKey":   {OnlyImportRemote: &enabled},
+				"username": {Value: "admin"},
+			},
+		}),
+	)
+})
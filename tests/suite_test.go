@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -41,18 +42,22 @@ var _ = BeforeSuite(func() {
 
 	ctx, cancel = context.WithCancel(context.TODO())
 
+	// The BinaryAssetsDirectory is only required if you want to run the tests directly
+	// without call the makefile target test. If not informed it will look for the
+	// default path defined in controller-runtime which is /usr/local/kubebuilder/.
+	// Note that you must have the required binaries setup under the bin directory to perform
+	// the tests directly. When we run make test it will be setup and used automatically.
+	binaryAssetsDirectory := filepath.Join("..", "bin", "k8s",
+		fmt.Sprintf("1.31.0-%s-%s", runtime.GOOS, runtime.GOARCH))
+	if !envtestAssetsAvailable(binaryAssetsDirectory) {
+		Skip("envtest binaries not found, run the suite through make test-integration")
+	}
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: true,
-
-		// The BinaryAssetsDirectory is only required if you want to run the tests directly
-		// without call the makefile target test. If not informed it will look for the
-		// default path defined in controller-runtime which is /usr/local/kubebuilder/.
-		// Note that you must have the required binaries setup under the bin directory to perform
-		// the tests directly. When we run make test it will be setup and used automatically.
-		BinaryAssetsDirectory: filepath.Join("..", "bin", "k8s",
-			fmt.Sprintf("1.31.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
+		BinaryAssetsDirectory: binaryAssetsDirectory,
 	}
 
 	var err error
@@ -73,7 +78,24 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	cancel()
+	if testEnv == nil {
+		return
+	}
 	By("tearing down the test environment")
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
+
+// envtestAssetsAvailable reports whether a kube-apiserver binary can be found, through
+// KUBEBUILDER_ASSETS, the bin directory of the repository or the controller-runtime default
+func envtestAssetsAvailable(binaryAssetsDirectory string) bool {
+	for _, dir := range []string{os.Getenv("KUBEBUILDER_ASSETS"), binaryAssetsDirectory, "/usr/local/kubebuilder/bin"} {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "kube-apiserver")); err == nil {
+			return true
+		}
+	}
+	return false
+}